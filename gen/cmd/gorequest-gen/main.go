@@ -0,0 +1,56 @@
+package main
+
+/**
+ * Generates a typed client from an OpenAPI 3 document:
+ *
+ *   gorequest-gen -spec api.yaml -package api -out client.go
+ */
+
+import (
+	"flag"
+	"fmt"
+	gen "github.com/demianlessa/gorequest/gen"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+
+	spec := flag.String("spec", "", "OpenAPI 3 document, in YAML or JSON")
+	pkg := flag.String("package", "", "package of the generated file")
+	out := flag.String("out", "", "generated file (standard output if empty)")
+
+	flag.Parse()
+
+	if *spec == "" || *pkg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*spec, *pkg, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "gorequest-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(spec string, pkg string, out string) error {
+
+	document, err := ioutil.ReadFile(spec)
+
+	if err != nil {
+		return err
+	}
+
+	source, err := gen.Generate(document, gen.Options{Package: pkg})
+
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+
+	return ioutil.WriteFile(out, source, 0644)
+}
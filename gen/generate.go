@@ -0,0 +1,515 @@
+package gorequest
+
+/**
+ * Generates typed clients from OpenAPI 3 documents. Every operation becomes
+ * a method of a Client with its path parameters as arguments, its query and
+ * header parameters in a Params struct and its JSON bodies as Go types;
+ * requests are sent through a model.Client, so they get its auth, retry and
+ * other settings:
+ *
+ *   source, err := gen.Generate(spec, gen.Options{Package: "users"})
+ *
+ * or, from a go:generate directive:
+ *
+ *   //go:generate go run github.com/demianlessa/gorequest/gen/cmd/gorequest-gen -spec users.yaml -package users -out client.go
+ *
+ * Only local $refs (#/components/...) are resolved. oneOf and anyOf schemas
+ * are left to the caller as json.RawMessage.
+ */
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	oas "github.com/demianlessa/gorequest/internal/oas"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Options struct {
+	// Name of the package of the generated file.
+	Package string
+}
+
+/**
+ * Generates the Go source of a client for the OpenAPI 3 document spec, in
+ * YAML or JSON. The source is gofmt-formatted and imports this module.
+ */
+func Generate(spec []byte, options Options) ([]byte, error) {
+
+	if options.Package == "" {
+		return nil, errors.New("Package name is required")
+	}
+
+	doc, err := oas.Parse(spec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		doc:      doc,
+		imports:  map[string]bool{},
+		reserved: map[string]bool{"APIError": true, "Client": true, "NewClient": true},
+	}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		id := identifier(name)
+		if g.reserved[id] {
+			return nil, fmt.Errorf("Schema %q clashes with another generated name", name)
+		}
+		g.reserved[id] = true
+	}
+
+	for _, name := range names {
+		g.declareComponent(identifier(name), doc.Components.Schemas[name])
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := doc.Paths[path]
+		for _, entry := range item.Operations() {
+			g.operation(path, entry.Method, item, entry.Operation)
+		}
+	}
+
+	return g.source(options.Package)
+}
+
+type generator struct {
+	doc      *oas.Document
+	imports  map[string]bool
+	methods  bytes.Buffer
+	reserved map[string]bool
+	types    bytes.Buffer
+	// Whether the runtime helpers are used
+	formatsValues bool
+	sendsJSON     bool
+}
+
+/**
+ * Reserves an identifier, suffixed with a number if taken already.
+ */
+func (g *generator) reserve(name string) string {
+	id := name
+	for i := 2; g.reserved[id]; i++ {
+		id = name + strconv.Itoa(i)
+	}
+	g.reserved[id] = true
+	return id
+}
+
+/**
+ * Generates the method of one operation.
+ */
+func (g *generator) operation(path string, method string, item *oas.PathItem, op *oas.Operation) {
+
+	name := op.OperationID
+	if name == "" {
+		name = defaultName(method, path)
+	}
+	name = g.reserve(identifier(name))
+
+	// Operation parameters override those of the path item
+	var parameters []*oas.Parameter
+	seen := map[string]bool{}
+	for _, list := range [][]*oas.Parameter{op.Parameters, item.Parameters} {
+		for _, p := range list {
+			p = g.doc.Parameter(p)
+			if p == nil || seen[p.In+" "+p.Name] {
+				continue
+			}
+			seen[p.In+" "+p.Name] = true
+			parameters = append(parameters, p)
+		}
+	}
+
+	var signature []string
+	var body bytes.Buffer
+
+	signature = append(signature, "ctx context.Context")
+
+	// Path parameters, in the order of the template
+	pathParameters := map[string]*oas.Parameter{}
+	for _, p := range parameters {
+		if p.In == "path" {
+			pathParameters[p.Name] = p
+		}
+	}
+
+	var segments []string
+	rest := path
+
+	for {
+		open := strings.Index(rest, "{")
+		end := strings.Index(rest, "}")
+		if open < 0 || end < open {
+			break
+		}
+		if open > 0 {
+			segments = append(segments, strconv.Quote(rest[:open]))
+		}
+		parameterName := rest[open+1 : end]
+		argumentName := argument(parameterName)
+		argumentType := "string"
+		if p := pathParameters[parameterName]; p != nil {
+			argumentType = g.goType(p.Schema, name+identifier(parameterName))
+		}
+		signature = append(signature, argumentName+" "+argumentType)
+		segments = append(segments, "url.PathEscape(formatValue("+argumentName+"))")
+		g.formatsValues = true
+		rest = rest[end+1:]
+	}
+
+	if rest != "" || len(segments) == 0 {
+		segments = append(segments, strconv.Quote(rest))
+	}
+
+	// Result: the JSON content of the first success response
+	var resultSchema *oas.Schema
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if resp := g.doc.Response(op.Responses[code]); resp != nil {
+			if media := jsonContent(resp.Content); media != nil {
+				resultSchema = media.Schema
+				break
+			}
+		}
+	}
+
+	results, failure := "error", "err"
+
+	if resultSchema != nil {
+		resultType := g.goType(resultSchema, name+"Response")
+		fmt.Fprintf(&body, "\tvar result %s\n", resultType)
+		if g.isStruct(resultSchema) {
+			results, failure = "(*"+resultType+", error)", "nil, err"
+		} else {
+			results, failure = "("+resultType+", error)", "result, err"
+		}
+	}
+
+	fmt.Fprintf(&body, "\tpath := %s\n", strings.Join(segments, " + "))
+	body.WriteString("\tquery := url.Values{}\n")
+	body.WriteString("\theaders := map[string]string{}\n")
+
+	// Query and header parameters
+	var fields []*oas.Parameter
+	for _, p := range parameters {
+		if p.In == "query" || p.In == "header" {
+			fields = append(fields, p)
+		}
+	}
+
+	if len(fields) > 0 {
+
+		paramsType := g.reserve(name + "Params")
+		signature = append(signature, "params *"+paramsType)
+		g.formatsValues = true
+
+		var decl bytes.Buffer
+
+		fmt.Fprintf(&decl, "// %s holds the query and header parameters of %s.\ntype %s struct {\n", paramsType, name, paramsType)
+		body.WriteString("\tif params != nil {\n")
+
+		names := map[string]bool{}
+
+		for _, p := range fields {
+
+			field := identifier(p.Name)
+			for names[field] {
+				field += "_"
+			}
+			names[field] = true
+			fieldType := g.goType(p.Schema, paramsType+field)
+			set := "query.Add(" + strconv.Quote(p.Name) + ", formatValue(%s))"
+			if p.In == "header" {
+				set = "headers[" + strconv.Quote(p.Name) + "] = formatValue(%s)"
+			}
+
+			comment(&decl, "\t", p.Description)
+
+			switch {
+			case strings.HasPrefix(fieldType, "[]") && fieldType != "[]byte":
+				fmt.Fprintf(&body, "\t\tfor _, value := range params.%s {\n\t\t\t%s\n\t\t}\n", field, fmt.Sprintf(set, "value"))
+			case p.Required:
+				fmt.Fprintf(&body, "\t\t%s\n", fmt.Sprintf(set, "params."+field))
+			case g.isNillable(p.Schema):
+				fmt.Fprintf(&body, "\t\tif params.%s != nil {\n\t\t\t%s\n\t\t}\n", field, fmt.Sprintf(set, "params."+field))
+			default:
+				fieldType = "*" + fieldType
+				fmt.Fprintf(&body, "\t\tif params.%s != nil {\n\t\t\t%s\n\t\t}\n", field, fmt.Sprintf(set, "*params."+field))
+			}
+
+			fmt.Fprintf(&decl, "\t%s %s\n", field, fieldType)
+		}
+
+		body.WriteString("\t}\n")
+		decl.WriteString("}\n\n")
+		g.types.Write(decl.Bytes())
+	}
+
+	// Request body: JSON bodies are typed, others passed as they are
+	payload := "nil"
+
+	if rb := g.doc.RequestBody(op.RequestBody); rb != nil && len(rb.Content) > 0 {
+
+		payload = "payload"
+
+		if media := jsonContent(rb.Content); media != nil {
+
+			bodyType := g.goType(media.Schema, name+"Request")
+			g.sendsJSON = true
+
+			if g.isStruct(media.Schema) {
+				bodyType = "*" + bodyType
+			}
+
+			signature = append(signature, "body "+bodyType)
+
+			if g.isStruct(media.Schema) || g.isNillable(media.Schema) {
+				body.WriteString("\tvar payload model.RequestBody\n\tif body != nil {\n")
+				body.WriteString("\t\tvar err error\n\t\tif payload, err = newJSONBody(body); err != nil {\n\t\t\treturn %[1]s\n\t\t}\n\t}\n")
+			} else {
+				body.WriteString("\tpayload, err := newJSONBody(body)\n\tif err != nil {\n\t\treturn %[1]s\n\t}\n")
+			}
+
+		} else {
+			signature = append(signature, "payload model.RequestBody")
+		}
+	}
+
+	send := fmt.Sprintf("c.send(ctx, %s, path, query, headers, %s, %%s)", strconv.Quote(method), payload)
+
+	switch {
+	case resultSchema == nil:
+		fmt.Fprintf(&body, "\treturn %s\n", fmt.Sprintf(send, "nil"))
+	case g.isStruct(resultSchema):
+		fmt.Fprintf(&body, "\tif err := %s; err != nil {\n\t\treturn nil, err\n\t}\n\treturn &result, nil\n", fmt.Sprintf(send, "&result"))
+	default:
+		fmt.Fprintf(&body, "\tif err := %s; err != nil {\n\t\treturn result, err\n\t}\n\treturn result, nil\n", fmt.Sprintf(send, "&result"))
+	}
+
+	description := op.Summary
+	if description == "" {
+		description = op.Description
+	}
+	if description != "" {
+		comment(&g.methods, "", description)
+		g.methods.WriteString("//\n")
+	}
+	fmt.Fprintf(&g.methods, "// %s %s\n", method, path)
+	fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) %s {\n", name, strings.Join(signature, ", "), results)
+	g.methods.WriteString(strings.Replace(body.String(), "%[1]s", failure, -1))
+	g.methods.WriteString("}\n\n")
+}
+
+/**
+ * Name of an operation without operationId, e.g. GetUsersByID for
+ * GET /users/{id}.
+ */
+func defaultName(method string, path string) string {
+	name := strings.ToLower(method)
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name += " by " + segment[1:len(segment)-1]
+		} else {
+			name += " " + segment
+		}
+	}
+	return name
+}
+
+/**
+ * The JSON media type of content, if any.
+ */
+func jsonContent(content map[string]*oas.MediaType) *oas.MediaType {
+
+	types := make([]string, 0, len(content))
+	for name := range content {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+
+	for _, name := range types {
+		essence := strings.TrimSpace(strings.SplitN(name, ";", 2)[0])
+		if essence == "application/json" || strings.HasSuffix(essence, "+json") {
+			return content[name]
+		}
+	}
+
+	return nil
+}
+
+/**
+ * Assembles the generated file.
+ */
+func (g *generator) source(pkg string) ([]byte, error) {
+
+	var out bytes.Buffer
+
+	title := g.doc.Info.Title
+	if title == "" {
+		title = "an OpenAPI document"
+	}
+
+	baseURL := ""
+	if len(g.doc.Servers) > 0 {
+		baseURL = g.doc.Servers[0].URL
+	}
+
+	imports := []string{
+		`"context"`,
+		`"encoding/json"`,
+		`"fmt"`,
+		`gorequest "github.com/demianlessa/gorequest"`,
+		`model "github.com/demianlessa/gorequest/model"`,
+		`"net/url"`,
+		`"strings"`,
+	}
+
+	if g.sendsJSON {
+		imports = append(imports, `"bytes"`)
+	}
+
+	if g.formatsValues || g.imports["time"] {
+		imports = append(imports, `"time"`)
+	}
+
+	sort.Strings(imports)
+
+	fmt.Fprintf(&out, "// Code generated by gorequest-gen from %s. DO NOT EDIT.\n\n", strings.Replace(title, "\n", " ", -1))
+	fmt.Fprintf(&out, "package %s\n\nimport (\n\t%s\n)\n\n", pkg, strings.Join(imports, "\n\t"))
+	fmt.Fprintf(&out, runtime, title, strconv.Quote(baseURL))
+
+	if g.formatsValues {
+		out.WriteString(formatValueRuntime)
+	}
+
+	if g.sendsJSON {
+		out.WriteString(jsonBodyRuntime)
+	}
+
+	out.Write(g.types.Bytes())
+	out.Write(g.methods.Bytes())
+
+	formatted, err := format.Source(out.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("Cannot format generated source: %w", err)
+	}
+
+	return formatted, nil
+}
+
+const runtime = `// Client sends the operations of %s.
+type Client struct {
+	baseURL string
+	client  model.Client
+}
+
+// NewClient returns a Client sending its requests through client (the
+// default client if nil), which carries auth, retries and the other
+// settings, to baseURL. An empty baseURL stands for the first server of the
+// document, %s.
+func NewClient(client model.Client, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = %[2]s
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+// APIError is returned for responses with a status other than 2xx.
+type APIError struct {
+	Body       []byte
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unexpected status %%d", e.StatusCode)
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, query url.Values, headers map[string]string, body model.RequestBody, result interface{}) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	builder := gorequest.NewRequestBuilder().WithContext(ctx).WithMethod(method).WithUrl(target)
+	if c.client != nil {
+		builder.WithClient(c.client)
+	}
+	for name, value := range headers {
+		builder.WithHeader(name, value)
+	}
+	if body != nil {
+		builder.WithBody(body)
+	}
+	resp, err := builder.Build().Send()
+	if err != nil {
+		return err
+	}
+	if status := resp.Response().StatusCode; status < 200 || status > 299 {
+		return &APIError{Body: resp.Body(), StatusCode: status}
+	}
+	if result != nil && len(resp.Body()) > 0 {
+		if err := json.Unmarshal(resp.Body(), result); err != nil {
+			return fmt.Errorf("cannot decode response: %%w", err)
+		}
+	}
+	return nil
+}
+
+`
+
+const formatValueRuntime = `func formatValue(value interface{}) string {
+	if t, ok := value.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+`
+
+const jsonBodyRuntime = `type jsonBody struct {
+	data []byte
+}
+
+func newJSONBody(value interface{}) (model.RequestBody, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode request body: %w", err)
+	}
+	return &jsonBody{data: data}, nil
+}
+
+func (b *jsonBody) ContentType() string {
+	return "application/json"
+}
+
+func (b *jsonBody) RawData() *bytes.Buffer {
+	return bytes.NewBuffer(b.data)
+}
+
+`
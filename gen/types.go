@@ -0,0 +1,324 @@
+package gorequest
+
+import (
+	"bytes"
+	"fmt"
+	oas "github.com/demianlessa/gorequest/internal/oas"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/****************************************************
+ * Go types of schemas
+ ****************************************************/
+
+/**
+ * Returns the Go type of sc. Inline objects are declared as named types,
+ * under name if it is free.
+ */
+func (g *generator) goType(sc *oas.Schema, name string) string {
+
+	if sc == nil {
+		return "json.RawMessage"
+	}
+
+	if sc.Ref != "" {
+		return identifier(oas.RefName(sc.Ref, "schemas"))
+	}
+
+	if len(sc.OneOf) > 0 || len(sc.AnyOf) > 0 {
+		return "json.RawMessage"
+	}
+
+	if len(sc.AllOf) == 1 {
+		return g.goType(sc.AllOf[0], name)
+	}
+
+	if g.isStruct(sc) {
+		name = g.reserve(name)
+		g.declareStruct(name, sc)
+		return name
+	}
+
+	switch sc.Type {
+	case "array":
+		return "[]" + g.goType(sc.Items, name+"Item")
+	case "boolean":
+		return "bool"
+	case "integer":
+		if sc.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if sc.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "object":
+		if values := additionalProperties(sc); values != nil {
+			return "map[string]" + g.goType(values, name+"Value")
+		}
+		return "map[string]interface{}"
+	case "string":
+		switch sc.Format {
+		case "byte":
+			return "[]byte"
+		case "date-time":
+			g.imports["time"] = true
+			return "time.Time"
+		}
+		return "string"
+	}
+
+	return "json.RawMessage"
+}
+
+/**
+ * Reports whether sc is generated as a struct.
+ */
+func (g *generator) isStruct(sc *oas.Schema) bool {
+	sc = g.doc.Schema(sc)
+	if sc == nil {
+		return false
+	}
+	if len(sc.AllOf) == 1 {
+		return g.isStruct(sc.AllOf[0])
+	}
+	return len(sc.Properties) > 0 || len(sc.AllOf) > 1
+}
+
+/**
+ * Reports whether the Go type of sc has nil as a value: slices, maps and raw
+ * JSON.
+ */
+func (g *generator) isNillable(sc *oas.Schema) bool {
+	sc = g.doc.Schema(sc)
+	if sc == nil {
+		return true
+	}
+	if len(sc.AllOf) == 1 {
+		return g.isNillable(sc.AllOf[0])
+	}
+	if len(sc.OneOf) > 0 || len(sc.AnyOf) > 0 {
+		return true
+	}
+	if g.isStruct(sc) {
+		return false
+	}
+	switch sc.Type {
+	case "array", "object":
+		return true
+	case "boolean", "integer", "number":
+		return false
+	case "string":
+		return sc.Format == "byte"
+	}
+	return true
+}
+
+/**
+ * The schema of additionalProperties, when it is one rather than a boolean.
+ */
+func additionalProperties(sc *oas.Schema) *oas.Schema {
+	if sc.AdditionalProperties == nil {
+		return nil
+	}
+	return sc.AdditionalProperties.Schema
+}
+
+/**
+ * Declares an object schema as a struct. The members of allOf that are
+ * $refs are embedded, the properties of the others are merged.
+ */
+func (g *generator) declareStruct(name string, sc *oas.Schema) {
+
+	var embedded []string
+	properties := map[string]*oas.Schema{}
+	required := map[string]bool{}
+
+	parts := append([]*oas.Schema{sc}, sc.AllOf...)
+
+	for i, part := range parts {
+		if i > 0 && part.Ref != "" {
+			embedded = append(embedded, identifier(oas.RefName(part.Ref, "schemas")))
+			continue
+		}
+		part = g.doc.Schema(part)
+		for property, value := range part.Properties {
+			properties[property] = value
+		}
+		for _, property := range part.Required {
+			required[property] = true
+		}
+	}
+
+	names := make([]string, 0, len(properties))
+	for property := range properties {
+		names = append(names, property)
+	}
+	sort.Strings(names)
+
+	var decl bytes.Buffer
+
+	comment(&decl, "", sc.Description)
+	fmt.Fprintf(&decl, "type %s struct {\n", name)
+
+	for _, embed := range embedded {
+		fmt.Fprintf(&decl, "\t%s\n", embed)
+	}
+
+	fields := map[string]bool{}
+
+	for _, property := range names {
+
+		value := properties[property]
+		field := identifier(property)
+
+		for fields[field] {
+			field += "_"
+		}
+		fields[field] = true
+
+		fieldType := g.goType(value, name+field)
+		tag := property
+
+		if !required[property] {
+			tag += ",omitempty"
+		}
+
+		if (!required[property] || g.doc.Schema(value) != nil && g.doc.Schema(value).Nullable) && !g.isNillable(value) {
+			fieldType = "*" + fieldType
+		}
+
+		if value.Ref == "" {
+			comment(&decl, "\t", value.Description)
+		}
+		fmt.Fprintf(&decl, "\t%s %s `json:%s`\n", field, fieldType, strconv.Quote(tag))
+	}
+
+	decl.WriteString("}\n\n")
+
+	g.types.Write(decl.Bytes())
+}
+
+/**
+ * Declares a component schema under its own name.
+ */
+func (g *generator) declareComponent(name string, sc *oas.Schema) {
+
+	if g.isStruct(sc) && sc.Ref == "" && len(sc.AllOf) != 1 {
+		g.declareStruct(name, sc)
+		return
+	}
+
+	if sc.Type == "string" && len(sc.Enum) > 0 && sc.Format == "" {
+		comment(&g.types, "", sc.Description)
+		fmt.Fprintf(&g.types, "type %s string\n\nconst (\n", name)
+		for _, value := range sc.Enum {
+			if text, ok := value.(string); ok {
+				fmt.Fprintf(&g.types, "\t%s %s = %s\n", g.reserve(name+identifier(text)), name, strconv.Quote(text))
+			}
+		}
+		g.types.WriteString(")\n\n")
+		return
+	}
+
+	underlying := g.goType(sc, name+"Item")
+
+	comment(&g.types, "", sc.Description)
+	fmt.Fprintf(&g.types, "type %s %s\n\n", name, underlying)
+}
+
+/****************************************************
+ * Identifiers
+ ****************************************************/
+
+var initialisms = map[string]bool{
+	"API": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "TLS": true, "UID": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+/**
+ * Turns a name of the document (snake_case, kebab-case, camelCase...) into
+ * an exported Go identifier.
+ */
+func identifier(name string) string {
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var id strings.Builder
+
+	for _, word := range words {
+		if initialisms[strings.ToUpper(word)] {
+			id.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		id.WriteString(string(runes))
+	}
+
+	if id.Len() == 0 {
+		return "Value"
+	}
+
+	if unicode.IsDigit([]rune(id.String())[0]) {
+		return "N" + id.String()
+	}
+
+	return id.String()
+}
+
+/**
+ * Names that generated arguments cannot take: keywords, imported packages
+ * and the locals of generated methods.
+ */
+var reservedArguments = map[string]bool{
+	"body": true, "break": true, "bytes": true, "c": true, "case": true, "chan": true, "const": true,
+	"context": true, "continue": true, "ctx": true, "default": true, "defer": true, "else": true,
+	"err": true, "fallthrough": true, "fmt": true, "for": true, "func": true, "go": true, "goto": true,
+	"gorequest": true, "headers": true, "if": true, "import": true, "interface": true, "json": true,
+	"map": true, "model": true, "package": true, "params": true, "path": true, "payload": true,
+	"query": true, "range": true, "result": true, "return": true, "select": true, "strings": true,
+	"struct": true, "switch": true, "time": true, "type": true, "url": true, "var": true,
+}
+
+/**
+ * Turns a parameter name into an unexported Go identifier.
+ */
+func argument(name string) string {
+
+	id := identifier(name)
+
+	if initialisms[id] {
+		id = strings.ToLower(id)
+	} else {
+		runes := []rune(id)
+		runes[0] = unicode.ToLower(runes[0])
+		id = string(runes)
+	}
+
+	if reservedArguments[id] {
+		id += "Param"
+	}
+
+	return id
+}
+
+/**
+ * Writes text as a // comment, one line per line of text.
+ */
+func comment(out *bytes.Buffer, indent string, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(out, "%s// %s\n", indent, strings.TrimRight(line, " \t"))
+	}
+}
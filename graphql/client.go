@@ -0,0 +1,197 @@
+package gorequest
+
+/**
+ * GraphQL over HTTP on top of a model.Doer, so queries go through the auth,
+ * retry and observability settings of a Client:
+ *
+ *   gql := graphql.NewClient(gorequest.NewDoer(client), "https://api.example.com/graphql")
+ *   var result struct{ User struct{ Name string } }
+ *   err := gql.Query(ctx, `query($id: ID!) { user(id: $id) { name } }`, map[string]interface{}{"id": 1}, &result)
+ */
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"strings"
+)
+
+/**
+ * One entry of the errors member of a GraphQL response.
+ */
+type Error struct {
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+}
+
+type Location struct {
+	Column int `json:"column"`
+	Line   int `json:"line"`
+}
+
+/**
+ * Returned by Query when the response lists errors. The data of the
+ * response, if any, is decoded all the same: GraphQL responses can be
+ * partial.
+ */
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "GraphQL errors: " + strings.Join(messages, "; ")
+}
+
+/**
+ * Returned by Query when the response is not a GraphQL response, e.g. an
+ * error page of a proxy.
+ */
+type StatusError struct {
+	Body       []byte
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GraphQL endpoint answered %d without a GraphQL response", e.StatusCode)
+}
+
+/**
+ * Sends GraphQL operations to an endpoint.
+ */
+type Client struct {
+	doer     model.Doer
+	endpoint string
+	// Sends automatic persisted queries: the SHA-256 hash of the query
+	// first, the query itself only when the server does not know it yet.
+	PersistedQueries bool
+}
+
+func NewClient(doer model.Doer, endpoint string) *Client {
+	return &Client{
+		doer:     doer,
+		endpoint: endpoint,
+	}
+}
+
+/**
+ * Sends query (a query or a mutation) with variables and decodes the data
+ * member of the response into result, which may be nil. Transport failures
+ * and HTTP failures are returned as errors, GraphQL errors as Errors.
+ */
+func (c *Client) Query(ctx context.Context, query string, variables map[string]interface{}, result interface{}) error {
+
+	payload := request{Query: query, Variables: variables}
+
+	if c.PersistedQueries {
+		hash := sha256.Sum256([]byte(query))
+		payload.Extensions = &extensions{PersistedQuery: persistedQuery{Version: 1, Sha256Hash: hex.EncodeToString(hash[:])}}
+		payload.Query = ""
+	}
+
+	resp, err := c.send(ctx, payload)
+
+	if err == nil && c.PersistedQueries && resp.persistedQueryNotFound() {
+		payload.Query = query
+		resp, err = c.send(ctx, payload)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Data) > 0 && string(resp.Data) != "null" && result != nil {
+		if err := json.Unmarshal(resp.Data, result); err != nil {
+			return fmt.Errorf("Cannot decode GraphQL data: %w", err)
+		}
+	}
+
+	if len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+
+	return nil
+}
+
+type request struct {
+	Extensions *extensions            `json:"extensions,omitempty"`
+	Query      string                 `json:"query,omitempty"`
+	Variables  map[string]interface{} `json:"variables,omitempty"`
+}
+
+type extensions struct {
+	PersistedQuery persistedQuery `json:"persistedQuery"`
+}
+
+type persistedQuery struct {
+	Sha256Hash string `json:"sha256Hash"`
+	Version    int    `json:"version"`
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors Errors          `json:"errors"`
+}
+
+/**
+ * Reports whether the server asks for the query of a persisted query, in
+ * the way Apollo servers do.
+ */
+func (r *response) persistedQueryNotFound() bool {
+	for _, err := range r.Errors {
+		if err.Message == "PersistedQueryNotFound" || err.Extensions["code"] == "PERSISTED_QUERY_NOT_FOUND" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) send(ctx context.Context, payload request) (*response, error) {
+
+	raw, err := json.Marshal(payload)
+
+	if err != nil {
+		return nil, fmt.Errorf("Cannot encode GraphQL request: %w", err)
+	}
+
+	answer, err := c.doer.WithContext(ctx).Send(http.MethodPost, c.endpoint, &body{data: raw})
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response{}
+
+	if err := json.Unmarshal(answer.Body(), resp); err != nil || (resp.Data == nil && resp.Errors == nil) {
+		failure := &StatusError{Body: answer.Body()}
+		if answer.Response() != nil {
+			failure.StatusCode = answer.Response().StatusCode
+		}
+		return nil, failure
+	}
+
+	return resp, nil
+}
+
+/**
+ * model.RequestBody of GraphQL requests.
+ */
+type body struct {
+	data []byte
+}
+
+func (b *body) ContentType() string {
+	return "application/json"
+}
+
+func (b *body) RawData() *bytes.Buffer {
+	return bytes.NewBuffer(b.data)
+}
@@ -0,0 +1,226 @@
+package gorequest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+type principalKey struct{}
+
+/**
+ * Returns a copy of ctx carrying the principal (user, service account,
+ * tenant) requests built with it are sent on behalf of, for the audit log.
+ */
+func ContextWithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func principalOf(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
+
+/**
+ * Turns exchanges into audit records for a model.AuditSink.
+ */
+type auditor struct {
+	settings model.Audit
+}
+
+func newAuditor(settings model.Audit) *auditor {
+	if settings.Sink == nil {
+		panic(errors.New("Audit sink is required"))
+	}
+	return &auditor{
+		settings: settings,
+	}
+}
+
+func (a *auditor) record(req *http.Request, resp *response, err error, start time.Time, duration time.Duration) {
+
+	// transport errors quote the full URL, query string included
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	u := *req.URL
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+
+	record := model.AuditRecord{
+		Attempt:           attemptOf(req),
+		Duration:          duration,
+		Err:               err,
+		Method:            req.Method,
+		Principal:         principalOf(req.Context()),
+		RequestBodySHA256: requestBodyHash(req),
+		Time:              start,
+		URL:               u.String(),
+	}
+
+	if resp != nil {
+		record.StatusCode = resp.response.StatusCode
+		record.ResponseBodySHA256 = bodyHash(resp.body)
+	}
+
+	if err := a.settings.Sink.Write(record); err != nil && a.settings.OnError != nil {
+		a.settings.OnError(err)
+	}
+}
+
+func (a *auditor) close() {
+	if closer, ok := a.settings.Sink.(io.Closer); ok {
+		if err := closer.Close(); err != nil && a.settings.OnError != nil {
+			a.settings.OnError(err)
+		}
+	}
+}
+
+/**
+ * Hashes the body of req, reopened with GetBody, as it is read rather than
+ * loading it whole; empty without a body to reopen.
+ */
+func requestBodyHash(req *http.Request) string {
+
+	if req.GetBody == nil {
+		return ""
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return ""
+	}
+
+	defer body.Close()
+
+	hasher := sha256.New()
+
+	if n, err := io.Copy(hasher, body); err != nil || n == 0 {
+		return ""
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func bodyHash(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+/****************************************************
+ * model.AuditSink implementations
+ ****************************************************/
+
+type auditEntry struct {
+	Time               time.Time `json:"time"`
+	Principal          string    `json:"principal,omitempty"`
+	Method             string    `json:"method"`
+	URL                string    `json:"url"`
+	StatusCode         int       `json:"status,omitempty"`
+	Error              string    `json:"error,omitempty"`
+	DurationMillis     float64   `json:"durationMs"`
+	Attempt            int       `json:"attempt"`
+	RequestBodySHA256  string    `json:"requestBodySha256,omitempty"`
+	ResponseBodySHA256 string    `json:"responseBodySha256,omitempty"`
+}
+
+/**
+ * Writes records as JSON lines, one Write call per record.
+ */
+type writerAuditSink struct {
+	file   *os.File
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+/**
+ * Creates a sink writing one JSON object per line to writer. Since every
+ * record is a single Write call, a *syslog.Writer turns each record into
+ * one syslog message.
+ */
+func NewWriterAuditSink(writer io.Writer) model.AuditSink {
+	if writer == nil {
+		panic(errors.New("Writer is required"))
+	}
+	return &writerAuditSink{
+		writer: writer,
+	}
+}
+
+/**
+ * Creates a sink appending JSON lines to the file at path, which is created
+ * with mode 0600 if needed. The file is closed with the Client.
+ */
+func NewFileAuditSink(path string) model.AuditSink {
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return &writerAuditSink{
+		file:   file,
+		writer: file,
+	}
+}
+
+func (s *writerAuditSink) Write(record model.AuditRecord) error {
+
+	entry := auditEntry{
+		Attempt:            record.Attempt,
+		DurationMillis:     float64(record.Duration) / float64(time.Millisecond),
+		Method:             record.Method,
+		Principal:          record.Principal,
+		RequestBodySHA256:  record.RequestBodySHA256,
+		ResponseBodySHA256: record.ResponseBodySHA256,
+		StatusCode:         record.StatusCode,
+		Time:               record.Time.UTC(),
+		URL:                record.URL,
+	}
+
+	if record.Err != nil {
+		entry.Error = record.Err.Error()
+	}
+
+	line, err := json.Marshal(entry)
+
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, err = s.writer.Write(append(line, '\n'))
+
+	return err
+}
+
+/**
+ * Closes the file opened by NewFileAuditSink; writers supplied by the
+ * caller are left open.
+ */
+func (s *writerAuditSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
@@ -0,0 +1,74 @@
+package gorequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAudit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	var out bytes.Buffer
+
+	c := NewClientBuilder().WithAudit(model.Audit{Sink: NewWriterAuditSink(&out)}).Build()
+
+	ctx := ContextWithPrincipal(context.Background(), "alice")
+
+	NewRequestBuilder().
+		WithUrl(ts.URL + "/orders?api_key=secret").
+		WithMethod("POST").
+		WithBasicAuth("user", "password").
+		WithBody(newJsonBody(`{"id":1}`)).
+		WithContext(ctx).
+		WithClient(c).
+		Build().
+		Do()
+
+	var entry map[string]interface{}
+
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &entry), "Should write a JSON line")
+	assert.Equal(t, "alice", entry["principal"], "Should record the principal")
+	assert.Equal(t, "POST", entry["method"], "Should record the method")
+	assert.Equal(t, ts.URL+"/orders", entry["url"], "Should strip the query string")
+	assert.Equal(t, 200.0, entry["status"], "Should record the status")
+	assert.Equal(t, bodyHash([]byte(`{"id":1}`)), entry["requestBodySha256"], "Should hash the request body")
+	assert.Equal(t, bodyHash([]byte("OK")), entry["responseBodySha256"], "Should hash the response body")
+	assert.NotContains(t, out.String(), "secret", "Should not record secrets")
+	assert.NotContains(t, out.String(), "password", "Should not record credentials")
+}
+
+func TestFileAuditSink(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for i := 0; i < 2; i++ {
+		c := NewClientBuilder().WithAudit(model.Audit{Sink: NewFileAuditSink(path)}).Build()
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+		assert.Nil(t, c.Close(context.Background()), "Should close the client")
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	assert.Nil(t, err, "Should read the audit file")
+	assert.Equal(t, 2, strings.Count(string(data), "\n"), "Should append a line per exchange")
+}
@@ -0,0 +1,250 @@
+package gorequest
+
+import (
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+/**
+ * Consecutive failures after which an endpoint leaves the rotation, and for
+ * how long it stays out.
+ */
+var unhealthyThreshold int = 3
+var unhealthyCooldown time.Duration = 10 * time.Second
+
+type balancedEndpoint struct {
+	current        int
+	down           bool
+	failures       int
+	outstanding    int
+	unhealthyUntil time.Time
+	url            *url.URL
+	weight         int
+}
+
+/**
+ * Distributes requests across endpoints according to a strategy, tracking
+ * the health of each endpoint from the outcome of the requests it served and
+ * from active health checks, if any. When every endpoint is unhealthy, all
+ * of them are eligible again.
+ */
+type balancer struct {
+	clock     model.Clock
+	endpoints []*balancedEndpoint
+	latency   *latencyTracker
+	mutex     sync.Mutex
+	next      int
+	strategy  model.BalancingStrategy
+}
+
+func newBalancer(strategy model.BalancingStrategy, endpoints []model.Endpoint) *balancer {
+
+	if len(endpoints) == 0 {
+		panic(errors.New("At least one endpoint is required"))
+	}
+
+	b := &balancer{
+		clock:    systemClock{},
+		strategy: strategy,
+	}
+
+	b.update(endpoints)
+
+	return b
+}
+
+/**
+ * Replaces the endpoint set. Endpoints already known keep their health and
+ * in-flight counters.
+ */
+func (b *balancer) update(endpoints []model.Endpoint) {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	known := make(map[string]*balancedEndpoint, len(b.endpoints))
+	for _, endpoint := range b.endpoints {
+		known[endpoint.url.String()] = endpoint
+	}
+
+	updated := make([]*balancedEndpoint, 0, len(endpoints))
+
+	for _, endpoint := range endpoints {
+		weight := endpoint.Weight
+		if weight < 1 {
+			weight = 1
+		}
+		u := parseEndpoint(endpoint.URL)
+		if existing, ok := known[u.String()]; ok {
+			existing.weight = weight
+			updated = append(updated, existing)
+			continue
+		}
+		updated = append(updated, &balancedEndpoint{
+			url:    u,
+			weight: weight,
+		})
+	}
+
+	b.endpoints = updated
+}
+
+func (b *balancer) do(req *http.Request, exchange func(*http.Request) (*response, error)) (*response, error) {
+
+	if req.URL.IsAbs() {
+		return exchange(req)
+	}
+
+	endpoint := b.acquire()
+
+	attempt := retarget(req, resolveEndpoint(endpoint.url, req.URL))
+
+	resp, err := exchange(attempt)
+
+	b.release(endpoint, outcomeOf(attempt, resp, err))
+
+	return resp, err
+}
+
+func (b *balancer) acquire() *balancedEndpoint {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	candidates := b.healthy(b.clock.Now())
+
+	var chosen *balancedEndpoint
+
+	switch b.strategy {
+	case model.BalanceLeastOutstanding:
+		for i := range candidates {
+			// start at the round-robin position so ties are spread evenly
+			candidate := candidates[(b.next+i)%len(candidates)]
+			if chosen == nil || candidate.outstanding < chosen.outstanding {
+				chosen = candidate
+			}
+		}
+		b.next++
+	case model.BalanceWeighted:
+		// smooth weighted round-robin, as implemented by nginx
+		total := 0
+		for _, candidate := range candidates {
+			candidate.current += candidate.weight
+			total += candidate.weight
+			if chosen == nil || candidate.current > chosen.current {
+				chosen = candidate
+			}
+		}
+		chosen.current -= total
+	case model.BalanceLowestLatency:
+		var fastest time.Duration
+		for i := range candidates {
+			candidate := candidates[(b.next+i)%len(candidates)]
+			median, ok := b.median(candidate)
+			if !ok {
+				// no samples yet, measure it
+				chosen = candidate
+				break
+			}
+			if chosen == nil || median < fastest {
+				chosen, fastest = candidate, median
+			}
+		}
+		b.next++
+	default:
+		chosen = candidates[b.next%len(candidates)]
+		b.next++
+	}
+
+	chosen.outstanding++
+
+	return chosen
+}
+
+func (b *balancer) median(endpoint *balancedEndpoint) (time.Duration, bool) {
+	if b.latency == nil {
+		return 0, false
+	}
+	return b.latency.median(endpoint.url.Host)
+}
+
+func (b *balancer) release(endpoint *balancedEndpoint, outcome exchangeOutcome) {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	endpoint.outstanding--
+
+	switch outcome {
+	case exchangeAbandoned:
+		return
+	case exchangeSucceeded:
+		endpoint.failures = 0
+		return
+	}
+
+	endpoint.failures++
+
+	if endpoint.failures >= unhealthyThreshold {
+		endpoint.unhealthyUntil = b.clock.Now().Add(unhealthyCooldown)
+	}
+}
+
+func (b *balancer) targets() []*url.URL {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	targets := make([]*url.URL, 0, len(b.endpoints))
+	for _, endpoint := range b.endpoints {
+		targets = append(targets, endpoint.url)
+	}
+
+	return targets
+}
+
+func (b *balancer) setDown(target *url.URL, down bool) {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, endpoint := range b.endpoints {
+		if endpoint.url.String() == target.String() {
+			endpoint.down = down
+		}
+	}
+}
+
+func (b *balancer) healthy(now time.Time) []*balancedEndpoint {
+
+	candidates := make([]*balancedEndpoint, 0, len(b.endpoints))
+
+	for _, endpoint := range b.endpoints {
+		if !endpoint.down && now.After(endpoint.unhealthyUntil) {
+			candidates = append(candidates, endpoint)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return b.endpoints
+	}
+
+	return candidates
+}
+
+/**
+ * Returns the balancer behind selector, if any.
+ */
+func balancerOf(selector endpointSelector) *balancer {
+	switch selector := selector.(type) {
+	case *balancer:
+		return selector
+	case *discoveryBalancer:
+		return selector.balancer
+	}
+	return nil
+}
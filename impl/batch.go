@@ -0,0 +1,58 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"sync"
+)
+
+/**
+ * Runs requests concurrently, at most concurrency at a time (all of them if
+ * concurrency is not positive), and returns their outcomes in the order of
+ * requests. Once ctx is done no further request is started; those left get
+ * ctx.Err() as their error. Requests keep their own context: cancel them
+ * through it, or build them with ctx.
+ */
+func All(ctx context.Context, requests []model.Request, concurrency int) []model.Result {
+
+	results := make([]model.Result, len(requests))
+
+	if concurrency <= 0 || concurrency > len(requests) {
+		concurrency = len(requests)
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = run(requests[index])
+			}
+		}()
+	}
+
+	started := 0
+
+	for ; started < len(requests) && ctx.Err() == nil; started++ {
+		select {
+		case indexes <- started:
+			continue
+		case <-ctx.Done():
+		}
+		break
+	}
+
+	close(indexes)
+
+	for index := started; index < len(requests); index++ {
+		results[index] = model.Result{Err: ctx.Err()}
+	}
+
+	wg.Wait()
+
+	return results
+}
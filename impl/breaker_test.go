@@ -0,0 +1,150 @@
+package gorequest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitOpensOnFailureRate(t *testing.T) {
+	breakers := newCircuitBreakers(model.CircuitBreaker{FailureRate: 0.5, MinRequests: 4, OpenTimeout: time.Hour})
+	c := breakers.circuitFor("api.example.com")
+
+	for _, outcome := range []exchangeOutcome{exchangeSucceeded, exchangeFailed, exchangeAbandoned, exchangeSucceeded} {
+		done, err := c.allow()
+		assert.Nil(t, err, "Should be closed")
+		done(outcome)
+	}
+
+	done, _ := c.allow()
+	done(exchangeFailed)
+
+	_, err := c.allow()
+
+	assert.IsType(t, &model.CircuitOpenError{}, err, "Should fail fast")
+	assert.Equal(t, "Circuit for api.example.com is open", err.Error(), "Should equal error message")
+	assert.Equal(t, model.CircuitOpen, breakers.states()["api.example.com"], "Should report the open state")
+}
+
+func TestCircuitHalfOpenProbes(t *testing.T) {
+	breakers := newCircuitBreakers(model.CircuitBreaker{FailureRate: 1, MinRequests: 1, OpenTimeout: 10 * time.Millisecond})
+	c := breakers.circuitFor("api.example.com")
+
+	done, _ := c.allow()
+	done(exchangeFailed)
+
+	time.Sleep(20 * time.Millisecond)
+
+	probe, err := c.allow()
+
+	assert.Nil(t, err, "Should let a probe through")
+	assert.Equal(t, model.CircuitHalfOpen, breakers.states()["api.example.com"], "Should be half-open")
+
+	_, err = c.allow()
+
+	assert.NotNil(t, err, "Should reject requests beyond the probes")
+
+	probe(exchangeAbandoned)
+
+	assert.Equal(t, model.CircuitHalfOpen, breakers.states()["api.example.com"], "Should stay half-open when the caller gave up")
+
+	probe, err = c.allow()
+
+	assert.Nil(t, err, "Should let another probe through")
+
+	probe(exchangeFailed)
+
+	assert.Equal(t, model.CircuitOpen, breakers.states()["api.example.com"], "Should open again after a failed probe")
+
+	time.Sleep(20 * time.Millisecond)
+
+	probe, _ = c.allow()
+	probe(exchangeSucceeded)
+
+	assert.Equal(t, model.CircuitClosed, breakers.states()["api.example.com"], "Should close after a successful probe")
+}
+
+func TestCircuitBreakerOnClient(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithCircuitBreaker(model.CircuitBreaker{FailureRate: 0.5, MinRequests: 2}).Build()
+
+	for i := 0; i < 2; i++ {
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+	}
+
+	defer func() {
+		err := recover()
+
+		assert.IsType(t, &model.CircuitOpenError{}, err, "Should fail fast")
+		assert.Equal(t, 2, calls, "Should not contact the host")
+	}()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.True(t, false, "Should not have completed test")
+}
+
+func TestCircuitBreakersArePerClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	defer ts.Close()
+
+	builder := NewClientBuilder().WithCircuitBreaker(model.CircuitBreaker{FailureRate: 0.5, MinRequests: 2})
+	tripped := builder.Build()
+	other := builder.Build()
+
+	for i := 0; i < 2; i++ {
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(tripped).Build().Do()
+	}
+
+	assert.Equal(t, model.CircuitOpen, tripped.CircuitStates()[strings.TrimPrefix(ts.URL, "http://")], "Should open the circuit of the failing client")
+	assert.Empty(t, other.CircuitStates(), "Should not share circuits across clients")
+}
+
+func TestCircuitBreakerIgnoresCancelledHedges(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1)%2 == 1 {
+			// every original request stalls until its hedge wins
+			<-req.Context().Done()
+			return
+		}
+		fmt.Fprint(resp, "hedge")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().
+		WithHedging(model.Hedge{Delay: 10 * time.Millisecond}).
+		WithCircuitBreaker(model.CircuitBreaker{FailureRate: 0.5, MinRequests: 2, OpenTimeout: time.Hour}).
+		Build()
+
+	for i := 0; i < 3; i++ {
+		_, err := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Send()
+		assert.Nil(t, err, "Should succeed with the hedge")
+		// let the cancelled copy report its outcome
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	for host, state := range c.CircuitStates() {
+		assert.Equal(t, model.CircuitClosed, state, "Should not count the cancelled copies against "+host)
+	}
+}
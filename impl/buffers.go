@@ -0,0 +1,72 @@
+package gorequest
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+/**
+ * Buffers larger than this are not returned to the pool, so that a few
+ * large bodies do not pin memory for the life of the process.
+ */
+var maxPooledBuffer int = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+/**
+ * Reads reader to the end into a slice of the exact size. A known size
+ * (e.g. a Content-Length) is read with a single allocation; otherwise the
+ * body is read into a pooled buffer and copied out, instead of growing a
+ * slice repeatedly as ioutil.ReadAll does. A body shorter than size fails
+ * with io.ErrUnexpectedEOF, along with what was received.
+ */
+func readAll(reader io.Reader, size int64) ([]byte, error) {
+
+	if size > 0 && size <= int64(maxPooledBuffer) {
+		data := make([]byte, size)
+		n, err := io.ReadFull(reader, data)
+		if err == io.EOF {
+			// shorter than announced: truncated, not complete
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return data[:n], err
+		}
+		rest, err := readAll(reader, 0)
+		if len(rest) == 0 {
+			return data, err
+		}
+		return append(data, rest...), err
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	_, err := buf.ReadFrom(reader)
+
+	if err == nil && int64(buf.Len()) < size {
+		err = io.ErrUnexpectedEOF
+	}
+
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+
+	return data, err
+}
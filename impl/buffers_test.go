@@ -0,0 +1,83 @@
+package gorequest
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAll(t *testing.T) {
+	body := strings.Repeat("gorequest", 1000)
+
+	for _, size := range []int64{-1, 0, int64(len(body)), 10} {
+		data, err := readAll(strings.NewReader(body), size)
+
+		assert.Nil(t, err, "Should read the body")
+		assert.Equal(t, body, string(data), "Should read the whole body whatever the size hint")
+	}
+
+	for _, size := range []int64{-1, int64(len(body))} {
+		data, _ := readAll(strings.NewReader(body), size)
+
+		assert.Equal(t, len(data), cap(data), "Should not keep spare capacity")
+	}
+
+	data, err := readAll(strings.NewReader(""), -1)
+
+	assert.Nil(t, err, "Should read an empty body")
+	assert.NotNil(t, data, "Should return an empty body as a non-nil slice")
+
+	for _, size := range []int64{int64(len(body) + 10), int64(maxPooledBuffer) + 1} {
+		data, err = readAll(strings.NewReader(body), size)
+
+		assert.Equal(t, io.ErrUnexpectedEOF, err, "Should fail on bodies shorter than announced")
+		assert.Equal(t, body, string(data), "Should return what was received")
+	}
+}
+
+func TestReadAllTruncatedResponse(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err, "Should listen")
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			bufio.NewReader(conn).ReadString('\n')
+			conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 100\r\nConnection: close\r\n\r\nhello"))
+			conn.Close()
+		}
+	}()
+
+	_, err = NewRequest(WithUrl("http://" + listener.Addr().String())).Send()
+
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "Should fail on truncated responses, got %v", err)
+}
+
+func BenchmarkReadAll(b *testing.B) {
+	body := bytes.Repeat([]byte("gorequest"), 4096)
+
+	b.Run("ioutil.ReadAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ioutil.ReadAll(bytes.NewReader(body))
+		}
+	})
+
+	b.Run("readAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			readAll(bytes.NewReader(body), -1)
+		}
+	})
+}
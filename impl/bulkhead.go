@@ -0,0 +1,69 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"strings"
+)
+
+/**
+ * Bulkheads of a client, indexed by host. Bulkheads are only configured
+ * while the client is built, so lookups need no locking.
+ */
+type bulkheads struct {
+	byHost map[string]*bulkhead
+	byName map[string]*bulkhead
+}
+
+type bulkhead struct {
+	limiter *concurrencyLimiter
+	name    string
+}
+
+func newBulkheads() *bulkheads {
+	return &bulkheads{
+		byHost: make(map[string]*bulkhead),
+		byName: make(map[string]*bulkhead),
+	}
+}
+
+func (b *bulkheads) add(settings model.Bulkhead) {
+	compartment := &bulkhead{
+		limiter: newConcurrencyLimiter(settings.MaxConcurrent, settings.MaxQueue),
+		name:    settings.Name,
+	}
+	b.byName[settings.Name] = compartment
+	for _, host := range settings.Hosts {
+		b.byHost[strings.ToLower(host)] = compartment
+	}
+}
+
+/**
+ * Takes a slot in the bulkhead of host, if any, and returns the function
+ * releasing it. Fails with a *model.BulkheadFullError when the bulkhead and
+ * its queue are full, or with the error of ctx if it is done while queued.
+ */
+func (b *bulkheads) acquire(ctx context.Context, host string, priority model.Priority) (func(), error) {
+
+	compartment, ok := b.byHost[strings.ToLower(host)]
+
+	if !ok {
+		return func() {}, nil
+	}
+
+	release, err := compartment.limiter.enter(ctx, priority)
+
+	if err == errQueueFull {
+		return nil, &model.BulkheadFullError{Name: compartment.name, Host: host}
+	}
+
+	return release, err
+}
+
+func (b *bulkheads) stats() map[string]model.BulkheadStats {
+	stats := make(map[string]model.BulkheadStats, len(b.byName))
+	for name, compartment := range b.byName {
+		stats[name] = compartment.limiter.snapshot()
+	}
+	return stats
+}
@@ -0,0 +1,159 @@
+package gorequest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"net/http"
+	"strings"
+)
+
+/**
+ * The part of hash.Hash used to verify bodies.
+ */
+type digest interface {
+	io.Writer
+	Size() int
+	Sum(b []byte) []byte
+}
+
+func newDigest(algorithm string) digest {
+	switch algorithm {
+	case model.ChecksumMD5:
+		return md5.New()
+	case model.ChecksumSHA256:
+		return sha256.New()
+	}
+	return nil
+}
+
+/**
+ * Hashes a response body as it is read and fails the read reaching its end
+ * if the digest is not the expected one.
+ */
+type checksumReader struct {
+	algorithm string
+	digest    digest
+	expected  string
+	reader    io.ReadCloser
+}
+
+func newChecksumReader(reader io.ReadCloser, checksum model.Checksum, header http.Header) (io.ReadCloser, error) {
+
+	hasher := newDigest(checksum.Algorithm)
+	expected := strings.ToLower(checksum.Expected)
+
+	if expected == "" {
+		expected = announcedDigest(checksum.Algorithm, hasher.Size(), header)
+	}
+
+	if expected == "" {
+		return reader, fmt.Errorf("Response announces no %s digest to verify", checksum.Algorithm)
+	}
+
+	return &checksumReader{
+		algorithm: checksum.Algorithm,
+		digest:    hasher,
+		expected:  expected,
+		reader:    reader,
+	}, nil
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+
+	n, err := r.reader.Read(p)
+	r.digest.Write(p[:n])
+
+	if err == io.EOF {
+		if actual := hex.EncodeToString(r.digest.Sum(nil)); actual != r.expected {
+			return n, &model.ChecksumError{Actual: actual, Algorithm: r.algorithm, Expected: r.expected}
+		}
+	}
+
+	return n, err
+}
+
+func (r *checksumReader) Close() error {
+	return r.reader.Close()
+}
+
+/**
+ * Returns the hex digest for algorithm announced by Content-Digest
+ * (sha-256=:base64:), Digest (SHA-256=base64), Content-MD5 or, failing
+ * those, an ETag holding a hex digest of size bytes; empty if there is none.
+ */
+func announcedDigest(algorithm string, size int, header http.Header) string {
+
+	for _, name := range []string{"Content-Digest", "Digest"} {
+		for _, value := range header.Values(name) {
+			for _, entry := range strings.Split(value, ",") {
+
+				parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+
+				if len(parts) != 2 || !strings.EqualFold(parts[0], algorithm) {
+					continue
+				}
+
+				raw, err := base64.StdEncoding.DecodeString(strings.Trim(parts[1], ":"))
+
+				if err == nil && len(raw) == size {
+					return hex.EncodeToString(raw)
+				}
+			}
+		}
+	}
+
+	if algorithm == model.ChecksumMD5 {
+		if raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(header.Get("Content-MD5"))); err == nil && len(raw) == size {
+			return hex.EncodeToString(raw)
+		}
+	}
+
+	// weak ETags (W/"...") are not digests of the bytes
+	etag := strings.ToLower(strings.Trim(header.Get("ETag"), `"`))
+
+	if raw, err := hex.DecodeString(etag); err == nil && len(raw) == size {
+		return etag
+	}
+
+	return ""
+}
+
+/**
+ * Hashes the body of req, reopened with GetBody, and sets the header
+ * carrying its digest. Requests without a body are left as they are.
+ */
+func setContentDigest(req *http.Request, algorithm string) error {
+
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return err
+	}
+
+	defer body.Close()
+
+	hasher := newDigest(algorithm)
+
+	if _, err := io.Copy(hasher, body); err != nil {
+		return err
+	}
+
+	sum := base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+
+	if algorithm == model.ChecksumMD5 {
+		req.Header.Set("Content-MD5", sum)
+	} else {
+		req.Header.Set("Content-Digest", algorithm+"=:"+sum+":")
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package gorequest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksum(t *testing.T) {
+	artifact := "release artifact"
+	sha := sha256.Sum256([]byte(artifact))
+	sum := md5.Sum([]byte(artifact))
+
+	mock := requestmock.New()
+
+	mock.On("GET", "/release.tar.gz").Reply(http.StatusOK, artifact)
+	mock.On("GET", "/corrupted.tar.gz").Reply(http.StatusOK, "release artifacT")
+	mock.On("GET", "/digest.tar.gz").ReplyHeader("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sha[:])+":").Reply(http.StatusOK, artifact)
+	mock.On("GET", "/etag.tar.gz").ReplyHeader("ETag", `"`+hex.EncodeToString(sum[:])+`"`).Reply(http.StatusOK, artifact)
+	mock.On("GET", "/missing.tar.gz").Reply(http.StatusNotFound, "not found")
+
+	c := NewClient(WithTransport(mock), WithBaseURL("https://downloads.example.com"))
+	expected := model.Checksum{Algorithm: model.ChecksumSHA256, Expected: hex.EncodeToString(sha[:])}
+
+	resp, err := NewRequest(WithClient(c), WithUrl("/release.tar.gz"), WithChecksum(expected)).Send()
+
+	assert.Nil(t, err, "Should accept bodies matching the checksum")
+	assert.Equal(t, artifact, string(resp.Body()), "Should read the body")
+
+	_, err = NewRequest(WithClient(c), WithUrl("/corrupted.tar.gz"), WithChecksum(expected)).Send()
+
+	var mismatch *model.ChecksumError
+
+	assert.True(t, errors.As(err, &mismatch), "Should fail with a ChecksumError, got %v", err)
+	assert.Equal(t, hex.EncodeToString(sha[:]), mismatch.Expected, "Should report the expected digest")
+
+	var sink bytes.Buffer
+
+	_, err = NewRequestBuilder().WithClient(c).WithUrl("/corrupted.tar.gz").WithChecksum(expected).WithResponseSink(&sink).Build().Send()
+
+	assert.True(t, errors.As(err, &mismatch), "Should fail sinks, got %v", err)
+
+	resp, err = NewRequestBuilder().WithClient(c).WithUrl("/corrupted.tar.gz").WithChecksum(expected).WithResponseStream().Build().Send()
+
+	assert.Nil(t, err, "Should leave streamed bodies to the caller")
+
+	_, err = io.ReadAll(resp.Response().Body)
+	resp.Response().Body.Close()
+
+	assert.True(t, errors.As(err, &mismatch), "Should fail the last read of streams, got %v", err)
+
+	_, err = NewRequest(WithClient(c), WithUrl("/digest.tar.gz"), WithChecksum(model.Checksum{Algorithm: model.ChecksumSHA256})).Send()
+
+	assert.Nil(t, err, "Should verify against Content-Digest")
+
+	_, err = NewRequest(WithClient(c), WithUrl("/etag.tar.gz"), WithChecksum(model.Checksum{Algorithm: model.ChecksumMD5})).Send()
+
+	assert.Nil(t, err, "Should verify against digest ETags")
+
+	_, err = NewRequest(WithClient(c), WithUrl("/release.tar.gz"), WithChecksum(model.Checksum{Algorithm: model.ChecksumSHA256})).Send()
+
+	assert.EqualError(t, err, "Response announces no sha-256 digest to verify", "Should fail without a digest to verify")
+
+	resp, err = NewRequest(WithClient(c), WithUrl("/missing.tar.gz"), WithChecksum(expected)).Send()
+
+	assert.Nil(t, err, "Should not verify error responses")
+	assert.Equal(t, http.StatusNotFound, resp.Response().StatusCode, "Should return error responses as is")
+
+	assert.Panics(t, func() { NewRequestBuilder().WithChecksum(model.Checksum{Algorithm: "crc32"}) }, "Should reject unknown algorithms")
+}
+
+func TestChecksumOfCompressedBodies(t *testing.T) {
+	artifact := "release artifact"
+
+	var gzipped bytes.Buffer
+	w := gzip.NewWriter(&gzipped)
+	w.Write([]byte(artifact))
+	w.Close()
+
+	wire := sha256.Sum256(gzipped.Bytes())
+	content := sha256.Sum256([]byte(artifact))
+	sum := md5.Sum(gzipped.Bytes())
+
+	mock := requestmock.New()
+
+	mock.On("GET", "/digest.tar").ReplyHeader("Content-Encoding", "gzip").ReplyHeader("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(wire[:])+":").Reply(http.StatusOK, gzipped.String())
+	mock.On("GET", "/md5.tar").ReplyHeader("Content-Encoding", "gzip").ReplyHeader("Content-MD5", base64.StdEncoding.EncodeToString(sum[:])).Reply(http.StatusOK, gzipped.String())
+
+	c := NewClient(WithTransport(mock), WithBaseURL("https://downloads.example.com"))
+
+	resp, err := NewRequest(WithClient(c), WithUrl("/digest.tar"), WithAcceptEncoding("gzip"), WithChecksum(model.Checksum{Algorithm: model.ChecksumSHA256})).Send()
+
+	assert.Nil(t, err, "Should verify announced digests against the bytes received")
+	assert.Equal(t, artifact, string(resp.Body()), "Should decompress the verified body")
+
+	_, err = NewRequest(WithClient(c), WithUrl("/md5.tar"), WithAcceptEncoding("gzip"), WithChecksum(model.Checksum{Algorithm: model.ChecksumMD5})).Send()
+
+	assert.Nil(t, err, "Should verify against Content-MD5")
+
+	_, err = NewRequest(WithClient(c), WithUrl("/digest.tar"), WithAcceptEncoding("gzip"), WithChecksum(model.Checksum{Algorithm: model.ChecksumSHA256, Expected: hex.EncodeToString(content[:])})).Send()
+
+	assert.Nil(t, err, "Should verify expected digests against the decompressed body")
+}
+
+func TestContentDigest(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("PUT", "/bucket/object").Reply(http.StatusOK, "")
+
+	c := NewClient(WithTransport(mock), WithBaseURL("https://storage.example.com"))
+	body := `{"id":1}`
+	sha := sha256.Sum256([]byte(body))
+	sum := md5.Sum([]byte(body))
+
+	NewRequest(WithClient(c), WithMethod("PUT"), WithUrl("/bucket/object"), WithBody(newJsonBody(body)), WithContentDigest(model.ChecksumSHA256)).Do()
+	NewRequest(WithClient(c), WithMethod("PUT"), WithUrl("/bucket/object"), WithBody(newJsonBody(body)), WithContentDigest(model.ChecksumMD5)).Do()
+
+	calls := mock.Calls()
+
+	assert.Equal(t, "sha-256=:"+base64.StdEncoding.EncodeToString(sha[:])+":", calls[0].Header.Get("Content-Digest"), "Should send the SHA-256 digest")
+	assert.Equal(t, base64.StdEncoding.EncodeToString(sum[:]), calls[1].Header.Get("Content-MD5"), "Should send the MD5 digest")
+	assert.Equal(t, body, string(calls[0].Body), "Should send the body whole after hashing it")
+
+	req, err := NewRequestBuilder().WithUrl("https://storage.example.com/bucket/object").WithContentDigest(model.ChecksumSHA256).BuildHttpRequest(context.Background())
+
+	assert.Nil(t, err, "Should build requests without a body")
+	assert.Empty(t, req.Header.Get("Content-Digest"), "Should not digest missing bodies")
+}
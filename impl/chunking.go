@@ -0,0 +1,43 @@
+package gorequest
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+/**
+ * Sets the Content-Length or Transfer-Encoding of req for chunking.
+ */
+func frameBody(req *http.Request, chunking model.Chunking) error {
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	switch chunking {
+	case model.ChunkingAlways:
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	case model.ChunkingNever:
+		// a zero length along with a body also means an unknown size
+		if req.ContentLength > 0 {
+			return nil
+		}
+		if req.GetBody == nil {
+			return bufferBody(req)
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return err
+		}
+		defer body.Close()
+		if req.ContentLength, err = io.Copy(ioutil.Discard, body); err != nil {
+			return err
+		}
+		req.TransferEncoding = nil
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package gorequest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunking(t *testing.T) {
+	var encodings []string
+	var lengths []int64
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		encodings = append(encodings, strings.Join(r.TransferEncoding, ","))
+		lengths = append(lengths, r.ContentLength)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	send := func(chunking model.Chunking) {
+		NewRequest(WithUrl(server.URL), WithMethod("POST"), WithBody(newJsonBody(`{"id":1}`)), WithChunking(chunking)).Do()
+	}
+
+	send(model.ChunkingAuto)
+	send(model.ChunkingAlways)
+
+	assert.Equal(t, []string{"", "chunked"}, encodings, "Should chunk bodies only when forced to")
+	assert.Equal(t, []int64{8, -1}, lengths, "Should send the length of bodies that are not chunked")
+	assert.Equal(t, []string{`{"id":1}`, `{"id":1}`}, bodies, "Should send the whole body either way")
+
+	req, _ := http.NewRequest("POST", server.URL, ioutil.NopCloser(strings.NewReader("streamed")))
+
+	assert.Nil(t, frameBody(req, model.ChunkingNever), "Should frame bodies of unknown size")
+	assert.Equal(t, int64(8), req.ContentLength, "Should measure bodies of unknown size")
+
+	NewRequestFromHttp(nil, req).Do()
+
+	assert.Equal(t, "", encodings[2], "Should not chunk bodies when forbidden to")
+	assert.Equal(t, "streamed", bodies[2], "Should send the buffered body")
+}
@@ -0,0 +1,204 @@
+package gorequest
+
+import (
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+var defaultBreakerMinRequests int = 10
+var defaultBreakerOpenTimeout time.Duration = 30 * time.Second
+var defaultBreakerWindow time.Duration = 10 * time.Second
+
+/**
+ * Holds one circuit per host.
+ */
+type circuitBreakers struct {
+	circuits map[string]*circuit
+	clock    model.Clock
+	mutex    sync.Mutex
+	settings model.CircuitBreaker
+}
+
+type circuit struct {
+	clock    model.Clock
+	host     string
+	mutex    sync.Mutex
+	openedAt time.Time
+	probes   int
+	settings *model.CircuitBreaker
+	state    model.CircuitState
+	// successes of the probes sent while half-open
+	succeeded int
+	// requests and failures
+	window *rollingWindow
+}
+
+func newCircuitBreakers(settings model.CircuitBreaker) *circuitBreakers {
+
+	if settings.FailureRate <= 0 || settings.FailureRate > 1 {
+		panic(errors.New("Failure rate must be greater than 0 and at most 1"))
+	}
+
+	if settings.MinRequests <= 0 {
+		settings.MinRequests = defaultBreakerMinRequests
+	}
+	if settings.Window <= 0 {
+		settings.Window = defaultBreakerWindow
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = defaultBreakerOpenTimeout
+	}
+	if settings.HalfOpenProbes <= 0 {
+		settings.HalfOpenProbes = 1
+	}
+
+	return &circuitBreakers{
+		circuits: make(map[string]*circuit),
+		clock:    systemClock{},
+		settings: settings,
+	}
+}
+
+func (b *circuitBreakers) circuitFor(host string) *circuit {
+
+	host = strings.ToLower(host)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	c, ok := b.circuits[host]
+
+	if !ok {
+		c = &circuit{
+			clock:    b.clock,
+			host:     host,
+			settings: &b.settings,
+			window:   newRollingWindow(b.settings.Window),
+		}
+		b.circuits[host] = c
+	}
+
+	return c
+}
+
+func (b *circuitBreakers) states() map[string]model.CircuitState {
+
+	b.mutex.Lock()
+	circuits := make([]*circuit, 0, len(b.circuits))
+	for _, c := range b.circuits {
+		circuits = append(circuits, c)
+	}
+	b.mutex.Unlock()
+
+	states := make(map[string]model.CircuitState, len(circuits))
+
+	for _, c := range circuits {
+		c.mutex.Lock()
+		states[c.host] = c.current(c.clock.Now())
+		c.mutex.Unlock()
+	}
+
+	return states
+}
+
+/**
+ * Admits a request or fails fast with a *model.CircuitOpenError. Admitted
+ * requests must report their outcome through the returned function.
+ */
+func (c *circuit) allow() (func(outcome exchangeOutcome), error) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	state := c.current(c.clock.Now())
+
+	switch state {
+	case model.CircuitOpen:
+		return nil, &model.CircuitOpenError{Host: c.host, State: state}
+	case model.CircuitHalfOpen:
+		if c.probes >= c.settings.HalfOpenProbes {
+			return nil, &model.CircuitOpenError{Host: c.host, State: state}
+		}
+		c.probes++
+		return c.probeDone, nil
+	default:
+		return c.done, nil
+	}
+}
+
+/**
+ * Returns the state, moving from open to half-open once the timeout passed.
+ */
+func (c *circuit) current(now time.Time) model.CircuitState {
+	if c.state == model.CircuitOpen && now.Sub(c.openedAt) >= c.settings.OpenTimeout {
+		c.state = model.CircuitHalfOpen
+		c.probes = 0
+		c.succeeded = 0
+	}
+	return c.state
+}
+
+func (c *circuit) done(outcome exchangeOutcome) {
+
+	if outcome == exchangeAbandoned {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+
+	if outcome == exchangeFailed {
+		c.window.add(now, 1, 1)
+	} else {
+		c.window.add(now, 1, 0)
+	}
+
+	if c.state != model.CircuitClosed {
+		// outcome of a request admitted before the circuit opened
+		return
+	}
+
+	requests, failures := c.window.sums(now)
+
+	if requests >= c.settings.MinRequests && float64(failures)/float64(requests) >= c.settings.FailureRate {
+		c.open(now)
+	}
+}
+
+func (c *circuit) probeDone(outcome exchangeOutcome) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.state != model.CircuitHalfOpen {
+		return
+	}
+
+	if outcome == exchangeAbandoned {
+		// the probe proved nothing, the next request probes instead
+		c.probes--
+		return
+	}
+
+	if outcome == exchangeFailed {
+		c.open(c.clock.Now())
+		return
+	}
+
+	c.succeeded++
+
+	if c.succeeded >= c.settings.HalfOpenProbes {
+		c.state = model.CircuitClosed
+		c.window.reset()
+	}
+}
+
+func (c *circuit) open(now time.Time) {
+	c.state = model.CircuitOpen
+	c.openedAt = now
+}
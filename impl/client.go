@@ -0,0 +1,290 @@
+package gorequest
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+/****************************************************
+ * model.Client implementation
+ ****************************************************/
+
+type client struct {
+	activeCount      int32
+	auditor          *auditor
+	auth             model.AuthorizationMethod
+	breakers         *circuitBreakers
+	bulkheads        *bulkheads
+	canonicalQuery   bool
+	clock            model.Clock
+	closed           bool
+	concurrency      *concurrencyLimiter
+	contextHeaders   []contextHeader
+	downloadRate     *tokenBucket
+	endpoints        endpointSelector
+	expvar           *expvarStats
+	faults           *faultInjector
+	flights          *flightGroup
+	headers          map[string]string
+	healthChecker    *healthChecker
+	hedge            *model.Hedge
+	hooks            []model.Hooks
+	hostLimiter      *hostLimiter
+	httpClient       *http.Client
+	inFlight         sync.WaitGroup
+	latency          *latencyTracker
+	loggers          []*requestLogger
+	memo             *memoizer
+	metrics          *metricsReporter
+	observers        []model.Observer
+	mutex            sync.Mutex
+	parent           *client
+	profilerLabels   bool
+	quotaPacer       *quotaPacer
+	rateLimiter      *rateLimiter
+	redirectHeaders  *model.RedirectHeaders
+	redirectPolicy   model.RedirectPolicy
+	retry            *model.Retry
+	retryBudget      *retryBudget
+	retryStats       *retryStats
+	stats            *connectionStats
+	strictIDNA       bool
+	tracePropagation *model.TracePropagation
+	uploadRate       *tokenBucket
+	variants         map[tlsVariant]*http.Client
+}
+
+/**
+ * Per-request TLS settings that cannot be shared with the client's main
+ * transport. Each distinct variant gets its own transport (and therefore its
+ * own connection pool), so a relaxed setting never leaks into other requests.
+ */
+type tlsVariant struct {
+	insecureSkipVerify bool
+	serverName         string
+}
+
+func newClient(httpClient *http.Client) *client {
+	return &client{
+		clock:      systemClock{},
+		httpClient: httpClient,
+		memo:       newMemoizer(defaultMemoEntries),
+		retryStats: &retryStats{},
+		variants:   make(map[tlsVariant]*http.Client),
+	}
+}
+
+func (c *client) BulkheadStats() map[string]model.BulkheadStats {
+	if c.bulkheads == nil {
+		return map[string]model.BulkheadStats{}
+	}
+	return c.bulkheads.stats()
+}
+
+func (c *client) CircuitStates() map[string]model.CircuitState {
+	if c.breakers == nil {
+		return map[string]model.CircuitState{}
+	}
+	return c.breakers.states()
+}
+
+/**
+ * Rejects new requests with model.ErrClientClosed, waits for the requests in
+ * flight until ctx is done, stops background work (discovery, health checks),
+ * closes the audit sink and idle connections. Returns ctx.Err() if requests
+ * were still in flight when ctx was done. Clones only stop accepting
+ * requests; what they share with the original is closed with it.
+ */
+func (c *client) Close(ctx context.Context) error {
+
+	c.mutex.Lock()
+	if c.closed {
+		c.mutex.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mutex.Unlock()
+
+	drained := make(chan struct{})
+
+	go func() {
+		c.inFlight.Wait()
+		close(drained)
+	}()
+
+	var err error
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if c.parent != nil {
+		return err
+	}
+
+	if c.healthChecker != nil {
+		c.healthChecker.close()
+	}
+
+	if c.auditor != nil {
+		c.auditor.close()
+	}
+
+	if c.expvar != nil {
+		c.expvar.release(c)
+	}
+
+	if closer, ok := c.endpoints.(interface{ close() }); ok {
+		closer.close()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.httpClient.CloseIdleConnections()
+	for _, httpClient := range c.variants {
+		httpClient.CloseIdleConnections()
+	}
+
+	return err
+}
+
+func (c *client) ConnectionStats() model.ConnectionStats {
+	if c.stats == nil {
+		return model.ConnectionStats{Hosts: map[string]model.HostConnectionStats{}}
+	}
+	return c.stats.snapshot()
+}
+
+func (c *client) HttpClient() *http.Client {
+	return c.httpClient
+}
+
+func (c *client) LatencyStats() map[string]model.LatencyStats {
+	if c.latency == nil {
+		return map[string]model.LatencyStats{}
+	}
+	return c.latency.snapshot()
+}
+
+func (c *client) RetryStats() model.RetryStats {
+	return c.retryStats.snapshot()
+}
+
+/**
+ * Registers a request in flight and returns the function ending it. Fails
+ * once the client is closed.
+ */
+func (c *client) enter() (func(), error) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return nil, model.ErrClientClosed
+	}
+
+	c.inFlight.Add(1)
+	c.reportActive(atomic.AddInt32(&c.activeCount, 1))
+
+	return func() {
+		c.reportActive(atomic.AddInt32(&c.activeCount, -1))
+		c.inFlight.Done()
+	}, nil
+}
+
+func (c *client) reportActive(count int32) {
+	if c.metrics != nil {
+		c.metrics.inFlight(count)
+	}
+}
+
+/**
+ * Returns the number of requests in flight.
+ */
+func (c *client) active() int32 {
+	return atomic.LoadInt32(&c.activeCount)
+}
+
+/**
+ * Enables connection tracking. Only valid for clients whose transport was
+ * created by this package, since the transport's dialer gets replaced.
+ */
+func (c *client) trackConnections() *client {
+	c.stats = newConnectionStats()
+	transport, _ := c.transport()
+	c.stats.instrument(transport)
+	return c
+}
+
+func (c *client) httpClientFor(variant tlsVariant) (*http.Client, error) {
+
+	if variant == (tlsVariant{}) {
+		return c.httpClient, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if httpClient, ok := c.variants[variant]; ok {
+		return httpClient, nil
+	}
+
+	base, err := c.transport()
+
+	if err != nil {
+		return nil, err
+	}
+
+	transport := base.Clone()
+
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.InsecureSkipVerify = variant.insecureSkipVerify
+	if variant.serverName != "" {
+		transport.TLSClientConfig.ServerName = variant.serverName
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.variants[variant] = &httpClient
+
+	return &httpClient, nil
+}
+
+/**
+ * Returns the transport of the client, which only clients built on an
+ * *http.Transport have; trackConnections is only used on those.
+ */
+func (c *client) transport() (*http.Transport, error) {
+	switch transport := c.httpClient.Transport.(type) {
+	case nil:
+		return http.DefaultTransport.(*http.Transport), nil
+	case *http.Transport:
+		return transport, nil
+	default:
+		return nil, errors.New("Per-request TLS settings require the client to use an *http.Transport")
+	}
+}
+
+/**
+ * Returns the internal client backing the given model.Client. Foreign
+ * implementations are wrapped around the *http.Client they expose.
+ */
+func asClient(c model.Client) *client {
+	if c == nil {
+		return getDefaultClient()
+	}
+	if internal, ok := c.(*client); ok {
+		return internal
+	}
+	return newClient(c.HttpClient())
+}
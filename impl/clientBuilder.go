@@ -0,0 +1,886 @@
+package gorequest
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net"
+	"net/http"
+	"time"
+)
+
+/****************************************************
+ * model.ClientBuilder implementation
+ ****************************************************/
+
+type clientBuilder struct {
+	auditor          *auditor
+	breaker          *model.CircuitBreaker
+	bulkheads        []model.Bulkhead
+	canonicalQuery   bool
+	cipherSuites     []uint16
+	clock            model.Clock
+	concurrency      *concurrencyLimit
+	contextHeaders   []contextHeader
+	curvePreferences []tls.CurveID
+	downloadRate     *byteRate
+	deduplicate      bool
+	endpoints        func() endpointSelector
+	expvarName       string
+	faults           []model.Fault
+	healthCheck      *model.HealthCheck
+	hedge            *model.Hedge
+	hooks            []model.Hooks
+	hostLimit        *hostLimit
+	httpClient       *http.Client
+	dialer           dialerConfig
+	iface            string
+	latencyWindow    *time.Duration
+	localAddr        string
+	loggers          []*requestLogger
+	maxTLSVersion    uint16
+	memoEntries      int
+	metrics          *metricsReporter
+	minTLSVersion    uint16
+	observers        []model.Observer
+	offline          *offlineTransport
+	pinReporter      model.PinningReporter
+	pins             map[string][]string
+	profilerLabels   bool
+	quotaPacing      *quotaPacing
+	rateLimits       []rateLimit
+	redirectHeaders  *model.RedirectHeaders
+	redirectPolicy   model.RedirectPolicy
+	retry            *model.Retry
+	retryBudget      *model.RetryBudget
+	rootCADirs       []string
+	rootCAFiles      []string
+	rootCAs          [][]byte
+	strictIDNA       bool
+	timeout          time.Duration
+	tracePropagation *model.TracePropagation
+	transport        http.RoundTripper
+	uploadRate       *byteRate
+}
+
+type byteRate struct {
+	burst          int
+	bytesPerSecond int
+}
+
+type concurrencyLimit struct {
+	limit     int
+	queueSize int
+}
+
+type hostLimit struct {
+	limit  int
+	policy model.OverflowPolicy
+}
+
+type rateLimit struct {
+	burst             int
+	host              string
+	policy            model.OverflowPolicy
+	requestsPerSecond float64
+}
+
+func (b *clientBuilder) Build() model.Client {
+
+	b.validate()
+
+	client := newClient(&http.Client{
+		CheckRedirect: checkRedirect,
+		Timeout:       b.timeout,
+		Transport:     b.roundTripper(),
+	})
+
+	if b.httpClient != nil {
+		client.httpClient.CheckRedirect = chainCheckRedirect(b.httpClient.CheckRedirect)
+		client.httpClient.Jar = b.httpClient.Jar
+	}
+
+	if b.transport == nil && b.offline == nil {
+		client.trackConnections()
+	}
+
+	if b.clock != nil {
+		client.clock = b.clock
+	}
+
+	client.auditor = b.auditor
+	client.canonicalQuery = b.canonicalQuery
+	client.contextHeaders = append([]contextHeader(nil), b.contextHeaders...)
+	client.hedge = b.hedge
+	client.hooks = append([]model.Hooks(nil), b.hooks...)
+	client.loggers = b.loggers
+	client.observers = append([]model.Observer(nil), b.observers...)
+	client.profilerLabels = b.profilerLabels
+	client.redirectHeaders = b.redirectHeaders
+	client.redirectPolicy = b.redirectPolicy
+	client.retry = b.retry
+	client.strictIDNA = b.strictIDNA
+	client.tracePropagation = b.tracePropagation
+
+	// limiters, circuits and endpoints keep state, every client gets its own
+	b.buildLimits(client)
+
+	if b.memoEntries > 0 {
+		client.memo = newMemoizer(b.memoEntries)
+	}
+
+	if b.endpoints != nil {
+		client.endpoints = b.endpoints()
+	}
+
+	balancer := balancerOf(client.endpoints)
+
+	if balancer != nil {
+		balancer.clock = client.clock
+	}
+	var latency *latencyTracker
+
+	if b.latencyWindow != nil {
+		latency = newLatencyTracker(client.clock, *b.latencyWindow)
+	} else if balancer != nil && balancer.strategy == model.BalanceLowestLatency {
+		latency = newLatencyTracker(client.clock, 0)
+	}
+
+	if latency != nil {
+		client.latency = latency
+		client.observers = append(client.observers, latency)
+		if balancer != nil {
+			balancer.latency = latency
+		}
+	}
+
+	if b.metrics != nil {
+		client.metrics = b.metrics
+		client.observers = append(client.observers, b.metrics)
+	}
+
+	if b.expvarName != "" {
+		client.expvar = newExpvarStats(b.expvarName, client)
+		client.observers = append(client.observers, client.expvar)
+	}
+
+	if b.healthCheck != nil {
+		client.healthChecker = newHealthChecker(*b.healthCheck, client.endpoints, client.httpClient).start()
+	}
+
+	if b.deduplicate {
+		client.flights = newFlightGroup()
+	}
+
+	return client
+}
+
+func (b *clientBuilder) buildLimits(client *client) {
+
+	if b.breaker != nil {
+		client.breakers = newCircuitBreakers(*b.breaker)
+		client.breakers.clock = client.clock
+	}
+
+	if len(b.bulkheads) > 0 {
+		client.bulkheads = newBulkheads()
+		for _, bulkhead := range b.bulkheads {
+			client.bulkheads.add(bulkhead)
+		}
+	}
+
+	if b.concurrency != nil {
+		client.concurrency = newConcurrencyLimiter(b.concurrency.limit, b.concurrency.queueSize)
+	}
+
+	if b.downloadRate != nil {
+		client.downloadRate = newTokenBucket(client.clock, float64(b.downloadRate.bytesPerSecond), b.downloadRate.burst)
+	}
+
+	if len(b.faults) > 0 {
+		client.faults = &faultInjector{clock: client.clock, faults: append([]model.Fault(nil), b.faults...)}
+	}
+
+	if b.hostLimit != nil {
+		client.hostLimiter = newHostLimiter(b.hostLimit.limit, b.hostLimit.policy)
+	}
+
+	if b.quotaPacing != nil {
+		client.quotaPacer = newQuotaPacer(client.clock, *b.quotaPacing)
+	}
+
+	if len(b.rateLimits) > 0 {
+		client.rateLimiter = newRateLimiter(client.clock)
+		for _, limit := range b.rateLimits {
+			client.rateLimiter.limit(limit.host, limit.requestsPerSecond, limit.burst, limit.policy)
+		}
+	}
+
+	if b.retryBudget != nil {
+		client.retryBudget = newRetryBudget(*b.retryBudget)
+		client.retryBudget.clock = client.clock
+	}
+
+	if b.uploadRate != nil {
+		client.uploadRate = newTokenBucket(client.clock, float64(b.uploadRate.bytesPerSecond), b.uploadRate.burst)
+	}
+}
+
+/**
+ * Records every exchange (each attempt of each request) in an audit sink:
+ * principal, method, URL, status and body hashes. Credentials, query strings
+ * and raw bodies are never recorded.
+ */
+func (b *clientBuilder) WithAudit(audit model.Audit) model.ClientBuilder {
+	b.auditor = newAuditor(audit)
+	return b
+}
+
+/**
+ * Distributes requests with relative URLs across endpoints. Endpoints that
+ * keep failing are taken out of rotation for a while. Replaces any endpoints
+ * set with WithEndpoints.
+ */
+func (b *clientBuilder) WithBalancer(strategy model.BalancingStrategy, endpoints ...model.Endpoint) model.ClientBuilder {
+	if len(endpoints) == 0 {
+		panic(errors.New("At least one endpoint is required"))
+	}
+	endpoints = append([]model.Endpoint(nil), endpoints...)
+	b.endpoints = func() endpointSelector {
+		return newBalancer(strategy, endpoints)
+	}
+	return b
+}
+
+/**
+ * Isolates the requests to the hosts of the bulkhead in their own pool of
+ * slots. Requests to hosts outside any bulkhead are not limited.
+ */
+func (b *clientBuilder) WithBulkhead(bulkhead model.Bulkhead) model.ClientBuilder {
+	if bulkhead.MaxConcurrent <= 0 {
+		panic(errors.New("Bulkhead must allow a positive number of requests"))
+	}
+	if bulkhead.MaxQueue < 0 {
+		panic(errors.New("Queue size cannot be negative"))
+	}
+	b.bulkheads = append(b.bulkheads, bulkhead)
+	return b
+}
+
+/**
+ * Ignores the order of query parameters when telling whether requests are
+ * the same, for memoization and deduplication; only for APIs where that
+ * order does not matter. URLs are always normalized otherwise (case of the
+ * scheme and host, default ports, dot segments...).
+ */
+func (b *clientBuilder) WithCanonicalQuery() model.ClientBuilder {
+	b.canonicalQuery = true
+	return b
+}
+
+func (b *clientBuilder) WithCipherSuites(suites ...uint16) model.ClientBuilder {
+	b.cipherSuites = suites
+	return b
+}
+
+/**
+ * Enables a circuit breaker per destination host. Requests to a host whose
+ * circuit is open fail immediately with a *model.CircuitOpenError.
+ */
+func (b *clientBuilder) WithCircuitBreaker(breaker model.CircuitBreaker) model.ClientBuilder {
+	// validates the settings, every client builds its own breakers
+	newCircuitBreakers(breaker)
+	b.breaker = &breaker
+	return b
+}
+
+/**
+ * Replaces the system clock driving the time-based features of the client:
+ * retries and their deadlines, hedging, circuit breakers, the retry budget,
+ * rate limits and pacing, endpoint cooldowns, latency windows and injected
+ * latency; e.g. with a fake clock in tests. Timings of exchanges are still
+ * measured on the system clock.
+ */
+func (b *clientBuilder) WithClock(clock model.Clock) model.ClientBuilder {
+	if clock == nil {
+		panic(errors.New("Clock cannot be nil"))
+	}
+	b.clock = clock
+	return b
+}
+
+/**
+ * Copies the value stored in the request context under key into the given
+ * header of every request, e.g. a correlation or tenant ID. Values that are
+ * not strings are formatted with fmt. Requests without the value, or with
+ * the header set explicitly, are left alone.
+ */
+func (b *clientBuilder) WithContextHeader(key interface{}, header string) model.ClientBuilder {
+	if key == nil {
+		panic(errors.New("Context key cannot be nil"))
+	}
+	if header == "" {
+		panic(errors.New("Header name is required"))
+	}
+	b.contextHeaders = append(b.contextHeaders, contextHeader{header: header, key: key})
+	return b
+}
+
+func (b *clientBuilder) WithCurvePreferences(curves ...tls.CurveID) model.ClientBuilder {
+	b.curvePreferences = curves
+	return b
+}
+
+/**
+ * Coalesces concurrent GET requests with the same URL, headers and TLS
+ * settings into a single upstream call whose response is shared. Requests
+ * joining a call in flight do not run their own client traces.
+ */
+func (b *clientBuilder) WithDeduplication() model.ClientBuilder {
+	b.deduplicate = true
+	return b
+}
+
+/**
+ * Balances requests with relative URLs across the endpoints reported by
+ * discovery, refreshed every refresh interval. The first lookup happens when
+ * the client is built, and panics if it fails; failed refreshes keep the
+ * last known endpoints. Each client refreshes its endpoints until closed.
+ */
+func (b *clientBuilder) WithDiscovery(discovery model.Discovery, strategy model.BalancingStrategy, refresh time.Duration) model.ClientBuilder {
+	if discovery == nil {
+		panic(errors.New("Discovery is required"))
+	}
+	if refresh <= 0 {
+		panic(errors.New("Refresh interval must be positive"))
+	}
+	b.endpoints = func() endpointSelector {
+		return newDiscoveryBalancer(discovery, strategy, refresh)
+	}
+	return b
+}
+
+/**
+ * Caps the combined throughput of all response bodies read through the
+ * client. burst is the largest chunk read at once.
+ */
+func (b *clientBuilder) WithDownloadRate(bytesPerSecond int, burst int) model.ClientBuilder {
+	b.downloadRate = newByteRate(bytesPerSecond, burst)
+	return b
+}
+
+/**
+ * Sets an ordered list of base URLs for requests with relative URLs. On
+ * transport errors or 5xx responses the next endpoint is tried; requests
+ * that are not idempotent only move on when the endpoint could not be
+ * reached. Requests stick to the last endpoint that answered successfully.
+ * Replaces any balancer set with WithBalancer.
+ */
+func (b *clientBuilder) WithEndpoints(baseURLs ...string) model.ClientBuilder {
+	// bad URLs fail here, not when the client is built
+	newEndpoints(baseURLs)
+	baseURLs = append([]string(nil), baseURLs...)
+	b.endpoints = func() endpointSelector {
+		return newEndpoints(baseURLs)
+	}
+	return b
+}
+
+/**
+ * Publishes the client's request, error, byte and in-flight counters as an
+ * expvar map under name. Clients built with the same name share the map and
+ * add up their counters; closing a client removes it from in_flight. Panics
+ * if name was published by other means.
+ */
+func (b *clientBuilder) WithExpvar(name string) model.ClientBuilder {
+	b.expvarName = name
+	return b
+}
+
+/**
+ * Injects latency, refused connections or error statuses into a share of
+ * the requests, for resilience testing. Never enable it in production.
+ */
+func (b *clientBuilder) WithFaultInjection(faults ...model.Fault) model.ClientBuilder {
+	for _, fault := range faults {
+		if fault.Percentage < 0 || fault.Percentage > 100 {
+			panic(errors.New("Fault percentage must be between 0 and 100"))
+		}
+	}
+	b.faults = append(b.faults, faults...)
+	return b
+}
+
+/**
+ * Probes the endpoints set with WithEndpoints, WithBalancer or WithDiscovery
+ * periodically, steering requests away from endpoints failing the checks.
+ */
+func (b *clientBuilder) WithHealthCheck(check model.HealthCheck) model.ClientBuilder {
+	b.healthCheck = &check
+	return b
+}
+
+/**
+ * Sets the hedging settings of requests that do not define their own.
+ */
+func (b *clientBuilder) WithHedging(hedge model.Hedge) model.ClientBuilder {
+	b.hedge = &hedge
+	return b
+}
+
+/**
+ * Registers hooks run around every request of the client. May be called
+ * several times; hooks of the same kind run in registration order.
+ */
+func (b *clientBuilder) WithHooks(hooks model.Hooks) model.ClientBuilder {
+	b.hooks = append(b.hooks, hooks)
+	return b
+}
+
+/**
+ * Adopts an existing http.Client: its transport (proxy, instrumentation...),
+ * cookie jar and timeout are kept, and its CheckRedirect runs after the
+ * redirect policies of the package. As with WithTransport, the connection and
+ * TLS settings of the builder are ignored. httpClient itself is not modified.
+ */
+func (b *clientBuilder) WithHttpClient(httpClient *http.Client) model.ClientBuilder {
+	if httpClient == nil {
+		panic(errors.New("HTTP client cannot be nil"))
+	}
+	b.httpClient = httpClient
+	b.timeout = httpClient.Timeout
+	b.transport = httpClient.Transport
+	if b.transport == nil {
+		b.transport = http.DefaultTransport
+	}
+	return b
+}
+
+/**
+ * Binds outgoing connections to the address of the named network interface.
+ */
+func (b *clientBuilder) WithInterface(name string) model.ClientBuilder {
+	b.iface = name
+	return b
+}
+
+/**
+ * Sets the interval between TCP keepalive probes on idle connections, which
+ * keeps NAT devices from silently dropping them. A negative value disables
+ * keepalives.
+ */
+func (b *clientBuilder) WithKeepAlive(interval time.Duration) model.ClientBuilder {
+	b.dialer.keepAlive = interval
+	return b
+}
+
+/**
+ * Tracks per-host latency percentiles and error rates over a sliding window,
+ * available through Client.LatencyStats. Zero means one minute. Enabled
+ * implicitly by the BalanceLowestLatency strategy.
+ */
+func (b *clientBuilder) WithLatencyStats(window time.Duration) model.ClientBuilder {
+	if window < 0 {
+		panic(errors.New("Window cannot be negative"))
+	}
+	b.latencyWindow = &window
+	return b
+}
+
+/**
+ * Binds outgoing connections to the given local IP address, e.g. to pick the
+ * egress address on a multi-homed host.
+ */
+func (b *clientBuilder) WithLocalAddr(addr string) model.ClientBuilder {
+	b.localAddr = addr
+	return b
+}
+
+/**
+ * Logs every exchange (each attempt of each request) to logging.Logger,
+ * redacting credentials and the configured headers. May be called several
+ * times, e.g. to feed a HAR recorder next to the application log.
+ */
+func (b *clientBuilder) WithLogging(logging model.Logging) model.ClientBuilder {
+	if logging.Logger == nil {
+		panic(errors.New("Logger is required"))
+	}
+	b.loggers = append(b.loggers, newRequestLogger(logging))
+	return b
+}
+
+/**
+ * Limits the number of requests in flight across the client. Up to queueSize
+ * requests over the limit wait for a free slot; further requests fail with a
+ * *model.QueueFullError.
+ */
+func (b *clientBuilder) WithMaxConcurrentRequests(limit int, queueSize int) model.ClientBuilder {
+	if limit <= 0 {
+		panic(errors.New("Limit must be a positive number of requests"))
+	}
+	if queueSize < 0 {
+		panic(errors.New("Queue size cannot be negative"))
+	}
+	b.concurrency = &concurrencyLimit{limit: limit, queueSize: queueSize}
+	return b
+}
+
+/**
+ * Limits the number of concurrent requests per destination host. Requests
+ * over the limit wait or fail with a *model.HostLimitError, per policy.
+ */
+func (b *clientBuilder) WithMaxRequestsPerHost(limit int, policy model.OverflowPolicy) model.ClientBuilder {
+	if limit <= 0 {
+		panic(errors.New("Limit must be a positive number of requests"))
+	}
+	b.hostLimit = &hostLimit{limit: limit, policy: policy}
+	return b
+}
+
+func (b *clientBuilder) WithMaxTLSVersion(version uint16) model.ClientBuilder {
+	b.maxTLSVersion = version
+	return b
+}
+
+/**
+ * Bounds the responses memoized by RequestBuilder.WithCacheTTL, the least
+ * recently used ones being evicted first. Defaults to 1024.
+ */
+func (b *clientBuilder) WithMemoizationLimit(maxEntries int) model.ClientBuilder {
+	if maxEntries < 1 {
+		panic(errors.New("Memoization limit must be positive"))
+	}
+	b.memoEntries = maxEntries
+	return b
+}
+
+/**
+ * Reports request counts, durations, body sizes and requests in flight to
+ * collector, e.g. a Prometheus or statsd adapter.
+ */
+func (b *clientBuilder) WithMetrics(collector model.MetricsCollector) model.ClientBuilder {
+	if collector == nil {
+		panic(errors.New("Metrics collector is required"))
+	}
+	b.metrics = newMetricsReporter(collector)
+	return b
+}
+
+func (b *clientBuilder) WithMinTLSVersion(version uint16) model.ClientBuilder {
+	b.minTLSVersion = version
+	return b
+}
+
+/**
+ * Sets TCP_NODELAY on new connections. Go enables it by default; passing
+ * false turns Nagle's algorithm back on.
+ */
+func (b *clientBuilder) WithNoDelay(noDelay bool) model.ClientBuilder {
+	b.dialer.noDelay = &noDelay
+	return b
+}
+
+/**
+ * Reports every exchange to observer. May be called several times.
+ */
+func (b *clientBuilder) WithObserver(observer model.Observer) model.ClientBuilder {
+	if observer == nil {
+		panic(errors.New("Observer is required"))
+	}
+	b.observers = append(b.observers, observer)
+	return b
+}
+
+/**
+ * Refuses network access: requests are only answered by replay, e.g. a
+ * requestmock.Recorder replaying a cassette or a requestmock.Mock, and fail
+ * with its error when it has no answer; with a nil replay every request
+ * fails with model.ErrOffline. Takes precedence over WithTransport. A replay
+ * transport reporting that it records (Recording() bool) is refused.
+ */
+func (b *clientBuilder) WithOffline(replay http.RoundTripper) model.ClientBuilder {
+	if recorder, ok := replay.(interface{ Recording() bool }); ok && recorder.Recording() {
+		panic(errors.New("Offline clients cannot use a recording transport"))
+	}
+	b.offline = &offlineTransport{replay: replay}
+	return b
+}
+
+/**
+ * Pins the public keys accepted from host. Passing several pins allows
+ * backup keys; the handshake succeeds if any of them is part of the verified
+ * chain.
+ */
+func (b *clientBuilder) WithPinnedKeys(host string, pins ...string) model.ClientBuilder {
+	if b.pins == nil {
+		b.pins = make(map[string][]string)
+	}
+	b.pins[host] = append(b.pins[host], pins...)
+	return b
+}
+
+/**
+ * Reports pinning failures to reporter instead of aborting the handshake.
+ */
+func (b *clientBuilder) WithPinningReportOnly(reporter model.PinningReporter) model.ClientBuilder {
+	b.pinReporter = reporter
+	return b
+}
+
+/**
+ * Labels the goroutines running each request with its host and method
+ * (gorequest.host, gorequest.method), so that CPU and heap profiles
+ * attribute their cost to upstream calls; see runtime/pprof.
+ */
+func (b *clientBuilder) WithProfilerLabels() model.ClientBuilder {
+	b.profilerLabels = true
+	return b
+}
+
+/**
+ * Limits the rate of requests sent to host (a host name, without port) to
+ * requestsPerSecond, allowing bursts of up to burst requests. Requests over
+ * the limit are delayed or fail with a *model.RateLimitError, per policy.
+ */
+func (b *clientBuilder) WithRateLimit(host string, requestsPerSecond float64, burst int, policy model.OverflowPolicy) model.ClientBuilder {
+	if requestsPerSecond <= 0 {
+		panic(errors.New("Rate must be a positive number of requests per second"))
+	}
+	b.rateLimits = append(b.rateLimits, rateLimit{burst: burst, host: host, policy: policy, requestsPerSecond: requestsPerSecond})
+	return b
+}
+
+/**
+ * Paces requests by the quota hosts announce (see Response.RateLimit): once
+ * a host reports reserve requests or fewer left in its window, requests to
+ * it are spaced evenly over the rest of the window, and held until the
+ * reset once none are left. Requests whose turn is more than maxWait away
+ * fail with a *model.QuotaExhaustedError instead; zero means one minute, a
+ * negative value waits as long as it takes.
+ */
+func (b *clientBuilder) WithRateLimitPacing(reserve int, maxWait time.Duration) model.ClientBuilder {
+	if reserve < 0 {
+		panic(errors.New("Rate limit reserve cannot be negative"))
+	}
+	if maxWait == 0 {
+		maxWait = defaultMaxRetryAfter
+	}
+	b.quotaPacing = &quotaPacing{maxWait: maxWait, reserve: int64(reserve)}
+	return b
+}
+
+/**
+ * Sets the header forwarding rules of requests that do not define their own.
+ */
+func (b *clientBuilder) WithRedirectHeaders(rules model.RedirectHeaders) model.ClientBuilder {
+	b.redirectHeaders = &rules
+	return b
+}
+
+/**
+ * Sets the redirect policy of requests that do not define their own.
+ */
+func (b *clientBuilder) WithRedirectPolicy(policy model.RedirectPolicy) model.ClientBuilder {
+	b.redirectPolicy = policy
+	return b
+}
+
+/**
+ * Sets the retry settings of requests that do not define their own.
+ */
+func (b *clientBuilder) WithRetry(retry model.Retry) model.ClientBuilder {
+	b.retry = &retry
+	return b
+}
+
+/**
+ * Limits the extra load generated by retries across all requests of the
+ * client. Skipped retries are counted in the client's RetryStats.
+ */
+func (b *clientBuilder) WithRetryBudget(budget model.RetryBudget) model.ClientBuilder {
+	newRetryBudget(budget)
+	b.retryBudget = &budget
+	return b
+}
+
+/**
+ * Appends PEM encoded CA certificates to the system trust store used by the
+ * client's transport. The global system store itself is never modified.
+ */
+func (b *clientBuilder) WithRootCAs(pem []byte) model.ClientBuilder {
+	b.rootCAs = append(b.rootCAs, pem)
+	return b
+}
+
+/**
+ * Appends every .pem, .crt and .cer file found in dir to the trust store.
+ */
+func (b *clientBuilder) WithRootCADirectory(dir string) model.ClientBuilder {
+	b.rootCADirs = append(b.rootCADirs, dir)
+	return b
+}
+
+func (b *clientBuilder) WithRootCAFile(path string) model.ClientBuilder {
+	b.rootCAFiles = append(b.rootCAFiles, path)
+	return b
+}
+
+/**
+ * Fails requests to hosts with a label mixing scripts, e.g. a Cyrillic "а"
+ * among Latin letters, with a *model.MixedScriptError before they are sent.
+ */
+func (b *clientBuilder) WithStrictIDNA() model.ClientBuilder {
+	b.strictIDNA = true
+	return b
+}
+
+func (b *clientBuilder) WithTimeout(timeout time.Duration) model.ClientBuilder {
+	b.timeout = timeout
+	return b
+}
+
+/**
+ * Configures how the trace context of requests is propagated. Without it,
+ * requests whose context was set up with ContextWithTrace are still sent
+ * with traceparent and tracestate headers.
+ */
+func (b *clientBuilder) WithTracePropagation(propagation model.TracePropagation) model.ClientBuilder {
+	b.tracePropagation = &propagation
+	return b
+}
+
+/**
+ * Sends requests through transport instead of one built from the connection
+ * and TLS settings of the builder, which are then ignored; e.g. to stub the
+ * network in tests with the requestmock package. Connection statistics and
+ * per-request TLS settings are not available with a custom transport.
+ */
+func (b *clientBuilder) WithTransport(transport http.RoundTripper) model.ClientBuilder {
+	if transport == nil {
+		panic(errors.New("Transport cannot be nil"))
+	}
+	b.transport = transport
+	return b
+}
+
+/**
+ * Caps the combined throughput of all request bodies sent through the
+ * client. burst is the largest chunk sent at once.
+ */
+func (b *clientBuilder) WithUploadRate(bytesPerSecond int, burst int) model.ClientBuilder {
+	b.uploadRate = newByteRate(bytesPerSecond, burst)
+	return b
+}
+
+func (b *clientBuilder) localIP() net.IP {
+	if b.localAddr != "" {
+		return parseLocalAddr(b.localAddr)
+	}
+	if b.iface != "" {
+		return interfaceAddr(b.iface)
+	}
+	return nil
+}
+
+/**
+ * Returns the custom transport, if any, or a transport built from the
+ * connection and TLS settings.
+ */
+func (b *clientBuilder) roundTripper() http.RoundTripper {
+
+	if b.offline != nil {
+		return b.offline
+	}
+
+	if b.transport != nil {
+		return b.transport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	b.dialer.localAddr = b.localIP()
+	transport.DialContext = b.dialer.dialContext()
+	transport.TLSClientConfig = b.tlsConfig()
+
+	return transport
+}
+
+/**
+ * Cipher suites only apply to TLS 1.0-1.2; the TLS 1.3 suites are not
+ * configurable in crypto/tls.
+ */
+func (b *clientBuilder) tlsConfig() *tls.Config {
+
+	config := &tls.Config{
+		CipherSuites:     b.cipherSuites,
+		CurvePreferences: b.curvePreferences,
+		MaxVersion:       b.maxTLSVersion,
+		MinVersion:       b.minTLSVersion,
+	}
+
+	if len(b.rootCAs) > 0 || len(b.rootCAFiles) > 0 || len(b.rootCADirs) > 0 {
+		bundles := make(map[string][]byte)
+		for i, pem := range b.rootCAs {
+			bundles[fmt.Sprintf("root CA bundle #%d", i+1)] = pem
+		}
+		for _, path := range b.rootCAFiles {
+			bundles[path] = readRootCAFile(path)
+		}
+		for _, dir := range b.rootCADirs {
+			for path, pem := range readRootCADirectory(dir) {
+				bundles[path] = pem
+			}
+		}
+		config.RootCAs = newRootCAPool(bundles)
+	}
+
+	if len(b.pins) > 0 {
+		config.VerifyConnection = newPinSet(b.pins, b.pinReporter).verify
+	}
+
+	return config
+}
+
+func (b *clientBuilder) validate() {
+
+	if b.localAddr != "" && b.iface != "" {
+		panic(errors.New("Local address and interface are mutually exclusive"))
+	}
+
+	if b.healthCheck != nil && b.endpoints == nil {
+		panic(errors.New("Health checks require endpoints"))
+	}
+
+	if b.timeout < 0 {
+		panic(errors.New("Timeout cannot be negative"))
+	}
+
+	if b.minTLSVersion != 0 && b.maxTLSVersion != 0 && b.minTLSVersion > b.maxTLSVersion {
+		panic(errors.New("Minimum TLS version cannot be greater than the maximum TLS version"))
+	}
+
+	for _, version := range []uint16{b.minTLSVersion, b.maxTLSVersion} {
+		switch version {
+		case 0, tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+		default:
+			panic(fmt.Errorf("Unsupported TLS version: 0x%04x", version))
+		}
+	}
+
+	known := make(map[uint16]bool)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[suite.ID] = true
+	}
+	for _, id := range b.cipherSuites {
+		if !known[id] {
+			panic(fmt.Errorf("Unsupported cipher suite: 0x%04x", id))
+		}
+	}
+}
+
+func newByteRate(bytesPerSecond int, burst int) *byteRate {
+	if bytesPerSecond <= 0 {
+		panic(errors.New("Rate must be a positive number of bytes per second"))
+	}
+	return &byteRate{burst: burst, bytesPerSecond: bytesPerSecond}
+}
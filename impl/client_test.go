@@ -0,0 +1,610 @@
+package gorequest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientBuilderTLSSettings(t *testing.T) {
+	c := NewClientBuilder().
+		WithMinTLSVersion(tls.VersionTLS12).
+		WithMaxTLSVersion(tls.VersionTLS13).
+		WithCipherSuites(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256).
+		WithCurvePreferences(tls.X25519).
+		Build()
+
+	config := c.(*client).httpClient.Transport.(*http.Transport).TLSClientConfig
+
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion, "Should equal TLS 1.2")
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MaxVersion, "Should equal TLS 1.3")
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, config.CipherSuites, "Should equal cipher suites")
+	assert.Equal(t, []tls.CurveID{tls.X25519}, config.CurvePreferences, "Should equal curve preferences")
+}
+
+func TestClientBuilderInvalidTLSVersionRange(t *testing.T) {
+	defer func() {
+		err := recover().(error)
+
+		assert.NotNil(t, err, "Should not be nil")
+		assert.Equal(t, "Minimum TLS version cannot be greater than the maximum TLS version", err.Error(), "Should equal error message")
+	}()
+
+	NewClientBuilder().WithMinTLSVersion(tls.VersionTLS13).WithMaxTLSVersion(tls.VersionTLS12).Build()
+
+	assert.True(t, false, "Should not have completed test")
+}
+
+func TestClientBuilderUnknownCipherSuite(t *testing.T) {
+	defer func() {
+		err := recover().(error)
+
+		assert.NotNil(t, err, "Should not be nil")
+		assert.Equal(t, "Unsupported cipher suite: 0xffff", err.Error(), "Should equal error message")
+	}()
+
+	NewClientBuilder().WithCipherSuites(0xffff).Build()
+
+	assert.True(t, false, "Should not have completed test")
+}
+
+func TestTLSVersionEnforced(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+	ts.TLS = &tls.Config{MinVersion: tls.VersionTLS13}
+	ts.StartTLS()
+
+	defer ts.Close()
+
+	defer func() {
+		err := recover()
+		assert.NotNil(t, err, "Should not be nil")
+	}()
+
+	c := NewClientBuilder().WithMaxTLSVersion(tls.VersionTLS12).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.True(t, false, "Should not have completed test")
+}
+
+func TestInsecureSkipVerifyIsPerRequest(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithInsecureSkipVerify().Build().Do()
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+	assert.True(t, response.InsecureSkipVerify(), "Should be tagged as insecure")
+	assert.False(t, c.HttpClient().Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify, "Should not modify the shared transport")
+}
+
+func TestRootCAsTrustPrivateCertificate(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	c := NewClientBuilder().WithRootCAs(bundle).Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+	assert.False(t, response.InsecureSkipVerify(), "Should not be tagged as insecure")
+}
+
+func TestRootCAsInvalidBundle(t *testing.T) {
+	defer func() {
+		err := recover().(error)
+
+		assert.NotNil(t, err, "Should not be nil")
+		assert.Equal(t, "No PEM encoded certificates found in root CA bundle #1", err.Error(), "Should equal error message")
+	}()
+
+	NewClientBuilder().WithRootCAs([]byte("not a certificate")).Build()
+
+	assert.True(t, false, "Should not have completed test")
+}
+
+func TestPinnedKeysWithBackupPin(t *testing.T) {
+	state := tls.ConnectionState{
+		ServerName: "example.com",
+		VerifiedChains: [][]*x509.Certificate{{
+			{RawSubjectPublicKeyInfo: []byte("leaf")},
+			{RawSubjectPublicKeyInfo: []byte("intermediate")},
+		}},
+	}
+
+	primary := newPinSet(map[string][]string{"Example.com": {"sha256/" + spkiPin([]byte("leaf"))}}, nil)
+	backup := newPinSet(map[string][]string{"example.com": {spkiPin([]byte("retired")), spkiPin([]byte("intermediate"))}}, nil)
+
+	assert.Nil(t, primary.verify(state), "Should accept the pinned leaf key")
+	assert.Nil(t, backup.verify(state), "Should accept a pinned key anywhere in the chain")
+}
+
+func TestPinningReportOnly(t *testing.T) {
+	state := tls.ConnectionState{
+		ServerName:       "example.com",
+		PeerCertificates: []*x509.Certificate{{RawSubjectPublicKeyInfo: []byte("presented")}},
+	}
+
+	var reported *model.PinningError
+
+	enforced := newPinSet(map[string][]string{"example.com": {spkiPin([]byte("pinned"))}}, nil)
+	reportOnly := newPinSet(map[string][]string{"example.com": {spkiPin([]byte("pinned"))}}, func(err *model.PinningError) {
+		reported = err
+	})
+
+	assert.NotNil(t, enforced.verify(state), "Should reject the connection")
+	assert.Nil(t, reportOnly.verify(state), "Should accept the connection")
+	assert.Equal(t, "example.com", reported.Host, "Should report the host")
+	assert.Nil(t, enforced.verify(tls.ConnectionState{ServerName: "other.com"}), "Should accept hosts without pins")
+}
+
+func TestPinningIgnoresUnverifiedCertificates(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), NotAfter: time.Now().Add(time.Hour)}
+	der, _ := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	pinned, _ := x509.ParseCertificate(der)
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	ts.StartTLS()
+
+	defer ts.Close()
+
+	// the server appends the pinned certificate to its own, trusted chain
+	ts.TLS.Certificates[0].Certificate = append(ts.TLS.Certificates[0].Certificate, der)
+
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+
+	c := NewClientBuilder().WithRootCAs(bundle).WithPinnedKeys("example.com", spkiPin(pinned.RawSubjectPublicKeyInfo)).Build()
+
+	_, err := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithServerName("example.com").Build().Send()
+
+	var pinningErr *model.PinningError
+
+	assert.True(t, errors.As(err, &pinningErr), "Should fail the handshake")
+}
+
+func TestServerNameOverride(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, req.TLS.ServerName)
+	}))
+
+	defer ts.Close()
+
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	pin := spkiPin(ts.Certificate().RawSubjectPublicKeyInfo)
+
+	c := NewClientBuilder().WithRootCAs(bundle).WithPinnedKeys("example.com", pin).Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithServerName("example.com").Build().Do()
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+	assert.Equal(t, "example.com", string(response.Body()), "Should present the overridden server name")
+}
+
+func TestConnectionStats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().Build()
+
+	for i := 0; i < 3; i++ {
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+	}
+
+	stats := c.ConnectionStats()
+	host := stats.Hosts[strings.TrimPrefix(ts.URL, "http://")]
+
+	assert.Equal(t, int64(1), stats.Dialed, "Should dial a single connection")
+	assert.Equal(t, int64(2), stats.Reused, "Should reuse the connection")
+	assert.Equal(t, int64(1), stats.Open, "Should keep the connection open")
+	assert.Equal(t, int64(1), stats.Idle, "Should park the connection in the idle pool")
+	assert.Equal(t, stats.HostConnectionStats, host, "Should attribute everything to the test server")
+
+	c.HttpClient().CloseIdleConnections()
+
+	stats = c.ConnectionStats()
+
+	assert.Equal(t, int64(0), stats.Open, "Should have closed the connection")
+	assert.Equal(t, int64(0), stats.Idle, "Should have emptied the idle pool")
+}
+
+func TestClientTrace(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	var connected, firstByte bool
+
+	trace := &httptrace.ClientTrace{
+		ConnectDone: func(network, addr string, err error) {
+			connected = err == nil
+		},
+		GotFirstResponseByte: func() {
+			firstByte = true
+		},
+	}
+
+	c := NewClientBuilder().Build()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithClientTrace(trace).Build().Do()
+
+	assert.True(t, connected, "Should observe the connection")
+	assert.True(t, firstByte, "Should observe the first response byte")
+	assert.Equal(t, int64(1), c.ConnectionStats().Dialed, "Should keep the internal trace working")
+}
+
+func TestLocalAddr(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		host, _, _ := net.SplitHostPort(req.RemoteAddr)
+		fmt.Fprint(resp, host)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithLocalAddr("127.0.0.1").Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, "127.0.0.1", string(response.Body()), "Should connect from the bound address")
+}
+
+func TestInvalidLocalAddr(t *testing.T) {
+	defer func() {
+		err := recover().(error)
+
+		assert.NotNil(t, err, "Should not be nil")
+		assert.Equal(t, "Invalid local address: not-an-ip", err.Error(), "Should equal error message")
+	}()
+
+	NewClientBuilder().WithLocalAddr("not-an-ip").Build()
+
+	assert.True(t, false, "Should not have completed test")
+}
+
+func TestCloseDrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(resp, "done")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().Build()
+
+	body := make(chan string)
+	go func() {
+		body <- string(NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do().Body())
+	}()
+
+	<-started
+
+	assert.Nil(t, c.Close(context.Background()), "Should drain without error")
+	assert.Equal(t, "done", <-body, "Should let the request in flight complete")
+
+	defer func() {
+		err := recover()
+
+		assert.Equal(t, model.ErrClientClosed, err, "Should reject new requests")
+	}()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+}
+
+func TestCloseDeadline(t *testing.T) {
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+
+	defer ts.Close()
+	defer close(release)
+
+	c := NewClientBuilder().Build()
+
+	go NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	for c.ConnectionStats().Open == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, c.Close(ctx), "Should give up waiting at the deadline")
+}
+
+func TestTimings(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().Build()
+
+	timings := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithInsecureSkipVerify().Build().Do().Timings()
+
+	assert.False(t, timings.ConnectionReused, "Should open a new connection")
+	assert.True(t, timings.Connect > 0, "Should measure the connection")
+	assert.True(t, timings.TLSHandshake > 0, "Should measure the TLS handshake")
+	assert.True(t, timings.TimeToFirstByte >= 20*time.Millisecond, "Should measure the time to first byte")
+	assert.True(t, timings.Total >= timings.Connect+timings.TLSHandshake+timings.TimeToFirstByte, "Should measure the total")
+
+	timings = NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithInsecureSkipVerify().Build().Do().Timings()
+
+	assert.True(t, timings.ConnectionReused, "Should reuse the connection")
+	assert.Equal(t, time.Duration(0), timings.TLSHandshake, "Should not handshake again")
+}
+
+func TestResponseStreaming(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 && req.URL.Path == "/flaky" {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(resp, "unavailable")
+			return
+		}
+		fmt.Fprint(resp, strings.Repeat("x", 1<<16))
+	}))
+
+	defer ts.Close()
+
+	response := NewRequestBuilder().WithUrl(ts.URL + "/large").WithResponseStream().Build().Do()
+
+	assert.Nil(t, response.Body(), "Should not buffer the body")
+
+	data, err := ioutil.ReadAll(response.Response().Body)
+	response.Response().Body.Close()
+
+	assert.Nil(t, err, "Should read the open body")
+	assert.Equal(t, 1<<16, len(data), "Should stream the whole body")
+
+	atomic.StoreInt32(&calls, 0)
+
+	var sink bytes.Buffer
+
+	response = NewRequestBuilder().
+		WithUrl(ts.URL + "/flaky").
+		WithRetry(model.Retry{MaxAttempts: 2, BaseDelay: time.Millisecond}).
+		WithResponseSink(&sink).
+		Build().
+		Do()
+
+	assert.Equal(t, http.StatusOK, response.Response().StatusCode, "Should retry")
+	assert.Nil(t, response.Body(), "Should not buffer the body")
+	assert.Equal(t, strings.Repeat("x", 1<<16), sink.String(), "Should only copy the final body to the sink")
+}
+
+func TestWarmup(t *testing.T) {
+	var requests int32
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithRootCAs(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})).Build()
+
+	assert.Nil(t, c.Warmup(context.Background(), ts.URL), "Should warm up the connection")
+
+	var reused bool
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused },
+	}
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithClientTrace(trace).Build().Do()
+
+	assert.True(t, reused, "Should reuse the warmed up connection")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests), "Should send a single request to warm up")
+
+	err := c.Warmup(context.Background(), "127.0.0.1:1")
+
+	assert.Contains(t, err.Error(), "Warmup failed for 127.0.0.1:1", "Should report the hosts that failed")
+}
+
+type headerTransport struct {
+	next http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Instrumented", "yes")
+	return t.next.RoundTrip(req)
+}
+
+func TestWrapHttpClient(t *testing.T) {
+	var attempts int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/moved" {
+			http.Redirect(resp, req, "/elsewhere", http.StatusFound)
+			return
+		}
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(resp, req.Header.Get("X-Instrumented"))
+	}))
+
+	defer ts.Close()
+
+	var redirects int32
+
+	httpClient := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			atomic.AddInt32(&redirects, 1)
+			return http.ErrUseLastResponse
+		},
+		Timeout:   5 * time.Second,
+		Transport: headerTransport{next: http.DefaultTransport},
+	}
+
+	c := NewClientBuilder().WithHttpClient(httpClient).WithRetry(model.Retry{MaxAttempts: 2, BaseDelay: time.Millisecond}).Build()
+
+	resp := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, "yes", string(resp.Body()), "Should send requests through the existing transport")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts), "Should retry as configured on the builder")
+	assert.Equal(t, 5*time.Second, c.HttpClient().Timeout, "Should keep the timeout")
+
+	resp = NewRequestBuilder().WithUrl(ts.URL + "/moved").WithClient(WrapHttpClient(httpClient)).Build().Do()
+
+	assert.Equal(t, http.StatusFound, resp.Response().StatusCode, "Should run the existing CheckRedirect")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&redirects), "Should run the existing CheckRedirect")
+}
+
+func TestClone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(resp, "%s %s %s", req.URL.Path, req.Header.Get("Authorization"), req.Header.Get("X-Tenant"))
+	}))
+
+	defer ts.Close()
+
+	base := NewClientBuilder().WithEndpoints("http://127.0.0.1:1").Build()
+
+	var tenants []model.Client
+
+	for _, tenant := range []string{"a", "b"} {
+		tenants = append(tenants, base.Clone(model.ClientOverrides{
+			Auth:    newAuthBearer("token-" + tenant),
+			BaseURL: ts.URL,
+			Headers: map[string]string{"X-Tenant": tenant},
+			Timeout: time.Second,
+		}))
+	}
+
+	resp := NewRequestBuilder().WithUrl("/users").WithClient(tenants[0]).Build().Do()
+
+	assert.Equal(t, "/users Bearer token-a a", string(resp.Body()), "Should apply the overrides")
+
+	resp = NewRequestBuilder().WithUrl("/users").WithHeader("X-Tenant", "c").WithBearerAuth("own").WithClient(tenants[1]).Build().Do()
+
+	assert.Equal(t, "/users Bearer own c", string(resp.Body()), "Should not override the request")
+
+	assert.Equal(t, time.Second, tenants[0].HttpClient().Timeout, "Should override the timeout")
+	assert.Equal(t, base.HttpClient().Transport, tenants[0].HttpClient().Transport, "Should share the transport")
+	assert.Equal(t, int64(1), base.ConnectionStats().Reused, "Should share the connection pool")
+
+	assert.Nil(t, tenants[0].Close(context.Background()), "Should close the clone")
+	assert.Equal(t, "/users Bearer token-b b", string(NewRequestBuilder().WithUrl("/users").WithClient(tenants[1]).Build().Do().Body()), "Should not close the original")
+}
+
+type exchangeCollector struct {
+	exchanges []model.Exchange
+}
+
+func (c *exchangeCollector) ObserveExchange(exchange model.Exchange) {
+	c.exchanges = append(c.exchanges, exchange)
+}
+
+func TestTransportErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+
+	defer ts.Close()
+
+	observer := &exchangeCollector{}
+	var hooked []error
+
+	c := NewClientBuilder().
+		WithTimeout(20 * time.Millisecond).
+		WithCircuitBreaker(model.CircuitBreaker{FailureRate: 0.5, MinRequests: 10, OpenTimeout: time.Second}).
+		WithObserver(observer).
+		WithLatencyStats(time.Minute).
+		WithHooks(model.Hooks{OnError: func(req *http.Request, err error) { hooked = append(hooked, err) }}).
+		WithRetry(model.Retry{MaxAttempts: 1}).
+		Build()
+
+	for _, target := range []string{ts.URL, "http://gorequest.invalid/", ts.URL + "/stream"} {
+		var debug bytes.Buffer
+
+		builder := NewRequestBuilder().WithUrl(target).WithClient(c).WithDebug(&debug, true)
+		if strings.HasSuffix(target, "/stream") {
+			builder.WithResponseStream()
+		}
+
+		resp, err := builder.Build().Send()
+
+		assert.Nil(t, resp, "Should not have a response")
+		assert.NotNil(t, err, "Should return the transport error of "+target)
+		assert.Contains(t, debug.String(), "* Error after", "Should log the failed exchange")
+	}
+
+	assert.Equal(t, 3, len(observer.exchanges), "Should observe every exchange")
+	assert.Equal(t, 3, len(hooked), "Should run the error hooks")
+
+	var netErr net.Error
+
+	assert.True(t, errors.As(hooked[0], &netErr) && netErr.Timeout(), "Should report the timeout")
+}
+
+func TestTransportErrorWithoutResponse(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("GET", "/refused").ReplyError(syscall.ECONNREFUSED)
+
+	c := NewClientBuilder().WithTransport(mock).WithRetry(model.Retry{MaxAttempts: 1}).Build()
+
+	for _, stream := range []bool{false, true} {
+		builder := NewRequestBuilder().WithUrl("https://api.example.com/refused").WithClient(c)
+		if stream {
+			builder.WithResponseStream()
+		}
+
+		var resp model.Response
+		var err error
+
+		assert.NotPanics(t, func() { resp, err = builder.Build().Send() }, "Should not close the body of a missing response")
+		assert.Nil(t, resp, "Should not have a response")
+		assert.True(t, errors.Is(err, syscall.ECONNREFUSED), "Should return the transport error, got %v", err)
+	}
+}
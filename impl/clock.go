@@ -0,0 +1,18 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * The model.Clock of the time package.
+ */
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
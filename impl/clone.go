@@ -0,0 +1,100 @@
+package gorequest
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+/**
+ * The clone shares the transport, and therefore the connection pool, along
+ * with hooks, observers, limiters, circuit breakers and statistics. It gets
+ * its own memoized responses and deduplicated flights, which could otherwise
+ * leak between clones sending different credentials, and its own transports
+ * for per-request TLS settings.
+ */
+func (c *client) Clone(overrides model.ClientOverrides) model.Client {
+
+	clone := &client{
+		auditor:          c.auditor,
+		auth:             c.auth,
+		breakers:         c.breakers,
+		bulkheads:        c.bulkheads,
+		canonicalQuery:   c.canonicalQuery,
+		clock:            c.clock,
+		concurrency:      c.concurrency,
+		contextHeaders:   c.contextHeaders,
+		downloadRate:     c.downloadRate,
+		endpoints:        c.endpoints,
+		faults:           c.faults,
+		hedge:            c.hedge,
+		hooks:            c.hooks,
+		hostLimiter:      c.hostLimiter,
+		httpClient:       c.httpClient,
+		latency:          c.latency,
+		loggers:          c.loggers,
+		memo:             newMemoizer(c.memo.maxEntries),
+		metrics:          c.metrics,
+		observers:        c.observers,
+		parent:           c,
+		profilerLabels:   c.profilerLabels,
+		quotaPacer:       c.quotaPacer,
+		rateLimiter:      c.rateLimiter,
+		redirectHeaders:  c.redirectHeaders,
+		redirectPolicy:   c.redirectPolicy,
+		retry:            c.retry,
+		retryBudget:      c.retryBudget,
+		retryStats:       c.retryStats,
+		stats:            c.stats,
+		strictIDNA:       c.strictIDNA,
+		tracePropagation: c.tracePropagation,
+		uploadRate:       c.uploadRate,
+		variants:         make(map[tlsVariant]*http.Client),
+	}
+
+	if c.flights != nil {
+		clone.flights = newFlightGroup()
+	}
+
+	if overrides.Auth != nil {
+		clone.auth = overrides.Auth
+	}
+
+	if overrides.BaseURL != "" {
+		clone.endpoints = newEndpoints([]string{overrides.BaseURL})
+	}
+
+	if len(c.headers) > 0 || len(overrides.Headers) > 0 {
+		clone.headers = make(map[string]string, len(c.headers)+len(overrides.Headers))
+		for name, value := range c.headers {
+			clone.headers[name] = value
+		}
+		for name, value := range overrides.Headers {
+			clone.headers[name] = value
+		}
+	}
+
+	if overrides.Timeout > 0 {
+		httpClient := *c.httpClient
+		httpClient.Timeout = overrides.Timeout
+		clone.httpClient = &httpClient
+	}
+
+	return clone
+}
+
+/**
+ * Adds the headers and authorization of the client (set by Clone) to req,
+ * unless it sets them already.
+ */
+func (c *client) applyDefaults(req *http.Request) {
+
+	for name, value := range c.headers {
+		if req.Header.Get(name) == "" {
+			req.Header.Set(name, value)
+		}
+	}
+
+	if c.auth != nil && req.Header.Get("Authorization") == "" {
+		c.auth.Configure(req)
+	}
+}
@@ -0,0 +1,133 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"sync"
+)
+
+/**
+ * Returned by enter when the queue is full; callers turn it into their own
+ * typed error.
+ */
+var errQueueFull = errors.New("Queue full")
+
+/**
+ * Caps the number of requests in flight. Requests over the limit wait in a
+ * bounded queue, by priority; once the queue is full they are rejected.
+ */
+type concurrencyLimiter struct {
+	inFlight  int
+	limit     int
+	mutex     sync.Mutex
+	queueSize int
+	rejected  int64
+	waiting   priorityHeap
+}
+
+func newConcurrencyLimiter(limit int, queueSize int) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		limit:     limit,
+		queueSize: queueSize,
+	}
+}
+
+/**
+ * Takes a slot, queueing if none is free, and returns the function releasing
+ * it. Fails with a *model.QueueFullError when the queue is full, or with the
+ * error of ctx if it is done while queued.
+ */
+func (l *concurrencyLimiter) acquire(ctx context.Context, priority model.Priority) (func(), error) {
+
+	release, err := l.enter(ctx, priority)
+
+	if err == errQueueFull {
+		return nil, &model.QueueFullError{Limit: l.limit, QueueSize: l.queueSize}
+	}
+
+	return release, err
+}
+
+/**
+ * Takes a slot, queueing if none is free. Fails with errQueueFull when the
+ * queue is full, or with the error of ctx if it is done while queued.
+ */
+func (l *concurrencyLimiter) enter(ctx context.Context, priority model.Priority) (func(), error) {
+
+	l.mutex.Lock()
+
+	if l.inFlight < l.limit {
+		l.inFlight++
+		l.mutex.Unlock()
+		return l.releaser(), nil
+	}
+
+	if l.waiting.len() >= l.queueSize {
+		l.rejected++
+		l.mutex.Unlock()
+		return nil, errQueueFull
+	}
+
+	ready := make(chan struct{})
+	l.waiting.push(priority, ready)
+
+	l.mutex.Unlock()
+
+	// the slot is handed over by the request releasing it
+	select {
+	case <-ready:
+		return l.releaser(), nil
+	case <-ctx.Done():
+	}
+
+	l.mutex.Lock()
+
+	if l.waiting.remove(ready) {
+		l.mutex.Unlock()
+		return nil, ctx.Err()
+	}
+
+	l.mutex.Unlock()
+
+	// handed over meanwhile: pass it on
+	l.release()
+
+	return nil, ctx.Err()
+}
+
+func (l *concurrencyLimiter) releaser() func() {
+
+	var once sync.Once
+
+	return func() {
+		once.Do(l.release)
+	}
+}
+
+func (l *concurrencyLimiter) release() {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.waiting.len() > 0 {
+		close(l.waiting.pop().(chan struct{}))
+		return
+	}
+
+	l.inFlight--
+}
+
+func (l *concurrencyLimiter) snapshot() model.BulkheadStats {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return model.BulkheadStats{
+		InFlight:      l.inFlight,
+		Queued:        l.waiting.len(),
+		Rejected:      l.rejected,
+		MaxConcurrent: l.limit,
+		MaxQueue:      l.queueSize,
+	}
+}
@@ -0,0 +1,185 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+)
+
+/**
+ * Tracks the connections of a transport. Dials and closes are observed by
+ * wrapping the transport's DialContext; reuse and idle parking are observed
+ * through an httptrace.ClientTrace attached to every request.
+ */
+type connectionStats struct {
+	hosts map[string]*model.HostConnectionStats
+	mutex sync.Mutex
+}
+
+func newConnectionStats() *connectionStats {
+	return &connectionStats{
+		hosts: make(map[string]*model.HostConnectionStats),
+	}
+}
+
+/**
+ * Installs the dial hook on transport. Must be called before the transport
+ * is used and only on transports owned by this package.
+ */
+func (s *connectionStats) instrument(transport *http.Transport) {
+
+	dial := transport.DialContext
+
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		s.update(addr, func(host *model.HostConnectionStats) {
+			host.Dialed++
+			host.Open++
+		})
+		return &trackedConn{Conn: conn, addr: addr, stats: s}, nil
+	}
+}
+
+/**
+ * Returns the per-request trace feeding reuse and idle counters.
+ */
+func (s *connectionStats) trace() *httptrace.ClientTrace {
+
+	var current *trackedConn
+
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			current = unwrapTrackedConn(info.Conn)
+			if current == nil {
+				return
+			}
+			leftIdle := current.setIdle(false)
+			s.update(current.addr, func(host *model.HostConnectionStats) {
+				if info.Reused {
+					host.Reused++
+				}
+				if leftIdle {
+					host.Idle--
+				}
+			})
+		},
+		PutIdleConn: func(err error) {
+			if current == nil || err != nil {
+				return
+			}
+			if current.setIdle(true) {
+				s.update(current.addr, func(host *model.HostConnectionStats) {
+					host.Idle++
+				})
+			}
+		},
+	}
+}
+
+func (s *connectionStats) snapshot() model.ConnectionStats {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := model.ConnectionStats{
+		Hosts: make(map[string]model.HostConnectionStats, len(s.hosts)),
+	}
+
+	for addr, host := range s.hosts {
+		stats.Hosts[addr] = *host
+		stats.Dialed += host.Dialed
+		stats.Reused += host.Reused
+		stats.Open += host.Open
+		stats.Idle += host.Idle
+	}
+
+	return stats
+}
+
+func (s *connectionStats) update(addr string, change func(host *model.HostConnectionStats)) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	host, ok := s.hosts[addr]
+
+	if !ok {
+		host = &model.HostConnectionStats{}
+		s.hosts[addr] = host
+	}
+
+	change(host)
+}
+
+/****************************************************
+ * net.Conn wrapper
+ ****************************************************/
+
+type trackedConn struct {
+	net.Conn
+	addr   string
+	closed bool
+	idle   bool
+	mutex  sync.Mutex
+	stats  *connectionStats
+}
+
+func (c *trackedConn) Close() error {
+
+	c.mutex.Lock()
+	closed, idle := c.closed, c.idle
+	c.closed, c.idle = true, false
+	c.mutex.Unlock()
+
+	if !closed {
+		c.stats.update(c.addr, func(host *model.HostConnectionStats) {
+			host.Open--
+			if idle {
+				host.Idle--
+			}
+		})
+	}
+
+	return c.Conn.Close()
+}
+
+/**
+ * Sets the idle flag and reports whether it changed. Closed connections
+ * never change state.
+ */
+func (c *trackedConn) setIdle(idle bool) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.closed || c.idle == idle {
+		return false
+	}
+	c.idle = idle
+	return true
+}
+
+/**
+ * TLS connections handed to httptrace wrap the dialed connection.
+ */
+func unwrapTrackedConn(conn net.Conn) *trackedConn {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *trackedConn:
+			return c
+		case interface{ NetConn() net.Conn }:
+			conn = c.NetConn()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package gorequest
 
 import (
+	"context"
 	model "github.com/demianlessa/gorequest/model"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -12,15 +14,68 @@ import (
 func NewRequestBuilder() model.RequestBuilder {
 	return &requestBuilder{
 		auth: newAuthNone(),
+		ctx: context.Background(),
 		headers: make(map[string]string),
 		method: defaultMethod,
 	}
 }
 
+/**
+ * Returns a ClientBuilder whose clients use the package defaults.
+ */
+func NewClientBuilder() model.ClientBuilder {
+	return &clientBuilder{
+		dialer:  newDialerConfig(),
+		timeout: defaultTimeout,
+	}
+}
+
+/**
+ * Returns a Client sending its requests through httpClient; shorthand for
+ * NewClientBuilder().WithHttpClient(httpClient).Build().
+ */
+func WrapHttpClient(httpClient *http.Client) model.Client {
+	return NewClientBuilder().WithHttpClient(httpClient).Build()
+}
+
+/**
+ * Returns a QueueBuilder with the default number of workers and capacity.
+ */
+func NewQueueBuilder() model.QueueBuilder {
+	return &queueBuilder{
+		capacity: defaultQueueCapacity,
+		workers:  defaultQueueWorkers,
+	}
+}
+
+/**
+ * Returns the Client of requests built without one, created on first use.
+ * Safe for concurrent use.
+ */
+func getDefaultClient() *client {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if defaultClient == nil || defaultClient.httpClient != defaultHttpClient() {
+		defaultClient = newClient(defaultHttpClient()).trackConnections()
+	}
+	return defaultClient
+}
+
 func getDefaultHttpClient() *http.Client {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	return defaultHttpClient()
+}
+
+/**
+ * Same as getDefaultHttpClient, with defaultMutex held.
+ */
+func defaultHttpClient() *http.Client {
 	if httpClient == nil {
 		httpClient = &http.Client{
-			Timeout: defaultTimeout,
+			CheckRedirect: checkRedirect,
+			Timeout:       defaultTimeout,
+			Transport:     http.DefaultTransport.(*http.Transport).Clone(),
 		}
 	}
 	return httpClient;
@@ -32,6 +87,13 @@ func getHttpClient(timeout time.Duration) *http.Client {
 	}
 }
 
+/**
+ * The package-level default client and its http.Client, created lazily and
+ * guarded by defaultMutex. Resetting httpClient to nil makes the next use
+ * create new ones.
+ */
+var defaultClient *client
+var defaultMutex sync.Mutex
 var httpClient *http.Client
 var defaultAuthorization model.AuthorizationMethod = newAuthNone()
 var defaultMethod string = "GET"
@@ -0,0 +1,101 @@
+package gorequest
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+/**
+ * A response body decompressed by the client, counting the bytes received.
+ */
+type decodedBody struct {
+	decoded  io.Reader
+	encoded  io.ReadCloser
+	encoding string
+	received int64
+}
+
+func (d *decodedBody) Read(p []byte) (int, error) {
+	return d.decoded.Read(p)
+}
+
+func (d *decodedBody) Close() error {
+	return d.encoded.Close()
+}
+
+/**
+ * Reader of the encoded body, counting what the decompressor consumes.
+ */
+type receivedCounter struct {
+	body *decodedBody
+}
+
+func (c receivedCounter) Read(p []byte) (int, error) {
+	n, err := c.body.encoded.Read(p)
+	atomic.AddInt64(&c.body.received, int64(n))
+	return n, err
+}
+
+/**
+ * Replaces the body of resp by its decompressed content when its
+ * Content-Encoding is gzip or deflate, as the transport does for the gzip
+ * it advertises itself; other encodings are left as received. Returns nil
+ * when the body is not decompressed.
+ */
+func decodeBody(resp *http.Response) (*decodedBody, error) {
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	if encoding != "gzip" && encoding != "x-gzip" && encoding != "deflate" {
+		return nil, nil
+	}
+
+	if resp.ContentLength == 0 || resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified ||
+		resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return nil, nil
+	}
+
+	body := &decodedBody{encoded: resp.Body, encoding: encoding}
+	encoded := bufio.NewReader(receivedCounter{body})
+
+	if encoding == "deflate" {
+		body.decoded = inflate(encoded)
+	} else {
+		decoded, err := gzip.NewReader(encoded)
+		if err != nil {
+			return nil, err
+		}
+		body.decoded = decoded
+	}
+
+	resp.Body = body
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.Uncompressed = true
+
+	return body, nil
+}
+
+/**
+ * Decompresses a deflate body: zlib-wrapped as the HTTP spec has it, or
+ * raw as many servers send it.
+ */
+func inflate(encoded *bufio.Reader) io.Reader {
+
+	header, err := encoded.Peek(2)
+
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		if decoded, err := zlib.NewReader(encoded); err == nil {
+			return decoded
+		}
+	}
+
+	return flate.NewReader(encoded)
+}
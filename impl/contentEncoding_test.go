@@ -0,0 +1,98 @@
+package gorequest
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentEncoding(t *testing.T) {
+	payload := strings.Repeat(`{"name":"gorequest"}`, 50)
+
+	compress := func(encoding string) []byte {
+		var out bytes.Buffer
+		var w io.WriteCloser
+		switch encoding {
+		case "gzip":
+			w = gzip.NewWriter(&out)
+		case "zlib":
+			w = zlib.NewWriter(&out)
+		default:
+			w, _ = flate.NewWriter(&out, flate.DefaultCompression)
+		}
+		w.Write([]byte(payload))
+		w.Close()
+		return out.Bytes()
+	}
+
+	gzipped := compress("gzip")
+	var accepted []string
+	deflate := "zlib"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted = append(accepted, r.Header.Get("Accept-Encoding"))
+		switch {
+		case strings.Contains(r.Header.Get("Accept-Encoding"), "gzip"):
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gzipped)
+		case strings.Contains(r.Header.Get("Accept-Encoding"), "deflate"):
+			w.Header().Set("Content-Encoding", "deflate")
+			w.Write(compress(deflate))
+		default:
+			w.Write([]byte(payload))
+		}
+	}))
+	defer server.Close()
+
+	resp := NewRequest(WithUrl(server.URL)).Do()
+
+	assert.Equal(t, payload, string(resp.Body()), "Should decompress by default")
+	assert.Equal(t, model.ContentEncoding{Decompressed: true, Encoding: "gzip", EncodedSize: -1}, resp.ContentEncoding(), "Should report the decompression of the transport")
+
+	resp = NewRequest(WithUrl(server.URL), WithAcceptEncoding("gzip", "identity;q=0.5")).Do()
+
+	assert.Equal(t, "gzip, identity;q=0.5", accepted[1], "Should advertise the encodings given")
+	assert.Equal(t, payload, string(resp.Body()), "Should decompress gzip bodies")
+	assert.Equal(t, model.ContentEncoding{Decompressed: true, Encoding: "gzip", EncodedSize: int64(len(gzipped))}, resp.ContentEncoding(), "Should report the size received")
+	assert.Empty(t, resp.Response().Header.Get("Content-Encoding"), "Should drop the encoding of decompressed bodies")
+
+	for _, deflate = range []string{"zlib", "flate"} {
+		resp = NewRequest(WithUrl(server.URL), WithAcceptEncoding("deflate")).Do()
+
+		assert.Equal(t, payload, string(resp.Body()), "Should decompress %s deflate bodies", deflate)
+		assert.Equal(t, int64(len(compress(deflate))), resp.ContentEncoding().EncodedSize, "Should report the size received")
+	}
+
+	resp = NewRequest(WithUrl(server.URL), WithAcceptEncoding("identity")).Do()
+
+	assert.Equal(t, "identity", accepted[4], "Should ask for identity")
+	assert.Equal(t, model.ContentEncoding{EncodedSize: int64(len(payload))}, resp.ContentEncoding(), "Should report bodies received as they are")
+
+	resp = NewRequest(WithUrl(server.URL), WithoutDecompression()).Do()
+
+	assert.Equal(t, "gzip", accepted[5], "Should advertise gzip as the transport does")
+	assert.Equal(t, gzipped, resp.Body(), "Should pass compressed bodies through")
+	assert.Equal(t, model.ContentEncoding{Encoding: "gzip", EncodedSize: int64(len(gzipped))}, resp.ContentEncoding(), "Should report the body as not decompressed")
+
+	streamed, err := NewRequestBuilder().WithUrl(server.URL).WithAcceptEncoding("gzip").WithResponseStream().Build().Send()
+
+	assert.Nil(t, err, "Should stream decompressed bodies")
+
+	body, _ := ioutil.ReadAll(streamed.Response().Body)
+	streamed.Response().Body.Close()
+
+	assert.Equal(t, payload, string(body), "Should stream the decompressed body")
+	assert.Equal(t, int64(len(gzipped)), streamed.ContentEncoding().EncodedSize, "Should count the bytes streamed")
+
+	assert.Panics(t, func() { NewRequestBuilder().WithAcceptEncoding() }, "Should panic without encodings")
+}
@@ -0,0 +1,41 @@
+package gorequest
+
+import (
+	"fmt"
+	"net/http"
+)
+
+/**
+ * A context value copied into an outgoing header.
+ */
+type contextHeader struct {
+	header string
+	key    interface{}
+}
+
+/**
+ * Sets the configured headers of req from the values of its context.
+ * Missing or empty values and headers set explicitly on the request are
+ * skipped.
+ */
+func injectContextHeaders(req *http.Request, headers []contextHeader) {
+	for _, h := range headers {
+		value := contextValue(req.Context().Value(h.key))
+		if value != "" {
+			setDefaultHeader(req.Header, h.header, value)
+		}
+	}
+}
+
+func contextValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,360 @@
+package gorequest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/**
+ * Parses a curl command line, e.g. one copied from the developer tools of a
+ * browser, into a RequestBuilder. The method, URL, headers, data (joined with
+ * & as curl does), basic credentials, cookies and -k are kept; options that
+ * do not affect the request itself (-s, -L, --compressed, -o FILE...) are
+ * ignored, any other option is an error, as is data read from a file (-d
+ * @FILE). The command is split as a POSIX shell would, including line
+ * continuations and $'...' strings.
+ */
+func FromCurl(cmd string) (model.RequestBuilder, error) {
+
+	args, err := splitCommand(cmd)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, fmt.Errorf("Not a curl command: %s", cmd)
+	}
+
+	builder := NewRequestBuilder()
+
+	var contentType, method, target string
+	var data []string
+	var get bool
+
+	for i := 1; i < len(args); i++ {
+
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			target = arg
+			continue
+		}
+
+		name, value, attached := curlOption(arg)
+
+		if name == "" {
+			if curlFlags(arg) {
+				continue
+			}
+			return nil, fmt.Errorf("Unsupported curl option %s", arg)
+		}
+
+		if !attached {
+			if _, takesValue := curlValueOptions[name]; takesValue {
+				if i+1 == len(args) {
+					return nil, fmt.Errorf("Missing value for curl option %s", arg)
+				}
+				i++
+				value = args[i]
+			}
+		}
+
+		switch name {
+		case "-X", "--request":
+			method = strings.ToUpper(value)
+		case "-H", "--header":
+			parts := strings.SplitN(value, ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("Invalid curl header: %s", value)
+			}
+			name, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			if strings.EqualFold(name, "Content-Type") {
+				contentType = value
+			} else {
+				builder.WithHeader(name, value)
+			}
+		case "-d", "--data", "--data-ascii", "--data-binary":
+			if strings.HasPrefix(value, "@") {
+				return nil, fmt.Errorf("Cannot read data from a file in curl option %s %s", arg, value)
+			}
+			data = append(data, value)
+		case "--data-raw":
+			data = append(data, value)
+		case "--data-urlencode":
+			data = append(data, urlencodeData(value))
+		case "-u", "--user":
+			parts := strings.SplitN(value, ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("Password required in curl option %s", arg)
+			}
+			builder.WithBasicAuth(parts[0], parts[1])
+		case "-A", "--user-agent":
+			builder.WithHeader("User-Agent", value)
+		case "-e", "--referer":
+			builder.WithHeader("Referer", value)
+		case "-b", "--cookie":
+			builder.WithHeader("Cookie", value)
+		case "-G", "--get":
+			get = true
+		case "-I", "--head":
+			method = http.MethodHead
+		case "-k", "--insecure":
+			builder.WithInsecureSkipVerify()
+		case "--url":
+			target = value
+		}
+	}
+
+	if target == "" {
+		return nil, fmt.Errorf("No URL in curl command: %s", cmd)
+	}
+
+	if get && len(data) > 0 {
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + strings.Join(data, "&")
+		data = nil
+	}
+
+	if method == "" {
+		method = http.MethodGet
+		if len(data) > 0 {
+			method = http.MethodPost
+		}
+	}
+
+	if len(data) > 0 {
+		if contentType == "" {
+			contentType = "application/x-www-form-urlencoded"
+		}
+		builder.WithBody(&requestBody{
+			contentType: contentType,
+			data:        bytes.NewBufferString(strings.Join(data, "&")),
+		})
+	} else if contentType != "" {
+		builder.WithHeader("Content-Type", contentType)
+	}
+
+	return builder.WithMethod(method).WithUrl(target), nil
+}
+
+/**
+ * The options FromCurl understands that take a value, and those it skips
+ * along with their value.
+ */
+var curlValueOptions = map[string]struct{}{
+	"-A": {}, "--user-agent": {},
+	"-b": {}, "--cookie": {},
+	"-d": {}, "--data": {}, "--data-ascii": {}, "--data-binary": {}, "--data-raw": {}, "--data-urlencode": {},
+	"-e": {}, "--referer": {},
+	"-H": {}, "--header": {},
+	"-u": {}, "--user": {},
+	"-X": {}, "--request": {},
+	"--url": {},
+	// ignored
+	"-m": {}, "--max-time": {},
+	"-o": {}, "--output": {},
+	"--connect-timeout": {},
+	"-w": {}, "--write-out": {},
+}
+
+var curlIgnoredFlags = map[string]struct{}{
+	"--compressed": {}, "--fail": {}, "--globoff": {}, "--http1.1": {}, "--http2": {},
+	"--include": {}, "--location": {}, "--no-buffer": {}, "--progress-bar": {},
+	"--show-error": {}, "--silent": {}, "--verbose": {},
+}
+
+var curlKnownFlags = map[string]struct{}{
+	"--get": {}, "--head": {}, "--insecure": {},
+	"-G": {}, "-I": {}, "-k": {},
+}
+
+/**
+ * Splits arg into an option name and, for short options written like -XPOST,
+ * its attached value. Returns an empty name for flags curlFlags handles.
+ */
+func curlOption(arg string) (name string, value string, attached bool) {
+
+	if strings.HasPrefix(arg, "--") {
+		if _, ok := curlValueOptions[arg]; ok {
+			return arg, "", false
+		}
+		if _, ok := curlKnownFlags[arg]; ok {
+			return arg, "", false
+		}
+		return "", "", false
+	}
+
+	name = arg[:2]
+
+	if _, ok := curlValueOptions[name]; ok {
+		return name, arg[2:], len(arg) > 2
+	}
+
+	if _, ok := curlKnownFlags[name]; ok && len(arg) == 2 {
+		return name, "", false
+	}
+
+	return "", "", false
+}
+
+/**
+ * Reports whether arg only holds flags FromCurl ignores, e.g. --compressed
+ * or -sSL.
+ */
+func curlFlags(arg string) bool {
+
+	if strings.HasPrefix(arg, "--") {
+		_, ok := curlIgnoredFlags[arg]
+		return ok
+	}
+
+	for _, flag := range arg[1:] {
+		if !strings.ContainsRune("fiLNsSv", flag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/**
+ * Encodes a --data-urlencode value the way curl does: "content", "=content"
+ * and "name=content" encode the content only.
+ */
+func urlencodeData(value string) string {
+
+	if i := strings.Index(value, "="); i >= 0 {
+		if i == 0 {
+			return url.QueryEscape(value[1:])
+		}
+		return value[:i] + "=" + url.QueryEscape(value[i+1:])
+	}
+
+	return url.QueryEscape(value)
+}
+
+/**
+ * Splits a command line into words following the quoting rules of a POSIX
+ * shell: single quotes, double quotes, backslash escapes and line
+ * continuations, plus the $'...' strings bash uses for control characters.
+ */
+func splitCommand(cmd string) ([]string, error) {
+
+	var args []string
+	var word strings.Builder
+	inWord := false
+
+	runes := []rune(cmd)
+
+	for i := 0; i < len(runes); i++ {
+
+		c := runes[i]
+
+		switch {
+		case c == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("Unterminated escape in command: %s", cmd)
+			}
+			i++
+			if runes[i] == '\n' || runes[i] == '\r' {
+				if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+					i++
+				}
+				continue
+			}
+			word.WriteRune(runes[i])
+			inWord = true
+		case c == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("Unterminated quote in command: %s", cmd)
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			i = end
+			inWord = true
+		case c == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			end, err := ansiCString(runes, i+2, &word)
+			if err != nil {
+				return nil, fmt.Errorf("%s in command: %s", err, cmd)
+			}
+			i = end
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, fmt.Errorf("Unterminated quote in command: %s", cmd)
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		args = append(args, word.String())
+	}
+
+	return args, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+/**
+ * Decodes the body of a $'...' string starting at from into word and returns
+ * the index of its closing quote.
+ */
+func ansiCString(runes []rune, from int, word *strings.Builder) (int, error) {
+
+	escapes := map[rune]rune{'n': '\n', 't': '\t', 'r': '\r', '\\': '\\', '\'': '\'', '"': '"', '0': 0}
+
+	for i := from; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\'':
+			return i, nil
+		case runes[i] == '\\' && i+1 < len(runes):
+			i++
+			if escaped, ok := escapes[runes[i]]; ok {
+				word.WriteRune(escaped)
+			} else {
+				word.WriteRune('\\')
+				word.WriteRune(runes[i])
+			}
+		default:
+			word.WriteRune(runes[i])
+		}
+	}
+
+	return 0, errors.New("Unterminated quote")
+}
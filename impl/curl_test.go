@@ -0,0 +1,69 @@
+package gorequest
+
+import (
+	"net/http"
+	"testing"
+
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromCurl(t *testing.T) {
+	mock := requestmock.New()
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	var received *http.Request
+	var body string
+
+	mock.On("POST", "/api/users").Match(func(req *http.Request, raw []byte) bool {
+		received = req
+		body = string(raw)
+		return true
+	}).Reply(http.StatusCreated, "")
+
+	builder, err := FromCurl(`curl 'https://api.example.com/api/users' \
+  -H 'Accept: application/json' \
+  -H "Content-Type: application/json" \
+  -H $'X-Note: it\'s' \
+  -u admin:secret \
+  --data-raw '{"name":"Ann"}' \
+  --compressed -sS`)
+
+	assert.Nil(t, err, "Should parse the command")
+
+	resp := builder.WithClient(c).Build().Do()
+
+	assert.Equal(t, http.StatusCreated, resp.Response().StatusCode, "Should send the request")
+	assert.Equal(t, "application/json", received.Header.Get("Accept"), "Should keep the headers")
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"), "Should keep the content type")
+	assert.Equal(t, "it's", received.Header.Get("X-Note"), "Should decode $'...' strings")
+	assert.Equal(t, `{"name":"Ann"}`, body, "Should send the data")
+
+	user, password, _ := received.BasicAuth()
+
+	assert.Equal(t, "admin:secret", user+":"+password, "Should keep the credentials")
+
+	mock.On("GET", "/search").Match(func(req *http.Request, body []byte) bool {
+		received = req
+		return true
+	}).Reply(http.StatusOK, "")
+
+	builder, err = FromCurl(`curl -G -d q=go --data-urlencode "tag=a b" https://api.example.com/search`)
+
+	assert.Nil(t, err, "Should parse the command")
+
+	builder.WithClient(c).Build().Do()
+
+	assert.Equal(t, "q=go&tag=a+b", received.URL.RawQuery, "Should send the data in the query")
+
+	for _, cmd := range []string{
+		"wget https://example.com",
+		"curl -H 'Accept: */*'",
+		"curl --upload-file x https://example.com",
+		"curl -d @body.json https://example.com",
+		"curl 'https://example.com",
+	} {
+		_, err := FromCurl(cmd)
+		assert.NotNil(t, err, "Should reject "+cmd)
+	}
+}
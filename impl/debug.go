@@ -0,0 +1,102 @@
+package gorequest
+
+import (
+	"bytes"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+)
+
+/**
+ * A model.Logger writing exchanges in the format of curl -v: the request
+ * line and headers prefixed with "> ", the status line and headers prefixed
+ * with "< ", bodies as they are.
+ */
+type debugLogger struct {
+	mutex  sync.Mutex
+	output io.Writer
+}
+
+func newDebugLogger(output io.Writer) *debugLogger {
+	if output == nil {
+		output = os.Stderr
+	}
+	return &debugLogger{
+		output: output,
+	}
+}
+
+func (l *debugLogger) Log(entry model.LogEntry) {
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	target, host := entry.URL, ""
+
+	if u, err := url.Parse(entry.URL); err == nil {
+		target, host = u.RequestURI(), u.Host
+	}
+
+	proto := entry.Proto
+
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	fmt.Fprintf(buf, "* Attempt %d\n", entry.Attempt)
+	fmt.Fprintf(buf, "> %s %s %s\n", entry.Method, target, proto)
+	if display := HostToUnicode(host); display != host {
+		fmt.Fprintf(buf, "> Host: %s (%s)\n", host, display)
+	} else {
+		fmt.Fprintf(buf, "> Host: %s\n", host)
+	}
+	writeDebugHeader(buf, "> ", entry.RequestHeader)
+	buf.WriteString(">\n")
+	writeDebugBody(buf, entry.RequestBody)
+
+	if entry.Err != nil {
+		fmt.Fprintf(buf, "* Error after %s: %v\n", entry.Duration, entry.Err)
+	} else {
+		fmt.Fprintf(buf, "< %s %d %s\n", proto, entry.StatusCode, http.StatusText(entry.StatusCode))
+		writeDebugHeader(buf, "< ", entry.ResponseHeader)
+		buf.WriteString("<\n")
+		writeDebugBody(buf, entry.ResponseBody)
+		fmt.Fprintf(buf, "* Completed in %s\n", entry.Duration)
+	}
+
+	// one write per exchange, so concurrent requests do not interleave
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.output.Write(buf.Bytes())
+}
+
+func writeDebugHeader(buf *bytes.Buffer, prefix string, header http.Header) {
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(buf, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+func writeDebugBody(buf *bytes.Buffer, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	buf.Write(body)
+	if body[len(body)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+}
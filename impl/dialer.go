@@ -0,0 +1,115 @@
+package gorequest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+/**
+ * Same values as the dialer of http.DefaultTransport.
+ */
+var defaultDialTimeout time.Duration = 30 * time.Second
+var defaultKeepAlive time.Duration = 30 * time.Second
+
+/**
+ * Socket settings of transports created with a ClientBuilder.
+ */
+type dialerConfig struct {
+	// Interval between TCP keepalive probes; negative disables them.
+	keepAlive time.Duration
+	// Source address; nil lets the operating system pick one.
+	localAddr net.IP
+	// TCP_NODELAY; nil keeps the Go default (enabled, i.e. no Nagle).
+	noDelay *bool
+}
+
+func newDialerConfig() dialerConfig {
+	return dialerConfig{
+		keepAlive: defaultKeepAlive,
+	}
+}
+
+func (c dialerConfig) dialContext() func(ctx context.Context, network, addr string) (net.Conn, error) {
+
+	dialer := &net.Dialer{
+		KeepAlive: c.keepAlive,
+		Timeout:   defaultDialTimeout,
+	}
+
+	if c.localAddr != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: c.localAddr}
+	}
+
+	if c.noDelay == nil {
+		return dialer.DialContext
+	}
+
+	noDelay := *c.noDelay
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		if tcpConn, ok := conn.(*net.TCPConn); ok {
+			if err := tcpConn.SetNoDelay(noDelay); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		return conn, nil
+	}
+}
+
+func parseLocalAddr(addr string) net.IP {
+
+	ip := net.ParseIP(addr)
+
+	if ip == nil {
+		panic(fmt.Errorf("Invalid local address: %s", addr))
+	}
+
+	return ip
+}
+
+/**
+ * Returns the first IPv4 address of the named interface, or its first IPv6
+ * address when it has no IPv4 one.
+ */
+func interfaceAddr(name string) net.IP {
+
+	iface, err := net.InterfaceByName(name)
+
+	if err != nil {
+		panic(err)
+	}
+
+	addrs, err := iface.Addrs()
+
+	if err != nil {
+		panic(err)
+	}
+
+	var fallback net.IP
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			return ipNet.IP
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+
+	if fallback == nil {
+		panic(fmt.Errorf("No address assigned to interface %s", name))
+	}
+
+	return fallback
+}
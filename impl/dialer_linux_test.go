@@ -0,0 +1,60 @@
+//go:build linux
+// +build linux
+
+package gorequest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+ * Dials ts with the transport of builder and reads back the socket options
+ * of the connection.
+ */
+func dialedSocketOptions(t *testing.T, builder *clientBuilder, ts *httptest.Server) (keepAlive bool, interval int, noDelay bool) {
+
+	conn, err := builder.roundTripper().(*http.Transport).DialContext(context.Background(), "tcp", strings.TrimPrefix(ts.URL, "http://"))
+
+	if !assert.Nil(t, err, "Should dial") {
+		t.FailNow()
+	}
+
+	defer conn.Close()
+
+	raw, _ := conn.(*net.TCPConn).SyscallConn()
+
+	raw.Control(func(fd uintptr) {
+		on, _ := syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_KEEPALIVE)
+		interval, _ = syscall.GetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL)
+		delay, _ := syscall.GetsockoptInt(int(fd), syscall.IPPROTO_TCP, syscall.TCP_NODELAY)
+		keepAlive, noDelay = on != 0, delay != 0
+	})
+
+	return keepAlive, interval, noDelay
+}
+
+func TestKeepAliveAndNoDelay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+
+	defer ts.Close()
+
+	keepAlive, interval, noDelay := dialedSocketOptions(t, NewClientBuilder().WithKeepAlive(15*time.Second).WithNoDelay(false).(*clientBuilder), ts)
+
+	assert.True(t, keepAlive, "Should enable TCP keepalive")
+	assert.Equal(t, 15, interval, "Should probe at the configured interval")
+	assert.False(t, noDelay, "Should disable TCP_NODELAY")
+
+	keepAlive, _, noDelay = dialedSocketOptions(t, NewClientBuilder().WithKeepAlive(-1).(*clientBuilder), ts)
+
+	assert.False(t, keepAlive, "Should disable TCP keepalive")
+	assert.True(t, noDelay, "Should keep the Go default of TCP_NODELAY")
+}
@@ -0,0 +1,161 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**
+ * Upper bound for a single discovery lookup.
+ */
+var discoveryTimeout time.Duration = 10 * time.Second
+
+/**
+ * A balancer whose endpoints are refreshed periodically from a Discovery.
+ */
+type discoveryBalancer struct {
+	*balancer
+	discovery model.Discovery
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func newDiscoveryBalancer(discovery model.Discovery, strategy model.BalancingStrategy, refresh time.Duration) *discoveryBalancer {
+
+	if refresh <= 0 {
+		panic(errors.New("Refresh interval must be positive"))
+	}
+
+	endpoints, err := lookupEndpoints(discovery)
+
+	if err != nil {
+		panic(err)
+	}
+
+	b := &discoveryBalancer{
+		balancer:  newBalancer(strategy, endpoints),
+		discovery: discovery,
+		stop:      make(chan struct{}),
+	}
+
+	go b.refresh(refresh)
+
+	return b
+}
+
+/**
+ * Stops refreshing the endpoints.
+ */
+func (b *discoveryBalancer) close() {
+	b.stopOnce.Do(func() { close(b.stop) })
+}
+
+func (b *discoveryBalancer) refresh(interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			// keep the last known endpoints when discovery is unavailable
+			if endpoints, err := lookupEndpoints(b.discovery); err == nil {
+				b.update(endpoints)
+			}
+		}
+	}
+}
+
+func lookupEndpoints(discovery model.Discovery) ([]model.Endpoint, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+	defer cancel()
+
+	endpoints, err := discovery.Endpoints(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoints) == 0 {
+		return nil, errors.New("Discovery returned no endpoints")
+	}
+
+	// reject bad data here, refreshes must never panic in the background
+	for _, endpoint := range endpoints {
+		if u, err := url.Parse(endpoint.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("Discovery returned an invalid endpoint: %s", endpoint.URL)
+		}
+	}
+
+	return endpoints, nil
+}
+
+/****************************************************
+ * model.Discovery implementation
+ ****************************************************/
+
+type srvDiscovery struct {
+	name     string
+	proto    string
+	resolver *net.Resolver
+	scheme   string
+	service  string
+}
+
+/**
+ * Discovers endpoints from the DNS SRV records of _service._proto.name. Only
+ * the records of the best (lowest) priority are used, weighted as published.
+ * Endpoint URLs are built as scheme://target:port.
+ */
+func NewSRVDiscovery(scheme, service, proto, name string) model.Discovery {
+	return &srvDiscovery{
+		name:     name,
+		proto:    proto,
+		resolver: net.DefaultResolver,
+		scheme:   scheme,
+		service:  service,
+	}
+}
+
+func (d *srvDiscovery) Endpoints(ctx context.Context) ([]model.Endpoint, error) {
+
+	_, records, err := d.resolver.LookupSRV(ctx, d.service, d.proto, d.name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return srvEndpoints(d.scheme, records), nil
+}
+
+func srvEndpoints(scheme string, records []*net.SRV) []model.Endpoint {
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Priority < records[j].Priority
+	})
+
+	endpoints := make([]model.Endpoint, 0, len(records))
+
+	for _, record := range records {
+		if record.Priority != records[0].Priority {
+			break
+		}
+		endpoints = append(endpoints, model.Endpoint{
+			URL:    fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), fmt.Sprint(record.Port))),
+			Weight: int(record.Weight),
+		})
+	}
+
+	return endpoints
+}
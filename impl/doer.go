@@ -0,0 +1,77 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+/****************************************************
+ * model.Doer implementation
+ ****************************************************/
+
+type doer struct {
+	client model.Client
+	ctx    context.Context
+}
+
+/**
+ * Returns a Doer sending its requests through client, or through the
+ * default client if client is nil.
+ */
+func NewDoer(client model.Client) model.Doer {
+	return &doer{
+		client: client,
+		ctx:    context.Background(),
+	}
+}
+
+func (d *doer) Delete(url string) model.Response {
+	return d.Do(http.MethodDelete, url, nil)
+}
+
+func (d *doer) Do(method string, url string, body model.RequestBody) model.Response {
+	return d.request(method, url, body).Do()
+}
+
+func (d *doer) Get(url string) model.Response {
+	return d.Do(http.MethodGet, url, nil)
+}
+
+func (d *doer) Head(url string) model.Response {
+	return d.Do(http.MethodHead, url, nil)
+}
+
+func (d *doer) Patch(url string, body model.RequestBody) model.Response {
+	return d.Do(http.MethodPatch, url, body)
+}
+
+func (d *doer) Post(url string, body model.RequestBody) model.Response {
+	return d.Do(http.MethodPost, url, body)
+}
+
+func (d *doer) Put(url string, body model.RequestBody) model.Response {
+	return d.Do(http.MethodPut, url, body)
+}
+
+func (d *doer) Send(method string, url string, body model.RequestBody) (model.Response, error) {
+	return d.request(method, url, body).Send()
+}
+
+func (d *doer) WithContext(ctx context.Context) model.Doer {
+	return &doer{
+		client: d.client,
+		ctx:    ctx,
+	}
+}
+
+func (d *doer) request(method string, url string, body model.RequestBody) model.Request {
+
+	return NewRequestBuilder().
+		WithBody(body).
+		WithClient(d.client).
+		WithContext(d.ctx).
+		WithMethod(method).
+		WithUrl(url).
+		Build()
+}
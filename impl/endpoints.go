@@ -0,0 +1,256 @@
+package gorequest
+
+import (
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+/**
+ * Picks the base URL of requests with relative URLs. Absolute request URLs
+ * bypass the selector.
+ */
+type endpointSelector interface {
+	healthTarget
+	do(req *http.Request, exchange func(*http.Request) (*response, error)) (*response, error)
+}
+
+/**
+ * An ordered list of base URLs. Requests start on the last endpoint that
+ * answered successfully and move on to the next one as decided by failOver.
+ * Endpoints failing their health checks are tried last.
+ */
+type endpoints struct {
+	current int32
+	down    []int32
+	urls    []*url.URL
+}
+
+func newEndpoints(baseURLs []string) *endpoints {
+
+	if len(baseURLs) == 0 {
+		panic(errors.New("At least one endpoint is required"))
+	}
+
+	urls := make([]*url.URL, 0, len(baseURLs))
+
+	for _, baseURL := range baseURLs {
+		urls = append(urls, parseEndpoint(baseURL))
+	}
+
+	return &endpoints{
+		down: make([]int32, len(urls)),
+		urls: urls,
+	}
+}
+
+func (e *endpoints) do(req *http.Request, exchange func(*http.Request) (*response, error)) (*response, error) {
+
+	if req.URL.IsAbs() {
+		return exchange(req)
+	}
+
+	var resp *response
+	var err error
+
+	for i, index := range e.order() {
+
+		target := resolveEndpoint(e.urls[index], req.URL)
+		attempt := retarget(req, target)
+
+		// only the endpoints after the first one need a fresh body
+		if i > 0 {
+			clone, cloneErr := cloneRequest(req, target)
+			if cloneErr != nil {
+				// the body cannot be replayed, keep the outcome of the last attempt
+				break
+			}
+			// the failed response of the previous endpoint is not handed over
+			resp.discard()
+			attempt = clone
+		}
+
+		resp, err = exchange(attempt)
+
+		if !failedExchange(resp, err) {
+			atomic.StoreInt32(&e.current, int32(index))
+			return resp, nil
+		}
+
+		if !failOver(attempt, err) {
+			return resp, err
+		}
+	}
+
+	return resp, err
+}
+
+/**
+ * Reports whether a failed exchange may move on to the next endpoint. The
+ * server may have applied a request that is not idempotent before failing,
+ * so those only move on when the endpoint was never reached: it could not
+ * be dialed or its circuit is open. Errors of the caller's context and
+ * rejections by the client's limits never fail over.
+ */
+func failOver(req *http.Request, err error) bool {
+
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if err == nil {
+		// a 5xx response
+		return idempotent(req.Method)
+	}
+
+	var open *model.CircuitOpenError
+	var dial *net.OpError
+
+	if errors.As(err, &open) || errors.As(err, &dial) && dial.Op == "dial" {
+		return true
+	}
+
+	// the transport reports its errors as *url.Error, rejections are not
+	var transport *url.Error
+
+	return idempotent(req.Method) && errors.As(err, &transport)
+}
+
+/**
+ * Returns the indexes of the endpoints in the order they are tried: from
+ * the current one on, endpoints that are down last.
+ */
+func (e *endpoints) order() []int {
+
+	start := int(atomic.LoadInt32(&e.current))
+	order := make([]int, 0, len(e.urls))
+	var down []int
+
+	for i := 0; i < len(e.urls); i++ {
+		index := (start + i) % len(e.urls)
+		if atomic.LoadInt32(&e.down[index]) == 1 {
+			down = append(down, index)
+			continue
+		}
+		order = append(order, index)
+	}
+
+	return append(order, down...)
+}
+
+func (e *endpoints) targets() []*url.URL {
+	return e.urls
+}
+
+func (e *endpoints) setDown(target *url.URL, down bool) {
+	for i, u := range e.urls {
+		if u.String() != target.String() {
+			continue
+		}
+		if down {
+			atomic.StoreInt32(&e.down[i], 1)
+		} else {
+			atomic.StoreInt32(&e.down[i], 0)
+		}
+	}
+}
+
+func parseEndpoint(baseURL string) *url.URL {
+
+	u, err := url.Parse(baseURL)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		panic(fmt.Errorf("Endpoint must be an absolute URL: %s", baseURL))
+	}
+
+	return u
+}
+
+/**
+ * Joins the path of the endpoint with the path and query of the request.
+ */
+func resolveEndpoint(base *url.URL, ref *url.URL) *url.URL {
+	resolved := *base
+	resolved.Path = strings.TrimSuffix(resolved.Path, "/") + "/" + strings.TrimPrefix(ref.Path, "/")
+	resolved.RawPath = ""
+	resolved.RawQuery = ref.RawQuery
+	resolved.Fragment = ref.Fragment
+	return &resolved
+}
+
+/**
+ * Returns a copy of req aimed at target that shares its body, for the first
+ * time the body is sent.
+ */
+func retarget(req *http.Request, target *url.URL) *http.Request {
+	attempt := req.Clone(req.Context())
+	attempt.URL = target
+	attempt.Host = ""
+	return attempt
+}
+
+/**
+ * Returns a copy of req aimed at target with a fresh body, for requests
+ * that must be sent more than once.
+ */
+func cloneRequest(req *http.Request, target *url.URL) (*http.Request, error) {
+
+	clone := retarget(req, target)
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return clone, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, &model.BodyNotReplayableError{Method: req.Method, URL: req.URL.String()}
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return nil, &model.BodyNotReplayableError{Method: req.Method, URL: req.URL.String(), Err: err}
+	}
+
+	clone.Body = body
+
+	return clone, nil
+}
+
+/**
+ * Connection errors and 5xx responses count against an endpoint.
+ */
+func failedExchange(resp *response, err error) bool {
+	return err != nil || resp.response.StatusCode >= 500
+}
+
+/**
+ * What an exchange tells about the health of its host or endpoint. An
+ * exchange the caller gave up on tells nothing: its context was done, e.g.
+ * a hedged copy that lost or a request past its deadline.
+ */
+type exchangeOutcome int
+
+const (
+	exchangeSucceeded exchangeOutcome = iota
+	exchangeFailed
+	exchangeAbandoned
+)
+
+func outcomeOf(req *http.Request, resp *response, err error) exchangeOutcome {
+	if err != nil && req.Context().Err() != nil {
+		return exchangeAbandoned
+	}
+	if failedExchange(resp, err) {
+		return exchangeFailed
+	}
+	return exchangeSucceeded
+}
@@ -0,0 +1,458 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointFailover(t *testing.T) {
+	var primaryCalls, secondaryCalls int
+
+	primary := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		primaryCalls++
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		secondaryCalls++
+		body, _ := ioutil.ReadAll(req.Body)
+		fmt.Fprintf(resp, "%s %s?%s %s", req.Method, req.URL.Path, req.URL.RawQuery, body)
+	}))
+
+	defer secondary.Close()
+
+	c := NewClientBuilder().WithEndpoints(primary.URL+"/api/", secondary.URL+"/api").Build()
+
+	response := NewRequestBuilder().WithUrl("/v1/customers?page=2").WithClient(c).WithMethod("PUT").WithBody(newJsonBody(`{"id":1}`)).Build().Do()
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+	assert.Equal(t, `PUT /api/v1/customers?page=2 {"id":1}`, string(response.Body()), "Should replay the request on the secondary")
+
+	NewRequestBuilder().WithUrl("/v1/customers").WithClient(c).Build().Do()
+
+	assert.Equal(t, 1, primaryCalls, "Should stick to the healthy endpoint")
+	assert.Equal(t, 2, secondaryCalls, "Should send both requests to the secondary")
+
+	c = NewClientBuilder().WithEndpoints(primary.URL+"/api/", secondary.URL+"/api").Build()
+
+	response = NewRequestBuilder().WithUrl("/v1/customers").WithClient(c).WithMethod("POST").WithBody(newJsonBody(`{"id":1}`)).Build().Do()
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Response().StatusCode, "Should return the 5xx of non-idempotent requests")
+	assert.Equal(t, 2, primaryCalls, "Should send non-idempotent requests once")
+	assert.Equal(t, 2, secondaryCalls, "Should not replay non-idempotent requests after a 5xx")
+}
+
+type closeTracker struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeTracker) Close() error {
+	c.closed = true
+	return nil
+}
+
+type failoverTransport struct {
+	bodies []*closeTracker
+}
+
+func (f *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := &closeTracker{Reader: strings.NewReader(req.URL.Host)}
+	f.bodies = append(f.bodies, body)
+	status := http.StatusOK
+	if req.URL.Host == "primary.example.com" {
+		status = http.StatusBadGateway
+	}
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: body, Request: req}, nil
+}
+
+func TestEndpointFailoverDiscardsStreamedResponses(t *testing.T) {
+	transport := &failoverTransport{}
+
+	c := NewClientBuilder().WithTransport(transport).WithEndpoints("https://primary.example.com", "https://secondary.example.com").Build()
+
+	response, err := NewRequestBuilder().WithUrl("/files/1").WithClient(c).WithResponseStream().Build().Send()
+
+	assert.Nil(t, err, "Should fail over")
+	assert.Equal(t, http.StatusOK, response.Response().StatusCode, "Should return the response of the secondary")
+	assert.Len(t, transport.bodies, 2, "Should try both endpoints")
+	assert.True(t, transport.bodies[0].closed, "Should close the body of the failed response")
+	assert.False(t, transport.bodies[1].closed, "Should leave the body of the returned response open")
+
+	response.Response().Body.Close()
+}
+
+func TestEndpointFailoverOnConnectionError(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+	down.Close()
+
+	up := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "up")
+	}))
+
+	defer up.Close()
+
+	c := NewClientBuilder().WithEndpoints(down.URL, up.URL).Build()
+
+	response := NewRequestBuilder().WithUrl("/health").WithClient(c).Build().Do()
+
+	assert.Equal(t, "up", string(response.Body()), "Should equal body")
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+/**
+ * Answers per host: "refused" cannot be dialed, "timeout" times out after
+ * the request was written, any other host replies with its name.
+ */
+type hostTransport struct {
+	calls []string
+	mutex sync.Mutex
+}
+
+func (h *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	h.mutex.Lock()
+	h.calls = append(h.calls, req.URL.Hostname())
+	h.mutex.Unlock()
+
+	if req.Body != nil {
+		ioutil.ReadAll(req.Body)
+	}
+
+	switch {
+	case req.Context().Err() != nil:
+		return nil, req.Context().Err()
+	case req.URL.Hostname() == "refused":
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	case req.URL.Hostname() == "timeout":
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(req.URL.Hostname())), Request: req}, nil
+}
+
+func TestEndpointFailoverRules(t *testing.T) {
+	send := func(first string, method string, ctx context.Context) ([]string, error) {
+		transport := &hostTransport{}
+		c := NewClientBuilder().WithTransport(transport).WithEndpoints("http://"+first, "http://up").Build()
+		_, err := NewRequestBuilder().WithUrl("/orders").WithClient(c).WithMethod(method).WithBody(newJsonBody(`{}`)).WithContext(ctx).Build().Send()
+		return transport.calls, err
+	}
+
+	calls, err := send("timeout", "GET", context.Background())
+
+	assert.Nil(t, err, "Should fail over idempotent requests")
+	assert.Equal(t, []string{"timeout", "up"}, calls, "Should try the next endpoint")
+
+	calls, err = send("timeout", "POST", context.Background())
+
+	assert.NotNil(t, err, "Should return the error")
+	assert.Equal(t, []string{"timeout"}, calls, "Should not resend a request the server may have applied")
+
+	calls, err = send("refused", "POST", context.Background())
+
+	assert.Nil(t, err, "Should fail over requests that never reached the server")
+	assert.Equal(t, []string{"refused", "up"}, calls, "Should try the next endpoint")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls, err = send("timeout", "GET", ctx)
+
+	assert.True(t, errors.Is(err, context.Canceled), "Should return the error of the context")
+	assert.True(t, len(calls) <= 1, "Should not fail over when the caller gave up")
+}
+
+func TestEndpointFailoverSendsUnreplayableBodyOnce(t *testing.T) {
+	transport := &hostTransport{}
+	c := NewClientBuilder().WithTransport(transport).WithEndpoints("http://up", "http://refused").Build()
+
+	req, _ := http.NewRequest("POST", "/upload", ioutil.NopCloser(strings.NewReader("data")))
+
+	resp, err := NewRequestFromHttp(c, req).Send()
+
+	assert.Nil(t, err, "Should send a body that cannot be replayed to the first endpoint")
+	assert.Equal(t, "up", string(resp.Body()), "Should equal body")
+}
+
+func TestEndpointsIgnoredForAbsoluteUrls(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "direct")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithEndpoints("http://unused.invalid").Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, "direct", string(response.Body()), "Should equal body")
+}
+
+func newBalancerTestEndpoints(weights ...int) []model.Endpoint {
+	endpoints := make([]model.Endpoint, 0, len(weights))
+	for i, weight := range weights {
+		endpoints = append(endpoints, model.Endpoint{URL: fmt.Sprintf("http://backend-%d.internal", i), Weight: weight})
+	}
+	return endpoints
+}
+
+func TestBalancerRoundRobin(t *testing.T) {
+	b := newBalancer(model.BalanceRoundRobin, newBalancerTestEndpoints(1, 1, 1))
+
+	hosts := make([]string, 0)
+
+	for i := 0; i < 4; i++ {
+		endpoint := b.acquire()
+		hosts = append(hosts, endpoint.url.Host)
+		b.release(endpoint, exchangeSucceeded)
+	}
+
+	assert.Equal(t, []string{"backend-0.internal", "backend-1.internal", "backend-2.internal", "backend-0.internal"}, hosts, "Should cycle through the endpoints")
+}
+
+func TestBalancerWeighted(t *testing.T) {
+	b := newBalancer(model.BalanceWeighted, newBalancerTestEndpoints(3, 1))
+
+	counts := make(map[string]int)
+
+	for i := 0; i < 8; i++ {
+		endpoint := b.acquire()
+		counts[endpoint.url.Host]++
+		b.release(endpoint, exchangeSucceeded)
+	}
+
+	assert.Equal(t, 6, counts["backend-0.internal"], "Should send three quarters to the heavy endpoint")
+	assert.Equal(t, 2, counts["backend-1.internal"], "Should send a quarter to the light endpoint")
+}
+
+func TestBalancerLeastOutstanding(t *testing.T) {
+	b := newBalancer(model.BalanceLeastOutstanding, newBalancerTestEndpoints(1, 1))
+
+	busy := b.acquire()
+	idle := b.acquire()
+
+	assert.NotEqual(t, busy.url.Host, idle.url.Host, "Should avoid the busy endpoint")
+
+	b.release(idle, exchangeSucceeded)
+
+	for i := 0; i < 3; i++ {
+		endpoint := b.acquire()
+		assert.Equal(t, idle.url.Host, endpoint.url.Host, "Should pick the endpoint with fewer requests in flight")
+		b.release(endpoint, exchangeSucceeded)
+	}
+}
+
+func TestBalancerLowestLatency(t *testing.T) {
+	b := newBalancer(model.BalanceLowestLatency, newBalancerTestEndpoints(1, 1))
+	b.latency = newLatencyTracker(systemClock{}, 0)
+
+	b.latency.record("backend-0.internal", time.Now(), 80*time.Millisecond, false)
+
+	endpoint := b.acquire()
+	assert.Equal(t, "backend-1.internal", endpoint.url.Host, "Should measure the endpoint without samples first")
+	b.release(endpoint, exchangeSucceeded)
+
+	b.latency.record("backend-1.internal", time.Now(), 20*time.Millisecond, false)
+
+	for i := 0; i < 3; i++ {
+		endpoint := b.acquire()
+		assert.Equal(t, "backend-1.internal", endpoint.url.Host, "Should pick the fastest endpoint")
+		b.release(endpoint, exchangeSucceeded)
+	}
+}
+
+func TestBalancerSkipsUnhealthyEndpoints(t *testing.T) {
+	b := newBalancer(model.BalanceRoundRobin, newBalancerTestEndpoints(1, 1))
+
+	for i := 0; i < unhealthyThreshold; i++ {
+		b.endpoints[0].outstanding++
+		b.release(b.endpoints[0], exchangeFailed)
+	}
+
+	for i := 0; i < 3; i++ {
+		endpoint := b.acquire()
+		assert.Equal(t, "backend-1.internal", endpoint.url.Host, "Should skip the unhealthy endpoint")
+		b.release(endpoint, exchangeSucceeded)
+	}
+}
+
+func TestBalancerSendsRelativeRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, req.URL.Path)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithBalancer(model.BalanceRoundRobin, model.Endpoint{URL: ts.URL + "/svc"}).Build()
+
+	response := NewRequestBuilder().WithUrl("/status").WithClient(c).Build().Do()
+
+	assert.Equal(t, "/svc/status", string(response.Body()), "Should equal body")
+}
+
+type staticDiscovery struct {
+	endpoints []model.Endpoint
+	mutex     sync.Mutex
+}
+
+func (d *staticDiscovery) Endpoints(ctx context.Context) ([]model.Endpoint, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.endpoints, nil
+}
+
+func (d *staticDiscovery) set(endpoints ...model.Endpoint) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.endpoints = endpoints
+}
+
+func TestDiscoveryRefresh(t *testing.T) {
+	first := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "first")
+	}))
+
+	defer first.Close()
+
+	second := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "second")
+	}))
+
+	defer second.Close()
+
+	discovery := &staticDiscovery{}
+	discovery.set(model.Endpoint{URL: first.URL})
+
+	c := NewClientBuilder().WithDiscovery(discovery, model.BalanceRoundRobin, 10*time.Millisecond).Build()
+
+	response := NewRequestBuilder().WithUrl("/").WithClient(c).Build().Do()
+
+	assert.Equal(t, "first", string(response.Body()), "Should use the discovered endpoint")
+
+	discovery.set(model.Endpoint{URL: second.URL})
+	time.Sleep(50 * time.Millisecond)
+
+	response = NewRequestBuilder().WithUrl("/").WithClient(c).Build().Do()
+
+	assert.Equal(t, "second", string(response.Body()), "Should use the refreshed endpoint")
+}
+
+type countingDiscovery struct {
+	staticDiscovery
+	lookups int32
+}
+
+func (d *countingDiscovery) Endpoints(ctx context.Context) ([]model.Endpoint, error) {
+	atomic.AddInt32(&d.lookups, 1)
+	return d.staticDiscovery.Endpoints(ctx)
+}
+
+func TestDiscoveryStartsOnBuild(t *testing.T) {
+	discovery := &countingDiscovery{}
+	discovery.set(model.Endpoint{URL: "http://backend.internal"})
+
+	builder := NewClientBuilder().WithDiscovery(discovery, model.BalanceRoundRobin, time.Hour)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&discovery.lookups), "Should not look up endpoints before Build")
+
+	first := builder.Build()
+	second := builder.Build()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&discovery.lookups), "Should look up endpoints once per client")
+
+	assert.Nil(t, first.Close(context.Background()), "Should close the first client")
+	assert.Nil(t, second.Close(context.Background()), "Should close the second client")
+	assert.NotPanics(t, func() { first.(*client).endpoints.(*discoveryBalancer).close() }, "Should stop refreshing only once")
+}
+
+func TestSRVEndpoints(t *testing.T) {
+	endpoints := srvEndpoints("https", []*net.SRV{
+		{Target: "backup.example.com.", Port: 8443, Priority: 20, Weight: 100},
+		{Target: "a.example.com.", Port: 443, Priority: 10, Weight: 60},
+		{Target: "b.example.com.", Port: 443, Priority: 10, Weight: 40},
+	})
+
+	assert.Equal(t, []model.Endpoint{
+		{URL: "https://a.example.com:443", Weight: 60},
+		{URL: "https://b.example.com:443", Weight: 40},
+	}, endpoints, "Should only keep the best priority")
+}
+
+func TestHealthCheckSteersTraffic(t *testing.T) {
+	var sick int32 = 1
+
+	newServer := func(name string, healthy func() bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == "/healthz" && !healthy() {
+				resp.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(resp, name)
+		}))
+	}
+
+	good := newServer("good", func() bool { return true })
+	bad := newServer("bad", func() bool { return atomic.LoadInt32(&sick) == 0 })
+
+	defer good.Close()
+	defer bad.Close()
+
+	c := NewClientBuilder().
+		WithBalancer(model.BalanceRoundRobin, model.Endpoint{URL: bad.URL}, model.Endpoint{URL: good.URL}).
+		WithHealthCheck(model.HealthCheck{Path: "/healthz", Interval: 10 * time.Millisecond}).
+		Build()
+
+	defer c.Close(context.Background())
+
+	time.Sleep(50 * time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		body := NewRequestBuilder().WithUrl("/").WithClient(c).Build().Do().Body()
+		assert.Equal(t, "good", string(body), "Should avoid the endpoint failing its checks")
+	}
+
+	atomic.StoreInt32(&sick, 0)
+	time.Sleep(50 * time.Millisecond)
+
+	bodies := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		bodies[string(NewRequestBuilder().WithUrl("/").WithClient(c).Build().Do().Body())] = true
+	}
+
+	assert.Equal(t, map[string]bool{"good": true, "bad": true}, bodies, "Should use the endpoint again once it recovers")
+}
+
+func TestHealthCheckFailoverOrder(t *testing.T) {
+	e := newEndpoints([]string{"http://a.example.com", "http://b.example.com", "http://c.example.com"})
+
+	e.setDown(e.urls[0], true)
+
+	assert.Equal(t, []int{1, 2, 0}, e.order(), "Should try endpoints that are down last")
+
+	e.setDown(e.urls[0], false)
+
+	assert.Equal(t, []int{0, 1, 2}, e.order(), "Should restore the order")
+}
@@ -0,0 +1,51 @@
+package gorequest
+
+import (
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+/**
+ * Probes the resource of options with a HEAD request, or, for servers that
+ * answer HEAD with 405 or 501, with a GET whose body is closed unread.
+ * 2xx responses mean the resource exists, 404 and 410 that it does not;
+ * any other status is returned as an error along with the response, whose
+ * body is always closed.
+ */
+func Exists(options ...model.Option) (bool, *http.Response, error) {
+
+	resp, err := probe(options, http.MethodHead)
+
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = probe(options, http.MethodGet)
+	}
+
+	if err != nil {
+		return false, resp, err
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return true, resp, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return false, resp, nil
+	}
+
+	return false, resp, fmt.Errorf("Cannot tell whether the resource exists: status %d", resp.StatusCode)
+}
+
+func probe(options []model.Option, method string) (*http.Response, error) {
+
+	builder := newRequestBuilder(options)
+
+	resp, err := builder.WithMethod(method).WithResponseStream().Build().Send()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Response().Body.Close()
+
+	return resp.Response(), nil
+}
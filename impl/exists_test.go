@@ -0,0 +1,46 @@
+package gorequest
+
+import (
+	"net/http"
+	"testing"
+
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExists(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("HEAD", "/files/report.pdf").Reply(http.StatusOK, "")
+	mock.On("HEAD", "/files/missing.pdf").Reply(http.StatusNotFound, "")
+	mock.On("HEAD", "/legacy/report.pdf").Reply(http.StatusMethodNotAllowed, "")
+	mock.On("GET", "/legacy/report.pdf").Reply(http.StatusOK, "a large body")
+	mock.On("HEAD", "/private/report.pdf").Reply(http.StatusForbidden, "")
+
+	c := NewClient(WithTransport(mock), WithBaseURL("https://files.example.com"))
+
+	exists, resp, err := Exists(WithClient(c), WithUrl("/files/report.pdf"))
+
+	assert.Nil(t, err, "Should probe existing resources")
+	assert.True(t, exists, "Should report 2xx as existing")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "Should return the response")
+
+	exists, _, err = Exists(WithClient(c), WithUrl("/files/missing.pdf"))
+
+	assert.Nil(t, err, "Should probe missing resources")
+	assert.False(t, exists, "Should report 404 as missing")
+
+	exists, resp, err = Exists(WithClient(c), WithUrl("/legacy/report.pdf"))
+
+	assert.Nil(t, err, "Should fall back to GET")
+	assert.True(t, exists, "Should report the result of the GET")
+	assert.Equal(t, "GET", resp.Request.Method, "Should return the response to the GET")
+
+	exists, resp, err = Exists(WithClient(c), WithUrl("/private/report.pdf"))
+
+	assert.EqualError(t, err, "Cannot tell whether the resource exists: status 403", "Should report other statuses")
+	assert.False(t, exists, "Should not report resources of other statuses as existing")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "Should return the response along with the error")
+
+	assert.Len(t, mock.Calls(), 5, "Should send a single request per probe, and a GET on fallback")
+}
@@ -0,0 +1,105 @@
+package gorequest
+
+import (
+	"expvar"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"sync"
+)
+
+/**
+ * Basic client counters published as an expvar map. expvar cannot
+ * unpublish a name, so the map of a name is published once and shared by
+ * every client built with it: their counters add up, and in_flight counts
+ * the requests of those not closed yet.
+ */
+type expvarStats struct {
+	bytesReceived *expvar.Int
+	bytesSent     *expvar.Int
+	clients       map[*client]struct{}
+	errors        *expvar.Int
+	mutex         sync.Mutex
+	requests      *expvar.Int
+}
+
+var published = struct {
+	mutex sync.Mutex
+	stats map[string]*expvarStats
+}{stats: make(map[string]*expvarStats)}
+
+func newExpvarStats(name string, owner *client) *expvarStats {
+
+	published.mutex.Lock()
+	defer published.mutex.Unlock()
+
+	stats, ok := published.stats[name]
+
+	if !ok {
+
+		if expvar.Get(name) != nil {
+			panic(fmt.Errorf("Expvar %s is already published", name))
+		}
+
+		stats = &expvarStats{
+			bytesReceived: new(expvar.Int),
+			bytesSent:     new(expvar.Int),
+			clients:       make(map[*client]struct{}),
+			errors:        new(expvar.Int),
+			requests:      new(expvar.Int),
+		}
+
+		vars := expvar.NewMap(name)
+		vars.Set("bytes_received", stats.bytesReceived)
+		vars.Set("bytes_sent", stats.bytesSent)
+		vars.Set("errors", stats.errors)
+		vars.Set("in_flight", expvar.Func(stats.inFlight))
+		vars.Set("requests", stats.requests)
+
+		published.stats[name] = stats
+	}
+
+	stats.mutex.Lock()
+	stats.clients[owner] = struct{}{}
+	stats.mutex.Unlock()
+
+	return stats
+}
+
+func (s *expvarStats) inFlight() interface{} {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var active int32
+
+	for c := range s.clients {
+		active += c.active()
+	}
+
+	return active
+}
+
+/**
+ * Stops counting the requests of a closed client, and lets it be collected.
+ */
+func (s *expvarStats) release(c *client) {
+	s.mutex.Lock()
+	delete(s.clients, c)
+	s.mutex.Unlock()
+}
+
+/**
+ * model.Observer implementation.
+ */
+func (s *expvarStats) ObserveExchange(exchange model.Exchange) {
+	s.requests.Add(1)
+	if exchange.Err != nil {
+		s.errors.Add(1)
+	}
+	if exchange.BytesSent > 0 {
+		s.bytesSent.Add(exchange.BytesSent)
+	}
+	if exchange.BytesReceived > 0 {
+		s.bytesReceived.Add(exchange.BytesReceived)
+	}
+}
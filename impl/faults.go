@@ -0,0 +1,91 @@
+package gorequest
+
+import (
+	"bytes"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+)
+
+/**
+ * Injects the faults configured on a client. Every matching fault is rolled
+ * independently, in order; the first error or status injected ends the
+ * request.
+ */
+type faultInjector struct {
+	clock  model.Clock
+	faults []model.Fault
+}
+
+/**
+ * Applies the faults matching req. Reports true when the request must not
+ * be sent, with the injected outcome.
+ */
+func (f *faultInjector) inject(req *http.Request) (*http.Response, bool, error) {
+
+	for _, fault := range f.faults {
+
+		if !faultMatches(fault, req) || rand.Float64()*100 >= fault.Percentage {
+			continue
+		}
+
+		if fault.Latency > 0 {
+			select {
+			case <-f.clock.After(fault.Latency):
+			case <-req.Context().Done():
+				return nil, true, req.Context().Err()
+			}
+		}
+
+		if fault.ConnectionError {
+			return nil, true, &url.Error{
+				Op:  urlErrorOp(req.Method),
+				URL: req.URL.String(),
+				Err: &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)},
+			}
+		}
+
+		if fault.StatusCode != 0 {
+			return injectedResponse(req, fault.StatusCode), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func faultMatches(fault model.Fault, req *http.Request) bool {
+	if fault.Host != "" && !strings.EqualFold(fault.Host, req.URL.Hostname()) {
+		return false
+	}
+	return strings.HasPrefix(req.URL.Path, fault.PathPrefix)
+}
+
+func injectedResponse(req *http.Request, status int) *http.Response {
+	return &http.Response{
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Header:     make(http.Header),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Request:    req,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode: status,
+	}
+}
+
+/**
+ * Mirrors the Op of the errors returned by http.Client.
+ */
+func urlErrorOp(method string) string {
+	if method == "" {
+		return "Get"
+	}
+	return method[:1] + strings.ToLower(method[1:])
+}
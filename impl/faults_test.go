@@ -0,0 +1,90 @@
+package gorequest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultInjectionStatus(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithFaultInjection(model.Fault{PathPrefix: "/orders", Percentage: 100, StatusCode: 503}).Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL + "/orders/1").WithClient(c).Build().Do()
+
+	assert.Equal(t, 503, response.Response().StatusCode, "Should equal the injected status")
+	assert.Equal(t, 0, calls, "Should not contact the upstream")
+
+	response = NewRequestBuilder().WithUrl(ts.URL + "/customers/1").WithClient(c).Build().Do()
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should not affect other paths")
+	assert.Equal(t, 1, calls, "Should contact the upstream")
+}
+
+func TestFaultInjectionConnectionErrorIsRetried(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().
+		WithFaultInjection(model.Fault{Host: "127.0.0.1", Percentage: 100, ConnectionError: true, Latency: 10 * time.Millisecond}).
+		WithRetry(model.Retry{MaxAttempts: 3, BaseDelay: time.Millisecond}).
+		Build()
+
+	start := time.Now()
+
+	defer func() {
+		err := recover().(error)
+
+		assert.True(t, errors.Is(err, syscall.ECONNREFUSED), "Should fail with a refused connection")
+		assert.Equal(t, int64(2), c.RetryStats().Retries, "Should retry injected connection errors")
+		assert.True(t, time.Since(start) >= 30*time.Millisecond, "Should inject latency on every attempt")
+	}()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+}
+
+func TestFaultInjectionPercentage(t *testing.T) {
+	injector := &faultInjector{clock: systemClock{}, faults: []model.Fault{{Percentage: 0, StatusCode: 500}}}
+
+	req, _ := http.NewRequest("GET", "http://example.com/", nil)
+
+	_, injected, _ := injector.inject(req)
+
+	assert.False(t, injected, "Should never inject a fault at 0%")
+}
+
+func TestFaultLatencyFollowsClock(t *testing.T) {
+	clock := requestmock.NewClock(time.Now()).AutoAdvance()
+	mock := requestmock.New().WithClock(clock)
+	mock.On("GET", "/slow").Delay(time.Hour).Reply(http.StatusOK, "slow")
+
+	c := NewClientBuilder().
+		WithTransport(mock).
+		WithClock(clock).
+		WithFaultInjection(model.Fault{PathPrefix: "/slow", Percentage: 100, Latency: time.Hour}).
+		Build()
+
+	start := time.Now()
+	clockStart := clock.Now()
+
+	response := NewRequestBuilder().WithUrl("https://api.example.com/slow").WithClient(c).Build().Do()
+
+	assert.Equal(t, "slow", string(response.Body()), "Should reply")
+	assert.True(t, time.Since(start) < time.Second, "Should not wait on the system clock")
+	assert.Equal(t, 2*time.Hour, clock.Now().Sub(clockStart), "Should wait for the injected latency and the stub delay on the clock")
+}
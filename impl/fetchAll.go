@@ -0,0 +1,257 @@
+package gorequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+/**
+ * Fetches the first page of a collection from options, then every page
+ * after it, and collects their items as pagination asks. Pages failing
+ * with a status of 400 or above fail the walk. Pages on another origin than
+ * the first one are fetched without credentials.
+ */
+func FetchAll(ctx context.Context, options []model.Option, pagination model.Pagination) error {
+
+	var into reflect.Value
+
+	if pagination.Into != nil {
+		into = reflect.ValueOf(pagination.Into)
+		if into.Kind() != reflect.Ptr || into.Elem().Kind() != reflect.Slice {
+			panic(errors.New("Pagination.Into must be a pointer to a slice"))
+		}
+		into = into.Elem()
+	}
+
+	if pagination.Next == nil {
+		pagination.Next = nextLink
+	}
+
+	if pagination.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pagination.MaxDuration)
+		defer cancel()
+	}
+
+	options = append(append([]model.Option(nil), options...), WithContext(ctx))
+
+	first, err := url.Parse(newRequestBuilder(options).(*requestBuilder).url)
+
+	if err != nil {
+		return fmt.Errorf("Invalid URL of page 1: %w", err)
+	}
+
+	current := first
+	pageOptions := options
+
+	for number := 1; ; number++ {
+
+		if pagination.MaxPages > 0 && number > pagination.MaxPages {
+			return model.ErrPageLimit
+		}
+
+		page, err := NewRequest(pageOptions...).Send()
+
+		if err != nil {
+			return err
+		}
+
+		if status := page.Response().StatusCode; status >= http.StatusBadRequest {
+			return fmt.Errorf("Page %d failed with status %d", number, status)
+		}
+
+		items, err := pageItems(page.Body(), pagination.Items)
+
+		if err != nil {
+			return fmt.Errorf("Cannot read the items of page %d: %w", number, err)
+		}
+
+		for _, item := range items {
+			if pagination.Each != nil {
+				if err := pagination.Each(item); err != nil {
+					return err
+				}
+			}
+			if into.IsValid() {
+				value := reflect.New(into.Type().Elem())
+				if err := json.Unmarshal(item, value.Interface()); err != nil {
+					return fmt.Errorf("Cannot decode an item of page %d: %w", number, err)
+				}
+				into.Set(reflect.Append(into, value.Elem()))
+			}
+		}
+
+		next, err := pagination.Next(pageAt(page, current))
+
+		if err != nil || next == "" {
+			return err
+		}
+
+		target, err := current.Parse(next)
+
+		if err != nil {
+			return fmt.Errorf("Invalid URL of page %d: %w", number+1, err)
+		}
+
+		current = target
+		pageOptions = append(options[:len(options):len(options)], WithUrl(target.String()))
+
+		// a next link must not leak the credentials of the API to another host
+		if !sameOrigin(first, target) {
+			pageOptions = append(pageOptions, model.Option{Request: withoutCredentials})
+		}
+	}
+}
+
+/**
+ * A page whose response reports the URL it was fetched from, which static
+ * responses (stubs, memoized copies...) may not.
+ */
+type fetchedPage struct {
+	pageResponse
+	response *http.Response
+}
+
+type pageResponse interface {
+	model.Response
+}
+
+func (p *fetchedPage) Response() *http.Response {
+	return p.response
+}
+
+func pageAt(page model.Response, pageURL *url.URL) model.Response {
+
+	if page.Response().Request != nil {
+		return page
+	}
+
+	response := *page.Response()
+	response.Request = &http.Request{Method: http.MethodGet, URL: pageURL, Header: http.Header{}}
+
+	return &fetchedPage{pageResponse: page, response: &response}
+}
+
+func withoutCredentials(builder model.RequestBuilder) {
+	b := builder.(*requestBuilder)
+	b.auth = newAuthNone()
+	for name := range b.headers {
+		if containsHeader(sensitiveRedirectHeaders, name) {
+			delete(b.headers, name)
+		}
+	}
+}
+
+/**
+ * Returns the items held by the member at path of body.
+ */
+func pageItems(body []byte, path string) ([]json.RawMessage, error) {
+
+	member := json.RawMessage(body)
+
+	if path != "" {
+		for _, name := range strings.Split(path, ".") {
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(member, &object); err != nil {
+				return nil, err
+			}
+			member = object[name]
+		}
+	}
+
+	var items []json.RawMessage
+
+	if len(member) == 0 || string(member) == "null" {
+		return nil, nil
+	}
+
+	err := json.Unmarshal(member, &items)
+
+	return items, err
+}
+
+/**
+ * The default Pagination.Next: the rel="next" target of the Link header.
+ */
+func nextLink(page model.Response) (string, error) {
+
+	for _, header := range page.Response().Header.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				pair := strings.SplitN(strings.TrimSpace(param), "=", 2)
+				if len(pair) != 2 || !strings.EqualFold(pair[0], "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(pair[1], `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1], nil
+					}
+				}
+			}
+		}
+	}
+
+	return "", nil
+}
+
+/**
+ * Returns a Pagination.Next for cursor pagination: the next page is the
+ * current one with query parameter param set to the cursor found at the
+ * dot-separated path field of its body, until the cursor is missing, null
+ * or empty.
+ */
+func NextCursor(param string, field string) func(page model.Response) (string, error) {
+	return func(page model.Response) (string, error) {
+
+		member := json.RawMessage(page.Body())
+
+		for _, name := range strings.Split(field, ".") {
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(member, &object); err != nil {
+				return "", fmt.Errorf("Cannot read the cursor of a page: %w", err)
+			}
+			member = object[name]
+		}
+
+		if len(member) == 0 || string(member) == "null" {
+			return "", nil
+		}
+
+		var cursor string
+
+		if err := json.Unmarshal(member, &cursor); err != nil {
+			// numeric cursors are used as written
+			var number json.Number
+			if json.Unmarshal(member, &number) != nil {
+				return "", fmt.Errorf("Cannot read the cursor of a page: %w", err)
+			}
+			cursor = number.String()
+		}
+
+		if cursor == "" {
+			return "", nil
+		}
+
+		next := *page.Response().Request.URL
+		query := next.Query()
+		query.Set(param, cursor)
+		next.RawQuery = query.Encode()
+
+		return next.String(), nil
+	}
+}
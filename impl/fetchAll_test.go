@@ -0,0 +1,161 @@
+package gorequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAll(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/users").MatchQuery("page", "2").
+		ReplyHeader("Link", `</users?page=1>; rel="prev", </users?page=3>; rel="next last"`).
+		ReplyJSON(http.StatusOK, map[string]interface{}{"data": []map[string]string{{"name": "c"}}})
+	mock.On("GET", "/users").MatchQuery("page", "3").
+		ReplyHeader("Link", `</users?page=2>; rel="prev"`).
+		ReplyJSON(http.StatusOK, map[string]interface{}{"data": []map[string]string{{"name": "d"}}})
+	mock.On("GET", "/users").
+		ReplyHeader("Link", `<https://api.example.com/users?page=2>; rel="next"`).
+		ReplyJSON(http.StatusOK, map[string]interface{}{"data": []map[string]string{{"name": "a"}, {"name": "b"}}})
+
+	options := []model.Option{WithClient(NewClient(WithTransport(mock))), WithUrl("https://api.example.com/users")}
+
+	var users []struct{ Name string }
+	streamed := 0
+
+	err := FetchAll(context.Background(), options, model.Pagination{
+		Each:  func(item json.RawMessage) error { streamed++; return nil },
+		Into:  &users,
+		Items: "data",
+	})
+
+	assert.Nil(t, err, "Should fetch every page")
+	assert.Equal(t, []struct{ Name string }{{"a"}, {"b"}, {"c"}, {"d"}}, users, "Should append the items of every page in order")
+	assert.Equal(t, 4, streamed, "Should pass every item to Each")
+	assert.Len(t, mock.Calls(), 3, "Should follow rel=next until the last page")
+
+	users = nil
+
+	err = FetchAll(context.Background(), options, model.Pagination{Into: &users, Items: "data", MaxPages: 2})
+
+	assert.True(t, errors.Is(err, model.ErrPageLimit), "Should fail past MaxPages, got %v", err)
+	assert.Len(t, users, 3, "Should keep the items of the pages fetched")
+
+	stop := errors.New("Enough")
+
+	err = FetchAll(context.Background(), options, model.Pagination{
+		Each:  func(item json.RawMessage) error { return stop },
+		Items: "data",
+	})
+
+	assert.Equal(t, stop, err, "Should stop on errors of Each")
+}
+
+func TestFetchAllCursor(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/events").MatchQuery("cursor", "abc").
+		ReplyJSON(http.StatusOK, map[string]interface{}{"events": []int{3}, "meta": map[string]interface{}{"next": nil}})
+	mock.On("GET", "/events").
+		ReplyJSON(http.StatusOK, map[string]interface{}{"events": []int{1, 2}, "meta": map[string]string{"next": "abc"}})
+
+	var events []int
+
+	err := FetchAll(context.Background(),
+		[]model.Option{WithClient(NewClient(WithTransport(mock))), WithUrl("https://api.example.com/events?limit=2")},
+		model.Pagination{Into: &events, Items: "events", Next: NextCursor("cursor", "meta.next")})
+
+	assert.Nil(t, err, "Should fetch every page")
+	assert.Equal(t, []int{1, 2, 3}, events, "Should collect the items of every page")
+
+	calls := mock.Calls()
+
+	assert.Len(t, calls, 2, "Should stop when the cursor is null")
+	assert.Equal(t, "https://api.example.com/events?cursor=abc&limit=2", calls[1].URL.String(), "Should keep the query and set the cursor")
+
+	mock.Reset()
+	mock.On("GET", "/events").Reply(http.StatusInternalServerError, "")
+
+	err = FetchAll(context.Background(),
+		[]model.Option{WithClient(NewClient(WithTransport(mock))), WithUrl("https://api.example.com/events")},
+		model.Pagination{Into: &events})
+
+	assert.EqualError(t, err, "Page 1 failed with status 500", "Should fail on error statuses")
+
+	assert.Panics(t, func() {
+		FetchAll(context.Background(), nil, model.Pagination{Into: events})
+	}, "Should panic when Into is not a pointer to a slice")
+}
+
+/**
+ * Answers with responses that do not report their request, as stubs may.
+ */
+type staticPages struct {
+	calls []*http.Request
+	pages map[string]*http.Response
+}
+
+func (s *staticPages) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.calls = append(s.calls, req)
+	page, ok := s.pages[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody}, nil
+	}
+	response := *page
+	return &response, nil
+}
+
+func staticPage(header http.Header, body string) *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestFetchAllCursorOfStaticPages(t *testing.T) {
+	transport := &staticPages{pages: map[string]*http.Response{
+		"https://api.example.com/events":                staticPage(http.Header{}, `{"events": [1], "next": "a b&c"}`),
+		"https://api.example.com/events?cursor=a+b%26c": staticPage(http.Header{}, `{"events": [2], "next": 42}`),
+		"https://api.example.com/events?cursor=42":      staticPage(http.Header{}, `{"events": [3], "next": ""}`),
+	}}
+
+	var events []int
+
+	err := FetchAll(context.Background(),
+		[]model.Option{WithClient(NewClient(WithTransport(transport))), WithUrl("https://api.example.com/events")},
+		model.Pagination{Into: &events, Items: "events", Next: NextCursor("cursor", "next")})
+
+	assert.Nil(t, err, "Should fetch every page")
+	assert.Equal(t, []int{1, 2, 3}, events, "Should decode string and numeric cursors")
+}
+
+func TestFetchAllDropsCredentialsAcrossHosts(t *testing.T) {
+	transport := &staticPages{pages: map[string]*http.Response{
+		"https://api.example.com/users":        staticPage(http.Header{"Link": {`<https://cdn.example.net/users?page=2>; rel="next"`}}, `[1]`),
+		"https://cdn.example.net/users?page=2": staticPage(http.Header{}, `[2]`),
+	}}
+
+	var users []int
+
+	err := FetchAll(context.Background(),
+		[]model.Option{
+			WithClient(NewClient(WithTransport(transport))),
+			WithUrl("https://api.example.com/users"),
+			WithBearerAuth("secret"),
+			WithHeader("Cookie", "session=secret"),
+		},
+		model.Pagination{Into: &users})
+
+	assert.Nil(t, err, "Should fetch every page")
+	assert.Equal(t, []int{1, 2}, users, "Should follow the link to the other host")
+	assert.Len(t, transport.calls, 2, "Should fetch both pages")
+	assert.Equal(t, "Bearer secret", transport.calls[0].Header.Get("Authorization"), "Should authenticate to the API")
+	assert.Empty(t, transport.calls[1].Header.Get("Authorization"), "Should not send the token to another host")
+	assert.Empty(t, transport.calls[1].Header.Get("Cookie"), "Should not send cookies to another host")
+}
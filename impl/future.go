@@ -0,0 +1,98 @@
+package gorequest
+
+import (
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+)
+
+/****************************************************
+ * model.Future implementation
+ ****************************************************/
+
+type future struct {
+	done   chan struct{}
+	result model.Result
+}
+
+func newFuture() *future {
+	return &future{
+		done: make(chan struct{}),
+	}
+}
+
+/**
+ * Sends req on its own goroutine and returns a handle on its outcome.
+ */
+func Async(req model.Request) model.Future {
+
+	f := newFuture()
+
+	go func() {
+		f.complete(run(req))
+	}()
+
+	return f
+}
+
+/**
+ * Returns a Future completing with the first successful result among
+ * futures, or with the error of the last one to fail if none succeeds.
+ */
+func First(futures ...model.Future) model.Future {
+
+	f := newFuture()
+
+	if len(futures) == 0 {
+		f.complete(model.Result{Err: errors.New("No futures to wait for")})
+		return f
+	}
+
+	results := make(chan model.Result, len(futures))
+
+	for _, pending := range futures {
+		go func(pending model.Future) {
+			results <- pending.Result()
+		}(pending)
+	}
+
+	go func() {
+		var result model.Result
+		for range futures {
+			if result = <-results; result.Err == nil {
+				break
+			}
+		}
+		f.complete(result)
+	}()
+
+	return f
+}
+
+/**
+ * Waits for every future and returns their results, in order, failures
+ * included.
+ */
+func AllSettled(futures ...model.Future) []model.Result {
+
+	results := make([]model.Result, len(futures))
+
+	for i, pending := range futures {
+		results[i] = pending.Result()
+	}
+
+	return results
+}
+
+func (f *future) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *future) Result() model.Result {
+	<-f.done
+	return f.result
+}
+
+func (f *future) complete(result model.Result) {
+	f.result = result
+	close(f.done)
+}
@@ -0,0 +1,43 @@
+package gorequest
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	gen "github.com/demianlessa/gorequest/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	spec, err := ioutil.ReadFile(filepath.Join("testdata", "pets.yaml"))
+
+	assert.Nil(t, err, "Should read the document")
+
+	source, err := gen.Generate(spec, gen.Options{Package: "pets"})
+
+	assert.Nil(t, err, "Should generate the client")
+
+	_, err = parser.ParseFile(token.NewFileSet(), "client.go", source, 0)
+
+	assert.Nil(t, err, "Should generate valid Go")
+
+	golden, err := ioutil.ReadFile(filepath.Join("testdata", "pets_client.golden"))
+
+	assert.Nil(t, err, "Should read the golden file")
+	assert.Equal(t, string(golden), string(source), "Should match the golden file")
+
+	_, err = gen.Generate(spec, gen.Options{})
+
+	assert.NotNil(t, err, "Should require a package name")
+
+	_, err = gen.Generate([]byte("openapi: 3.0.0\ncomponents:\n  schemas:\n    Client:\n      type: object\n"), gen.Options{Package: "api"})
+
+	assert.NotNil(t, err, "Should reject schemas named like the generated types")
+
+	_, err = gen.Generate([]byte("swagger: \"2.0\"\n"), gen.Options{Package: "api"})
+
+	assert.NotNil(t, err, "Should reject Swagger 2.0 documents")
+}
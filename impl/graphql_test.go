@@ -0,0 +1,76 @@
+package gorequest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	graphql "github.com/demianlessa/gorequest/graphql"
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQL(t *testing.T) {
+	query := `query($id: ID!) { user(id: $id) { name friends } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+
+	mock := requestmock.New()
+
+	var payloads []map[string]interface{}
+
+	record := func(req *http.Request, body []byte) bool {
+		var payload map[string]interface{}
+		json.Unmarshal(body, &payload)
+		payloads = append(payloads, payload)
+		return true
+	}
+
+	mock.On("POST", "/graphql").Match(record).MatchJSON(`{"extensions":{"persistedQuery":{"sha256Hash":"` + hash + `","version":1}},"variables":{"id":1}}`).ReplyJSON(http.StatusOK, map[string]interface{}{
+		"errors": []interface{}{map[string]interface{}{"message": "PersistedQueryNotFound"}},
+	})
+	mock.On("POST", "/graphql").Match(record).MatchHeader("Authorization", "Bearer token").ReplyJSON(http.StatusOK, map[string]interface{}{
+		"data":   map[string]interface{}{"user": map[string]interface{}{"name": "alice"}},
+		"errors": []interface{}{map[string]interface{}{"message": "friends unavailable", "path": []interface{}{"user", "friends"}}},
+	})
+	mock.On("POST", "/broken").Reply(http.StatusBadGateway, "<html>Bad Gateway</html>")
+
+	c := NewClientBuilder().WithTransport(mock).Build().Clone(model.ClientOverrides{Auth: newAuthBearer("token")})
+
+	gql := graphql.NewClient(NewDoer(c), "https://api.example.com/graphql")
+	gql.PersistedQueries = true
+
+	var result struct {
+		User struct {
+			Name string
+		}
+	}
+
+	err := gql.Query(context.Background(), query, map[string]interface{}{"id": 1}, &result)
+
+	assert.Equal(t, "alice", result.User.Name, "Should decode partial data")
+	assert.Equal(t, "GraphQL errors: friends unavailable", err.Error(), "Should return the GraphQL errors")
+	assert.Equal(t, []interface{}{"user", "friends"}, err.(graphql.Errors)[0].Path, "Should return the GraphQL errors")
+
+	assert.Nil(t, payloads[0]["query"], "Should send the hash first")
+	assert.Equal(t, query, payloads[len(payloads)-1]["query"], "Should send the query once the server asks for it")
+	assert.Equal(t, payloads[0]["extensions"], payloads[len(payloads)-1]["extensions"], "Should send the same hash")
+
+	err = graphql.NewClient(NewDoer(c), "https://api.example.com/broken").Query(context.Background(), "{ ping }", nil, nil)
+
+	assert.Equal(t, http.StatusBadGateway, err.(*graphql.StatusError).StatusCode, "Should fail on non-GraphQL responses")
+
+	err = graphql.NewClient(NewDoer(c), "http://[::1").Query(context.Background(), "{ ping }", nil, nil)
+
+	assert.NotNil(t, err, "Should return errors building the request")
+
+	err = graphql.NewClient(NewDoer(c), "https://api.example.com/unknown").Query(context.Background(), "{ ping }", nil, nil)
+
+	var unmatched *requestmock.UnmatchedError
+	assert.True(t, errors.As(err, &unmatched), "Should return transport errors as they are")
+}
@@ -0,0 +1,278 @@
+package gorequest
+
+import (
+	"encoding/json"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+/****************************************************
+ * model.HARRecorder implementation
+ ****************************************************/
+
+type harRecorder struct {
+	entries    []harEntry
+	maxEntries int
+	mutex      sync.Mutex
+	recording  bool
+}
+
+/**
+ * Returns a recorder keeping at most maxEntries entries, the oldest ones
+ * being dropped first; zero means no limit. The recorder starts recording
+ * right away.
+ */
+func NewHARRecorder(maxEntries int) model.HARRecorder {
+	return &harRecorder{
+		maxEntries: maxEntries,
+		recording:  true,
+	}
+}
+
+func (r *harRecorder) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.entries)
+}
+
+func (r *harRecorder) Log(entry model.LogEntry) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.recording {
+		return
+	}
+
+	r.entries = append(r.entries, newHAREntry(entry))
+
+	if r.maxEntries > 0 && len(r.entries) > r.maxEntries {
+		r.entries = append(r.entries[:0:0], r.entries[len(r.entries)-r.maxEntries:]...)
+	}
+}
+
+func (r *harRecorder) Recording() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.recording
+}
+
+func (r *harRecorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = nil
+}
+
+func (r *harRecorder) Start() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.recording = true
+}
+
+func (r *harRecorder) Stop() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.recording = false
+}
+
+func (r *harRecorder) WriteTo(w io.Writer) (int64, error) {
+
+	r.mutex.Lock()
+	entries := append([]harEntry{}, r.entries...)
+	r.mutex.Unlock()
+
+	data, err := json.MarshalIndent(harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "gorequest", Version: "1.0"},
+		Entries: entries,
+	}}, "", "  ")
+
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(data)
+
+	return int64(n), err
+}
+
+/****************************************************
+ * HAR 1.2 document
+ ****************************************************/
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+/**
+ * Only the total time is known; it is reported as waiting time.
+ */
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHAREntry(entry model.LogEntry) harEntry {
+
+	milliseconds := float64(entry.Duration) / float64(time.Millisecond)
+	proto := entry.Proto
+
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+
+	har := harEntry{
+		StartedDateTime: entry.StartedAt.Format(time.RFC3339Nano),
+		Time:            milliseconds,
+		Request: harRequest{
+			Method:      entry.Method,
+			URL:         entry.URL,
+			HTTPVersion: proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(entry.RequestHeader),
+			QueryString: harQuery(entry.URL),
+			HeadersSize: -1,
+			BodySize:    entry.BytesSent,
+		},
+		Response: harResponse{
+			Status:      entry.StatusCode,
+			StatusText:  http.StatusText(entry.StatusCode),
+			HTTPVersion: proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(entry.ResponseHeader),
+			Content: harContent{
+				Size:     entry.BytesReceived,
+				MimeType: entry.ResponseHeader.Get("Content-Type"),
+				Text:     string(entry.ResponseBody),
+			},
+			HeadersSize: -1,
+			BodySize:    entry.BytesReceived,
+		},
+		Timings: harTimings{Send: 0, Wait: milliseconds, Receive: 0},
+	}
+
+	if entry.RequestBody != nil {
+		har.Request.PostData = &harPostData{
+			MimeType: entry.RequestHeader.Get("Content-Type"),
+			Text:     string(entry.RequestBody),
+		}
+	}
+
+	if entry.Err != nil {
+		har.Error = entry.Err.Error()
+	}
+
+	return har
+}
+
+func harHeaders(header http.Header) []harNameValue {
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := []harNameValue{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return pairs
+}
+
+func harQuery(rawURL string) []harNameValue {
+
+	pairs := []harNameValue{}
+
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return pairs
+	}
+
+	query := u.Query()
+
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return pairs
+}
@@ -0,0 +1,180 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var defaultHealthCheckInterval time.Duration = 10 * time.Second
+var defaultHealthCheckTimeout time.Duration = 5 * time.Second
+
+/**
+ * Endpoint selectors whose endpoints can be health checked.
+ */
+type healthTarget interface {
+	// Returns the base URLs currently in use.
+	targets() []*url.URL
+	// Marks an endpoint as failing (or passing) its health checks.
+	setDown(target *url.URL, down bool)
+}
+
+type healthStatus struct {
+	down      bool
+	failures  int
+	successes int
+}
+
+/**
+ * Probes the endpoints of a target periodically until stopped.
+ */
+type healthChecker struct {
+	httpClient *http.Client
+	settings   model.HealthCheck
+	status     map[string]*healthStatus
+	stop       chan struct{}
+	target     healthTarget
+}
+
+func newHealthChecker(settings model.HealthCheck, target healthTarget, httpClient *http.Client) *healthChecker {
+
+	if settings.Method == "" {
+		settings.Method = http.MethodGet
+	}
+	if settings.Interval <= 0 {
+		settings.Interval = defaultHealthCheckInterval
+	}
+	if settings.Timeout <= 0 {
+		settings.Timeout = defaultHealthCheckTimeout
+	}
+	if settings.UnhealthyThreshold < 1 {
+		settings.UnhealthyThreshold = 1
+	}
+	if settings.HealthyThreshold < 1 {
+		settings.HealthyThreshold = 1
+	}
+
+	return &healthChecker{
+		httpClient: httpClient,
+		settings:   settings,
+		status:     make(map[string]*healthStatus),
+		stop:       make(chan struct{}),
+		target:     target,
+	}
+}
+
+func (c *healthChecker) start() *healthChecker {
+	go c.run()
+	return c
+}
+
+/**
+ * Stops probing the endpoints.
+ */
+func (c *healthChecker) close() {
+	close(c.stop)
+}
+
+func (c *healthChecker) run() {
+
+	ticker := time.NewTicker(c.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.checkAll()
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *healthChecker) checkAll() {
+
+	targets := c.target.targets()
+	results := make([]bool, len(targets))
+
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target *url.URL) {
+			defer wg.Done()
+			results[i] = c.check(target)
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	// status is only touched by the checker goroutine
+	current := make(map[string]bool, len(targets))
+	for i, target := range targets {
+		current[target.String()] = true
+		c.record(target, results[i])
+	}
+
+	// forget endpoints removed by discovery
+	for key := range c.status {
+		if !current[key] {
+			delete(c.status, key)
+		}
+	}
+}
+
+func (c *healthChecker) check(target *url.URL) bool {
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.settings.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, c.settings.Method, resolveEndpoint(target, &url.URL{Path: c.settings.Path}).String(), nil)
+
+	if err != nil {
+		return false
+	}
+
+	resp, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return false
+	}
+
+	// the connection only returns to the pool once the body is consumed
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	return resp.StatusCode < 400
+}
+
+func (c *healthChecker) record(target *url.URL, passed bool) {
+
+	status, ok := c.status[target.String()]
+
+	if !ok {
+		status = &healthStatus{}
+		c.status[target.String()] = status
+	}
+
+	if passed {
+		status.failures = 0
+		status.successes++
+		if status.down && status.successes >= c.settings.HealthyThreshold {
+			status.down = false
+			c.target.setDown(target, false)
+		}
+		return
+	}
+
+	status.successes = 0
+	status.failures++
+
+	if !status.down && status.failures >= c.settings.UnhealthyThreshold {
+		status.down = true
+		c.target.setDown(target, true)
+	}
+}
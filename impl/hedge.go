@@ -0,0 +1,108 @@
+package gorequest
+
+import (
+	"context"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+type hedgeResult struct {
+	err  error
+	resp *response
+}
+
+/**
+ * Sends req and, every policy.Delay without a successful response, a copy of
+ * it. Returns the first successful outcome and cancels the copies still in
+ * flight; when every copy fails, the outcome of the last one is returned.
+ * The responses of the other copies are discarded, including those arriving
+ * after the winner, so that streamed bodies do not hold on to connections.
+ */
+func hedge(policy *model.Hedge, clock model.Clock, req *http.Request, send func(*http.Request) (*response, error)) (*response, error) {
+
+	copies := 1 + policy.MaxHedges
+
+	if policy.MaxHedges <= 0 {
+		copies = 2
+	}
+
+	results := make(chan hedgeResult, copies)
+	cancels := make([]context.CancelFunc, 0, copies)
+
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	launch := func() error {
+		ctx, cancel := context.WithCancel(req.Context())
+		clone, err := cloneRequest(req.WithContext(ctx), req.URL)
+		if err != nil {
+			cancel()
+			return err
+		}
+		cancels = append(cancels, cancel)
+		go func() {
+			defer func() {
+				// copies run on their own goroutine, a panic must not escape it
+				if p := recover(); p != nil {
+					results <- hedgeResult{err: panicError(p)}
+				}
+			}()
+			resp, err := send(clone)
+			results <- hedgeResult{err: err, resp: resp}
+		}()
+		return nil
+	}
+
+	if err := launch(); err != nil {
+		return nil, err
+	}
+
+	delay := clock.After(policy.Delay)
+
+	pending := 1
+	var last hedgeResult
+
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+			if !failedExchange(result.resp, result.err) {
+				last.resp.discard()
+				// cancelled copies still report, their responses are dropped
+				go discardHedges(results, pending)
+				return result.resp, result.err
+			}
+			last.resp.discard()
+			last = result
+			// no point in waiting for the delay after a failure
+			if len(cancels) < copies && launch() == nil {
+				pending++
+			}
+		case <-delay:
+			if len(cancels) < copies && launch() == nil {
+				pending++
+				delay = clock.After(policy.Delay)
+			}
+		}
+	}
+
+	return last.resp, last.err
+}
+
+func discardHedges(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		result := <-results
+		result.resp.discard()
+	}
+}
+
+func panicError(p interface{}) error {
+	if err, ok := p.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", p)
+}
@@ -0,0 +1,134 @@
+package gorequest
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedgedRequestFirstResponseWins(t *testing.T) {
+	var calls int32
+	cancelled := make(chan struct{}, 1)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// the original request stalls until it is cancelled
+			select {
+			case <-req.Context().Done():
+				cancelled <- struct{}{}
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		fmt.Fprint(resp, "hedge")
+	}))
+
+	defer ts.Close()
+
+	start := time.Now()
+	response := NewRequestBuilder().WithUrl(ts.URL).WithHedging(model.Hedge{Delay: 20 * time.Millisecond}).Build().Do()
+
+	assert.Equal(t, "hedge", string(response.Body()), "Should use the hedged response")
+	assert.True(t, time.Since(start) < time.Second, "Should not wait for the slow request")
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		assert.True(t, false, "Should cancel the slow request")
+	}
+}
+
+func TestHedgingSkipsNonIdempotentRequests(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(30 * time.Millisecond)
+	}))
+
+	defer ts.Close()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithMethod("POST").WithHedging(model.Hedge{Delay: time.Millisecond}).Build().Do()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "Should send POST only once")
+}
+
+func TestHedgeDelayFollowsClock(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-req.Context().Done()
+			return
+		}
+		fmt.Fprint(resp, "hedge")
+	}))
+
+	defer ts.Close()
+
+	clock := requestmock.NewClock(time.Now())
+	c := NewClientBuilder().WithClock(clock).Build()
+
+	done := make(chan model.Response, 1)
+	go func() {
+		done <- NewRequestBuilder().WithClient(c).WithUrl(ts.URL).WithHedging(model.Hedge{Delay: time.Hour}).Build().Do()
+	}()
+
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "Should wait for the clock before hedging")
+
+	clock.Advance(time.Hour)
+
+	select {
+	case response := <-done:
+		assert.Equal(t, "hedge", string(response.Body()), "Should hedge once the clock reaches the delay")
+	case <-time.After(5 * time.Second):
+		assert.True(t, false, "Should hedge once the clock reaches the delay")
+	}
+}
+
+type closeSignal struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func (c *closeSignal) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestHedgeDiscardsLosingResponses(t *testing.T) {
+	var calls int32
+	loser := &closeSignal{Reader: strings.NewReader("slow"), closed: make(chan struct{})}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+
+	resp, err := hedge(&model.Hedge{Delay: time.Millisecond}, systemClock{}, req, func(req *http.Request) (*response, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// the original copy answers only once it lost
+			<-req.Context().Done()
+			return &response{response: &http.Response{StatusCode: http.StatusOK, Body: loser}, streamed: true}, nil
+		}
+		return &response{response: &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, streamed: true}, nil
+	})
+
+	assert.Nil(t, err, "Should succeed")
+	assert.Equal(t, http.NoBody, resp.response.Body, "Should return the hedged response")
+
+	select {
+	case <-loser.closed:
+	case <-time.After(5 * time.Second):
+		assert.True(t, false, "Should close the body of the losing response")
+	}
+}
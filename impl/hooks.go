@@ -0,0 +1,65 @@
+package gorequest
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+/****************************************************
+ * model.Hooks dispatch
+ ****************************************************/
+
+func (r *request) beforeRequest(req *http.Request) error {
+	for _, hooks := range r.client.hooks {
+		if hooks.OnBeforeRequest == nil {
+			continue
+		}
+		if err := hooks.OnBeforeRequest(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *request) afterResponse(resp *response) (model.Response, error) {
+
+	var result model.Response = resp
+
+	for _, hooks := range r.client.hooks {
+		if hooks.OnAfterResponse == nil {
+			continue
+		}
+		replacement, err := hooks.OnAfterResponse(r.request, result)
+		if err != nil {
+			return nil, err
+		}
+		if replacement != nil {
+			result = replacement
+		}
+	}
+
+	return result, nil
+}
+
+func (r *request) onError(err error) {
+	for _, hooks := range r.client.hooks {
+		if hooks.OnError != nil {
+			hooks.OnError(r.request, err)
+		}
+	}
+}
+
+func (r *request) onRetry(attempt int, resp *response, err error) {
+
+	var httpResp *http.Response
+
+	if resp != nil {
+		httpResp = resp.response
+	}
+
+	for _, hooks := range r.client.hooks {
+		if hooks.OnRetry != nil {
+			hooks.OnRetry(r.request, attempt, httpResp, err)
+		}
+	}
+}
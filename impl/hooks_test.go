@@ -0,0 +1,114 @@
+package gorequest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"sync/atomic"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(resp, req.Header.Get("X-Request-Id"))
+	}))
+
+	defer ts.Close()
+
+	var events []string
+
+	c := NewClientBuilder().
+		WithRetry(model.Retry{MaxAttempts: 2, NoJitter: true}).
+		WithHooks(model.Hooks{
+			OnBeforeRequest: func(req *http.Request) error {
+				events = append(events, "before")
+				req.Header.Set("X-Request-Id", "42")
+				return nil
+			},
+			OnRetry: func(req *http.Request, attempt int, resp *http.Response, err error) {
+				events = append(events, fmt.Sprintf("retry %d %d", attempt, resp.StatusCode))
+			},
+		}).
+		WithHooks(model.Hooks{
+			OnAfterResponse: func(req *http.Request, resp model.Response) (model.Response, error) {
+				events = append(events, "after")
+				return NewStaticResponse(resp.Response().StatusCode, append([]byte("id="), resp.Body()...)), nil
+			},
+		}).
+		Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, "id=42", string(response.Body()), "Should return the replaced response")
+	assert.Equal(t, []string{"before", "retry 1 503", "before", "after"}, events, "Should call hooks in order")
+}
+
+func TestHooksOnError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		t.Error("Should not send the request")
+	}))
+
+	defer ts.Close()
+
+	rejected := errors.New("Rejected")
+	var reported error
+
+	c := NewClientBuilder().WithHooks(model.Hooks{
+		OnBeforeRequest: func(req *http.Request) error {
+			return rejected
+		},
+		OnError: func(req *http.Request, err error) {
+			reported = err
+		},
+	}).Build()
+
+	defer func() {
+		err := recover()
+
+		assert.Equal(t, rejected, err, "Should fail with the error of the hook")
+		assert.Equal(t, rejected, reported, "Should report the error")
+	}()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+}
+
+func TestProfilerLabels(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("GET", "/users/1").Reply(http.StatusOK, "")
+
+	labels := map[string]string{}
+
+	c := NewClientBuilder().
+		WithTransport(mock).
+		WithProfilerLabels().
+		WithHooks(model.Hooks{
+			OnBeforeRequest: func(req *http.Request) error {
+				pprof.ForLabels(req.Context(), func(key, value string) bool {
+					labels[key] = value
+					return true
+				})
+				return nil
+			},
+		}).
+		Build()
+
+	NewRequestBuilder().WithUrl("https://api.example.com/users/1").WithProfilerLabels("route", "/users/{id}").WithClient(c).Build().Do()
+
+	assert.Equal(t, map[string]string{
+		"gorequest.host":   "api.example.com",
+		"gorequest.method": "GET",
+		"route":            "/users/{id}",
+	}, labels, "Should label the request")
+}
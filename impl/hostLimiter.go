@@ -0,0 +1,101 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"strings"
+	"sync"
+)
+
+/**
+ * Caps the number of requests in flight per destination host, independently
+ * of the transport's connection limits. A request occupies a slot from the
+ * moment it is sent until its response is received: the body of buffered
+ * responses has been read by then, that of streamed responses has not.
+ */
+type hostLimiter struct {
+	limit  int
+	mutex  sync.Mutex
+	policy model.OverflowPolicy
+	slots  map[string]*hostSlots
+}
+
+/**
+ * The slots of a host, dropped once no request holds or waits for one so
+ * the map does not grow with every host ever contacted.
+ */
+type hostSlots struct {
+	slots chan struct{}
+	users int
+}
+
+func newHostLimiter(limit int, policy model.OverflowPolicy) *hostLimiter {
+	return &hostLimiter{
+		limit:  limit,
+		policy: policy,
+		slots:  make(map[string]*hostSlots),
+	}
+}
+
+/**
+ * Takes a slot for host and returns the function releasing it. Fails with a
+ * *model.HostLimitError when the limit is reached and the policy rejects,
+ * or with the error of ctx if it is done while waiting.
+ */
+func (l *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+
+	key := strings.ToLower(host)
+	entry := l.enter(key)
+
+	if l.policy == model.OverflowReject {
+		select {
+		case entry.slots <- struct{}{}:
+		default:
+			l.leave(key, entry)
+			return nil, &model.HostLimitError{Host: host, Limit: l.limit}
+		}
+	} else {
+		select {
+		case entry.slots <- struct{}{}:
+		case <-ctx.Done():
+			l.leave(key, entry)
+			return nil, ctx.Err()
+		}
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			<-entry.slots
+			l.leave(key, entry)
+		})
+	}, nil
+}
+
+func (l *hostLimiter) enter(host string) *hostSlots {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entry, ok := l.slots[host]
+
+	if !ok {
+		entry = &hostSlots{slots: make(chan struct{}, l.limit)}
+		l.slots[host] = entry
+	}
+
+	entry.users++
+
+	return entry
+}
+
+func (l *hostLimiter) leave(host string, entry *hostSlots) {
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if entry.users--; entry.users == 0 {
+		delete(l.slots, host)
+	}
+}
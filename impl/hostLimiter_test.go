@@ -0,0 +1,89 @@
+package gorequest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxRequestsPerHostReject(t *testing.T) {
+	limiter := newHostLimiter(1, model.OverflowReject)
+
+	release, _ := limiter.acquire(context.Background(), "api.example.com")
+	other, _ := limiter.acquire(context.Background(), "other.example.com")
+
+	_, err := limiter.acquire(context.Background(), "API.example.com")
+
+	_, ok := err.(*model.HostLimitError)
+
+	assert.True(t, ok, "Should fail with a host limit error")
+	assert.Equal(t, "Too many requests in flight to API.example.com (limit 1)", err.Error(), "Should equal error message")
+
+	release()
+	release()
+	other()
+
+	assert.Empty(t, limiter.slots, "Should drop the slots of idle hosts")
+
+	release, err = limiter.acquire(context.Background(), "api.example.com")
+
+	assert.Nil(t, err, "Should have a free slot again")
+
+	release()
+}
+
+func TestMaxRequestsPerHostBlockCanceled(t *testing.T) {
+	limiter := newHostLimiter(1, model.OverflowBlock)
+
+	release, _ := limiter.acquire(context.Background(), "api.example.com")
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := limiter.acquire(ctx, "api.example.com")
+
+	assert.Equal(t, context.DeadlineExceeded, err, "Should stop waiting when the context is done")
+	assert.Equal(t, 1, limiter.slots["api.example.com"].users, "Should stop counting requests that gave up")
+}
+
+func TestMaxRequestsPerHostBlock(t *testing.T) {
+	var inFlight, peak int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithMaxRequestsPerHost(2, model.OverflowBlock).Build()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak), "Should never exceed two requests in flight")
+}
@@ -0,0 +1,348 @@
+package gorequest
+
+import (
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/****************************************************
+ * Internationalized domain names
+ ****************************************************/
+
+/**
+ * Converts the Unicode labels of host, which may carry a port, to punycode
+ * (xn--...), as they must be sent on the wire. Labels are lowercased; no
+ * other UTS 46 mapping or normalization is applied.
+ */
+func HostToASCII(host string) (string, error) {
+
+	name, port := splitHostPort(host)
+
+	if isASCII(name) {
+		return host, nil
+	}
+
+	labels := strings.Split(strings.ToLower(name), ".")
+
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		encoded, err := punycodeEncode(label)
+		if err != nil {
+			return "", fmt.Errorf("Invalid internationalized host %s: %w", name, err)
+		}
+		labels[i] = "xn--" + encoded
+		if len(labels[i]) > 63 {
+			return "", fmt.Errorf("Invalid internationalized host %s: label %s is too long", name, label)
+		}
+	}
+
+	return joinHostPort(strings.Join(labels, "."), port), nil
+}
+
+/**
+ * Converts the punycode labels of host back to Unicode, for display. Labels
+ * that do not decode are kept as they are.
+ */
+func HostToUnicode(host string) string {
+
+	name, port := splitHostPort(host)
+
+	if !strings.Contains(strings.ToLower(name), "xn--") {
+		return host
+	}
+
+	labels := strings.Split(name, ".")
+
+	for i, label := range labels {
+		if len(label) > 4 && strings.EqualFold(label[:4], "xn--") {
+			// An ASCII label has no reason to be encoded
+			if decoded, err := punycodeDecode(label[4:]); err == nil && !isASCII(decoded) {
+				labels[i] = decoded
+			}
+		}
+	}
+
+	return joinHostPort(strings.Join(labels, "."), port)
+}
+
+/**
+ * Fails with a *model.MixedScriptError if a label of host mixes scripts,
+ * e.g. Latin and Cyrillic as in homograph attacks. Latin may only be mixed
+ * with the scripts of Chinese, Japanese or Korean, which are written along
+ * with it (UTS 39, highly restrictive).
+ */
+func checkHostScripts(host string) error {
+
+	for _, label := range strings.Split(HostToUnicode(host), ".") {
+
+		if isASCII(label) {
+			continue
+		}
+
+		scripts := labelScripts(label)
+
+		if len(scripts) > 1 && !allowedScripts(scripts) {
+			names := make([]string, 0, len(scripts))
+			for name := range scripts {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return &model.MixedScriptError{Host: HostToUnicode(host), Scripts: names}
+		}
+	}
+
+	return nil
+}
+
+func labelScripts(label string) map[string]bool {
+	scripts := map[string]bool{}
+	for _, r := range label {
+		for name, table := range unicode.Scripts {
+			if name != "Common" && name != "Inherited" && unicode.Is(table, r) {
+				scripts[name] = true
+				break
+			}
+		}
+	}
+	return scripts
+}
+
+var allowedScriptSets = []map[string]bool{
+	{"Han": true, "Hiragana": true, "Katakana": true, "Latin": true},
+	{"Bopomofo": true, "Han": true, "Latin": true},
+	{"Han": true, "Hangul": true, "Latin": true},
+}
+
+func allowedScripts(scripts map[string]bool) bool {
+	for _, allowed := range allowedScriptSets {
+		subset := true
+		for name := range scripts {
+			subset = subset && allowed[name]
+		}
+		if subset {
+			return true
+		}
+	}
+	return false
+}
+
+func splitHostPort(host string) (string, string) {
+	if name, port, err := net.SplitHostPort(host); err == nil {
+		return name, port
+	}
+	return host, ""
+}
+
+func joinHostPort(name string, port string) string {
+	if port == "" {
+		return name
+	}
+	return net.JoinHostPort(name, port)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+/****************************************************
+ * Punycode (RFC 3492)
+ ****************************************************/
+
+const (
+	punycodeBase        = 36
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+	punycodeSkew        = 38
+	punycodeTMax        = 26
+	punycodeTMin        = 1
+)
+
+var errPunycode = errors.New("invalid punycode")
+
+func punycodeEncode(label string) (string, error) {
+
+	runes := []rune(label)
+	var out strings.Builder
+
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out.WriteRune(r)
+		}
+	}
+
+	basic := out.Len()
+	handled := basic
+
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	n, delta, bias := rune(punycodeInitialN), 0, punycodeInitialBias
+
+	for handled < len(runes) {
+
+		next := rune(math.MaxInt32)
+		for _, r := range runes {
+			if r >= n && r < next {
+				next = r
+			}
+		}
+
+		if int(next-n) > (math.MaxInt32-delta)/(handled+1) {
+			return "", errPunycode
+		}
+
+		delta += int(next-n) * (handled + 1)
+		n = next
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+			q := delta
+			for k := punycodeBase; ; k += punycodeBase {
+				t := punycodeThreshold(k, bias)
+				if q < t {
+					break
+				}
+				out.WriteByte(punycodeDigit(t + (q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+			out.WriteByte(punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return out.String(), nil
+}
+
+func punycodeDecode(encoded string) (string, error) {
+
+	var output []rune
+	pos := 0
+
+	if i := strings.LastIndexByte(encoded, '-'); i >= 0 {
+		if !isASCII(encoded[:i]) {
+			return "", errPunycode
+		}
+		output = []rune(encoded[:i])
+		pos = i + 1
+	}
+
+	n, i, bias := punycodeInitialN, 0, punycodeInitialBias
+
+	for pos < len(encoded) {
+
+		previous, w := i, 1
+
+		for k := punycodeBase; ; k += punycodeBase {
+
+			if pos == len(encoded) {
+				return "", errPunycode
+			}
+
+			digit, ok := punycodeValue(encoded[pos])
+			pos++
+
+			if !ok || digit > (math.MaxInt32-i)/w {
+				return "", errPunycode
+			}
+
+			i += digit * w
+			t := punycodeThreshold(k, bias)
+
+			if digit < t {
+				break
+			}
+
+			w *= punycodeBase - t
+		}
+
+		length := len(output) + 1
+		bias = punycodeAdapt(i-previous, length, previous == 0)
+		n += i / length
+		i %= length
+
+		if n > unicode.MaxRune {
+			return "", errPunycode
+		}
+
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+
+	return string(output), nil
+}
+
+func punycodeThreshold(k int, bias int) int {
+	switch {
+	case k <= bias:
+		return punycodeTMin
+	case k >= bias+punycodeTMax:
+		return punycodeTMax
+	}
+	return k - bias
+}
+
+func punycodeAdapt(delta int, points int, first bool) int {
+
+	if first {
+		delta /= punycodeDamp
+	} else {
+		delta /= 2
+	}
+
+	delta += delta / points
+	k := 0
+
+	for delta > ((punycodeBase-punycodeTMin)*punycodeTMax)/2 {
+		delta /= punycodeBase - punycodeTMin
+		k += punycodeBase
+	}
+
+	return k + (punycodeBase-punycodeTMin+1)*delta/(delta+punycodeSkew)
+}
+
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func punycodeValue(c byte) (int, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c-'0') + 26, true
+	case 'a' <= c && c <= 'z':
+		return int(c - 'a'), true
+	case 'A' <= c && c <= 'Z':
+		return int(c - 'A'), true
+	}
+	return 0, false
+}
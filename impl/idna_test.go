@@ -0,0 +1,71 @@
+package gorequest
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDNA(t *testing.T) {
+	cases := map[string]string{
+		"bücher.example":      "xn--bcher-kva.example",
+		"BÜCHER.example:8443": "xn--bcher-kva.example:8443",
+		"münchen.de":          "xn--mnchen-3ya.de",
+		"例え.テスト":              "xn--r8jz45g.xn--zckzah",
+		"ελληνικά.gr":         "xn--hxargifdar.gr",
+		"api.example.com":     "api.example.com",
+		"[2001:db8::1]:443":   "[2001:db8::1]:443",
+		"правительство.рф":    "xn--80aealotwbjpid2k.xn--p1ai",
+		"점심.example":          "xn--cw4b17g.example",
+	}
+
+	for unicodeHost, asciiHost := range cases {
+		converted, err := HostToASCII(unicodeHost)
+		assert.Nil(t, err, "Should convert "+unicodeHost)
+		assert.Equal(t, asciiHost, converted, "Should convert "+unicodeHost)
+	}
+
+	assert.Equal(t, "bücher.example:8443", HostToUnicode("xn--bcher-kva.example:8443"), "Should convert back to Unicode")
+	assert.Equal(t, "правительство.рф", HostToUnicode("xn--80aealotwbjpid2k.xn--p1ai"), "Should convert back to Unicode")
+	assert.Equal(t, "xn--invalid-.example", HostToUnicode("xn--invalid-.example"), "Should keep labels that do not decode")
+
+	mock := requestmock.New()
+
+	var host string
+
+	mock.On("GET", "/books").Match(func(req *http.Request, body []byte) bool {
+		host = req.Host
+		return true
+	}).Reply(http.StatusOK, "")
+
+	var debug bytes.Buffer
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+	NewRequestBuilder().WithUrl("https://bücher.example/books").WithClient(c).WithDebug(&debug, false).Build().Do()
+
+	assert.Equal(t, "xn--bcher-kva.example", host, "Should send the host in punycode")
+	assert.Contains(t, debug.String(), "> Host: xn--bcher-kva.example (bücher.example)", "Should display the Unicode host")
+
+	strict := NewClientBuilder().WithTransport(mock).WithStrictIDNA().Build()
+
+	_, err := NewRequestBuilder().WithUrl("https://bücher.example/books").WithClient(strict).Build().Send()
+
+	assert.Nil(t, err, "Should accept single-script hosts")
+
+	// Cyrillic а (U+0430) among Latin letters
+	_, err = NewRequestBuilder().WithUrl("https://pаypal.example/books").WithClient(strict).Build().Send()
+
+	var mixed *model.MixedScriptError
+
+	assert.True(t, errors.As(err, &mixed), "Should reject mixed-script hosts")
+	assert.Equal(t, []string{"Cyrillic", "Latin"}, mixed.Scripts, "Should name the scripts")
+
+	_, err = NewRequestBuilder().WithUrl("https://xn--pypal-4ve.example/books").WithClient(strict).Build().Send()
+
+	assert.True(t, errors.As(err, &mixed), "Should check punycode hosts too")
+}
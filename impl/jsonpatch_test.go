@@ -0,0 +1,91 @@
+package gorequest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	jsonpatch "github.com/demianlessa/gorequest/jsonpatch"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONPatch(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("PATCH", "/users/1").Reply(http.StatusOK, "")
+
+	doer := NewDoer(NewClientBuilder().WithTransport(mock).Build())
+
+	body, err := jsonpatch.New().
+		Test(jsonpatch.Pointer("name"), "Ann").
+		Replace(jsonpatch.Pointer("name"), "Bo").
+		Add(jsonpatch.Pointer("tags", "-"), "admin").
+		Add(jsonpatch.Pointer("manager"), nil).
+		Move(jsonpatch.Pointer("a/b"), jsonpatch.Pointer("c~d")).
+		Remove(jsonpatch.Pointer("nickname")).
+		Body()
+
+	assert.Nil(t, err, "Should marshal the patch")
+
+	doer.Patch("https://api.example.com/users/1", body)
+
+	calls := mock.Calls()
+
+	assert.Equal(t, "application/json-patch+json", calls[0].Header.Get("Content-Type"), "Should send a JSON Patch")
+	assert.JSONEq(t, `[
+		{"op":"test","path":"/name","value":"Ann"},
+		{"op":"replace","path":"/name","value":"Bo"},
+		{"op":"add","path":"/tags/-","value":"admin"},
+		{"op":"add","path":"/manager","value":null},
+		{"op":"move","from":"/a~1b","path":"/c~0d"},
+		{"op":"remove","path":"/nickname"}
+	]`, string(calls[0].Body), "Should send the operations in order")
+}
+
+func TestJSONMergePatch(t *testing.T) {
+	type address struct {
+		City   string `json:"city"`
+		Street string `json:"street"`
+	}
+
+	type user struct {
+		Address  address  `json:"address"`
+		Name     string   `json:"name"`
+		Nickname string   `json:"nickname,omitempty"`
+		Tags     []string `json:"tags"`
+		Visits   int64    `json:"visits"`
+	}
+
+	before := user{Address: address{City: "Lisbon", Street: "Rua A"}, Name: "Ann", Nickname: "A", Tags: []string{"a"}, Visits: 1}
+	after := before
+	after.Address.Street = "Rua B"
+	after.Nickname = ""
+	after.Tags = []string{"a", "b"}
+	after.Visits = 9007199254740993
+
+	patch, err := jsonpatch.Diff(before, after)
+
+	assert.Nil(t, err, "Should compute the merge patch")
+	assert.JSONEq(t, `{"address":{"street":"Rua B"},"nickname":null,"tags":["a","b"],"visits":9007199254740993}`, string(patch), "Should keep only the changes")
+	assert.Contains(t, string(patch), `"visits":9007199254740993`, "Should keep numbers exact")
+
+	patch, err = jsonpatch.Diff(before, before)
+
+	assert.Nil(t, err, "Should compute empty patches")
+	assert.Equal(t, `{}`, string(patch), "Should patch nothing when nothing changed")
+
+	patch, err = jsonpatch.Diff([]int{1}, map[string]int{"a": 1})
+
+	assert.Nil(t, err, "Should diff values of different types")
+	assert.JSONEq(t, `{"a":1}`, string(patch), "Should replace values that are not both objects")
+
+	mock := requestmock.New()
+	mock.On("PATCH", "/users/1").Reply(http.StatusOK, "")
+
+	NewDoer(NewClientBuilder().WithTransport(mock).Build()).Patch("https://api.example.com/users/1", jsonpatch.MergePatch(json.RawMessage(`{"name":"Bo"}`)))
+
+	calls := mock.Calls()
+
+	assert.Equal(t, "application/merge-patch+json", calls[0].Header.Get("Content-Type"), "Should send a merge patch")
+	assert.Equal(t, `{"name":"Bo"}`, string(calls[0].Body), "Should send the document as is")
+}
@@ -0,0 +1,167 @@
+package gorequest
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+var defaultLatencyWindow time.Duration = time.Minute
+
+/**
+ * Samples kept per host; older samples are dropped even when they are still
+ * within the window.
+ */
+var latencySamples int = 1024
+
+type latencySample struct {
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+/**
+ * A ring of the most recent samples of a host.
+ */
+type latencyRing struct {
+	next    int
+	samples []latencySample
+}
+
+/**
+ * Per-host rolling latency statistics, fed as a model.Observer.
+ */
+type latencyTracker struct {
+	clock  model.Clock
+	hosts  map[string]*latencyRing
+	mutex  sync.Mutex
+	window time.Duration
+}
+
+func newLatencyTracker(clock model.Clock, window time.Duration) *latencyTracker {
+
+	if window <= 0 {
+		window = defaultLatencyWindow
+	}
+
+	return &latencyTracker{
+		clock:  clock,
+		hosts:  make(map[string]*latencyRing),
+		window: window,
+	}
+}
+
+/**
+ * model.Observer implementation.
+ */
+func (t *latencyTracker) ObserveExchange(exchange model.Exchange) {
+	failed := exchange.Err != nil || exchange.StatusCode >= http.StatusInternalServerError
+	t.record(exchange.Host, t.clock.Now(), exchange.Duration, failed)
+}
+
+func (t *latencyTracker) record(host string, at time.Time, duration time.Duration, failed bool) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	ring, ok := t.hosts[host]
+
+	if !ok {
+		ring = &latencyRing{}
+		t.hosts[host] = ring
+	}
+
+	sample := latencySample{at: at, duration: duration, failed: failed}
+
+	if len(ring.samples) < latencySamples {
+		ring.samples = append(ring.samples, sample)
+		return
+	}
+
+	ring.samples[ring.next] = sample
+	ring.next = (ring.next + 1) % len(ring.samples)
+}
+
+func (t *latencyTracker) snapshot() map[string]model.LatencyStats {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.clock.Now()
+	snapshot := make(map[string]model.LatencyStats, len(t.hosts))
+
+	for host, ring := range t.hosts {
+		if stats := ring.stats(now.Add(-t.window)); stats.Count > 0 {
+			snapshot[host] = stats
+		}
+	}
+
+	return snapshot
+}
+
+/**
+ * Returns the median latency of host and whether there are samples for it.
+ */
+func (t *latencyTracker) median(host string) (time.Duration, bool) {
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	ring, ok := t.hosts[host]
+
+	if !ok {
+		return 0, false
+	}
+
+	stats := ring.stats(t.clock.Now().Add(-t.window))
+
+	return stats.P50, stats.Count > 0
+}
+
+/**
+ * Computes the statistics of the samples taken after since.
+ */
+func (r *latencyRing) stats(since time.Time) model.LatencyStats {
+
+	durations := make([]time.Duration, 0, len(r.samples))
+	failures := 0
+
+	for _, sample := range r.samples {
+		if sample.at.Before(since) {
+			continue
+		}
+		durations = append(durations, sample.duration)
+		if sample.failed {
+			failures++
+		}
+	}
+
+	if len(durations) == 0 {
+		return model.LatencyStats{}
+	}
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	return model.LatencyStats{
+		Count:     len(durations),
+		ErrorRate: float64(failures) / float64(len(durations)),
+		P50:       percentile(durations, 50),
+		P90:       percentile(durations, 90),
+		P99:       percentile(durations, 99),
+	}
+}
+
+/**
+ * Nearest-rank percentile of sorted durations.
+ */
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
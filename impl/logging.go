@@ -0,0 +1,214 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var defaultMaxLoggedBody int = 4096
+
+/**
+ * Headers that are always redacted.
+ */
+var redactedHeaders []string = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+const redacted = "[REDACTED]"
+
+type attemptKey struct{}
+
+/**
+ * Records the attempt number of the request in its context.
+ */
+func withAttempt(req *http.Request, attempt int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), attemptKey{}, attempt))
+}
+
+func attemptOf(req *http.Request) int {
+	if attempt, ok := req.Context().Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
+}
+
+/**
+ * Turns exchanges into redacted log entries for a model.Logger.
+ */
+type requestLogger struct {
+	redact   map[string]bool
+	settings model.Logging
+}
+
+func newRequestLogger(settings model.Logging) *requestLogger {
+
+	if settings.MaxBodyBytes <= 0 {
+		settings.MaxBodyBytes = defaultMaxLoggedBody
+	}
+
+	redact := make(map[string]bool)
+	for _, name := range append(redactedHeaders, settings.RedactHeaders...) {
+		redact[http.CanonicalHeaderKey(name)] = true
+	}
+
+	return &requestLogger{
+		redact:   redact,
+		settings: settings,
+	}
+}
+
+func (l *requestLogger) log(req *http.Request, resp *response, err error, start time.Time, duration time.Duration) {
+
+	failed := failedExchange(resp, err)
+
+	if !failed && l.settings.SampleRate > 0 && rand.Float64() >= l.settings.SampleRate {
+		return
+	}
+
+	exchange := newExchange(req, resp, err, duration)
+
+	// transport errors quote the full URL, query string included
+	if urlErr, ok := err.(*url.Error); ok {
+		err = &url.Error{Op: urlErr.Op, URL: l.redactURL(req.URL), Err: urlErr.Err}
+	}
+
+	entry := model.LogEntry{
+		Attempt:       exchange.Attempt,
+		BytesReceived: exchange.BytesReceived,
+		BytesSent:     exchange.BytesSent,
+		Duration:      duration,
+		Err:           err,
+		Method:        req.Method,
+		RequestHeader: l.redactHeader(req.Header),
+		StartedAt:     start,
+		StatusCode:    exchange.StatusCode,
+		URL:           l.redactURL(req.URL),
+	}
+
+	if resp != nil {
+		entry.Proto = resp.response.Proto
+		entry.ResponseHeader = l.redactHeader(resp.response.Header)
+	}
+
+	if l.settings.LogBodies {
+		entry.RequestBody = l.redactBody(replayBody(req, int64(l.settings.MaxBodyBytes)+1))
+		if resp != nil {
+			entry.ResponseBody = l.redactBody(resp.body)
+		}
+	}
+
+	l.settings.Logger.Log(entry)
+}
+
+/**
+ * Returns u with its password and, unless LogQuery is set, the values of its
+ * query redacted; the names of the query parameters are kept.
+ */
+func (l *requestLogger) redactURL(u *url.URL) string {
+
+	clone := *u
+
+	if clone.RawQuery != "" && !l.settings.LogQuery {
+		pairs := strings.Split(clone.RawQuery, "&")
+		for i, pair := range pairs {
+			if parts := strings.SplitN(pair, "=", 2); len(parts) == 2 {
+				pairs[i] = parts[0] + "=" + redacted
+			}
+		}
+		clone.RawQuery = strings.Join(pairs, "&")
+	}
+
+	return clone.Redacted()
+}
+
+func (l *requestLogger) redactHeader(header http.Header) http.Header {
+
+	clone := header.Clone()
+
+	for name := range clone {
+		if l.redact[name] {
+			clone[name] = []string{redacted}
+		}
+	}
+
+	return clone
+}
+
+func (l *requestLogger) redactBody(body []byte) []byte {
+
+	if body == nil {
+		return nil
+	}
+
+	if len(body) > l.settings.MaxBodyBytes {
+		body = body[:l.settings.MaxBodyBytes]
+	}
+
+	body = append([]byte(nil), body...)
+
+	if l.settings.RedactBody != nil {
+		body = l.settings.RedactBody(body)
+	}
+
+	return body
+}
+
+/**
+ * Reads a fresh copy of the request body, if it can be replayed: its first
+ * limit bytes, or all of it if limit is negative.
+ */
+func replayBody(req *http.Request, limit int64) []byte {
+
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return nil
+	}
+
+	defer body.Close()
+
+	var reader io.Reader = body
+
+	if limit >= 0 {
+		reader = io.LimitReader(body, limit)
+	}
+
+	data, _ := readAll(reader, 0)
+
+	return data
+}
+
+func newExchange(req *http.Request, resp *response, err error, duration time.Duration) model.Exchange {
+
+	exchange := model.Exchange{
+		Attempt:       attemptOf(req),
+		BytesReceived: -1,
+		BytesSent:     req.ContentLength,
+		Duration:      duration,
+		Err:           err,
+		Host:          req.URL.Host,
+		Method:        req.Method,
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		exchange.BytesSent = 0
+	}
+
+	if resp != nil && resp.streamed {
+		exchange.BytesReceived = resp.response.ContentLength
+		exchange.StatusCode = resp.response.StatusCode
+	} else if resp != nil {
+		exchange.BytesReceived = int64(len(resp.body))
+		exchange.StatusCode = resp.response.StatusCode
+	}
+
+	return exchange
+}
@@ -0,0 +1,406 @@
+package gorequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	entries []model.LogEntry
+	mutex   sync.Mutex
+}
+
+func (l *testLogger) Log(entry model.LogEntry) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = append(l.entries, entry)
+}
+
+func TestLoggingWithRedaction(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.Header().Set("Set-Cookie", "session=secret")
+		if calls == 1 {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(resp, `{"token":"abc"}`)
+	}))
+
+	defer ts.Close()
+
+	logger := &testLogger{}
+
+	c := NewClientBuilder().
+		WithRetry(model.Retry{MaxAttempts: 2, BaseDelay: time.Millisecond}).
+		WithLogging(model.Logging{
+			Logger:        logger,
+			RedactHeaders: []string{"x-api-key"},
+			LogBodies:     true,
+			RedactBody: func(body []byte) []byte {
+				return bytes.Replace(body, []byte("abc"), []byte("***"), -1)
+			},
+		}).
+		Build()
+
+	NewRequestBuilder().WithUrl(ts.URL+"/items?access_token=secret&page=2").WithClient(c).WithMethod("PUT").WithBody(newJsonBody(`{"id":1}`)).
+		WithBearerAuth("token").WithHeader("X-Api-Key", "key").Build().Do()
+
+	assert.Equal(t, 2, len(logger.entries), "Should log every attempt")
+
+	first, second := logger.entries[0], logger.entries[1]
+
+	assert.Equal(t, 1, first.Attempt, "Should equal the attempt number")
+	assert.Equal(t, 503, first.StatusCode, "Should equal the status of the attempt")
+	assert.Equal(t, 2, second.Attempt, "Should equal the attempt number")
+	assert.Equal(t, 200, second.StatusCode, "Should equal the status of the attempt")
+	assert.Equal(t, "PUT", second.Method, "Should equal the method")
+	assert.Equal(t, ts.URL+"/items?access_token=[REDACTED]&page=[REDACTED]", second.URL, "Should redact the values of the query")
+	assert.Equal(t, int64(8), second.BytesSent, "Should count the bytes sent")
+	assert.Equal(t, int64(15), second.BytesReceived, "Should count the bytes received")
+	assert.Equal(t, "[REDACTED]", second.RequestHeader.Get("Authorization"), "Should redact credentials")
+	assert.Equal(t, "[REDACTED]", second.RequestHeader.Get("X-Api-Key"), "Should redact configured headers")
+	assert.Equal(t, "[REDACTED]", second.ResponseHeader.Get("Set-Cookie"), "Should redact cookies")
+	assert.Equal(t, `{"id":1}`, string(second.RequestBody), "Should log the request body")
+	assert.Equal(t, `{"token":"***"}`, string(second.ResponseBody), "Should redact the response body")
+
+	NewRequestBuilder().WithUrl("http://gorequest.invalid/?access_token=secret").WithClient(c).WithRetry(model.Retry{MaxAttempts: 1}).Build().Send()
+
+	failed := logger.entries[len(logger.entries)-1]
+
+	assert.NotNil(t, failed.Err, "Should log transport errors")
+	assert.NotContains(t, failed.Err.Error(), "secret", "Should redact the URL quoted by transport errors")
+}
+
+func TestLoggingSampling(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/fail" {
+			resp.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	defer ts.Close()
+
+	logger := &testLogger{}
+
+	c := NewClientBuilder().WithLogging(model.Logging{Logger: logger, SampleRate: 0.000001}).Build()
+
+	for i := 0; i < 10; i++ {
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+	}
+	NewRequestBuilder().WithUrl(ts.URL + "/fail").WithClient(c).Build().Do()
+
+	assert.Equal(t, 1, len(logger.entries), "Should sample successes but always log failures")
+	assert.Equal(t, 500, logger.entries[0].StatusCode, "Should log the failure")
+}
+
+type testObserver struct {
+	exchanges []model.Exchange
+	mutex     sync.Mutex
+}
+
+func (o *testObserver) ObserveExchange(exchange model.Exchange) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.exchanges = append(o.exchanges, exchange)
+}
+
+func TestObserver(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	observer := &testObserver{}
+	c := NewClientBuilder().WithObserver(observer).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, 1, len(observer.exchanges), "Should observe the exchange")
+
+	exchange := observer.exchanges[0]
+
+	assert.Equal(t, "GET", exchange.Method, "Should equal the method")
+	assert.Equal(t, strings.TrimPrefix(ts.URL, "http://"), exchange.Host, "Should equal the host")
+	assert.Equal(t, 200, exchange.StatusCode, "Should equal the status")
+	assert.Equal(t, int64(2), exchange.BytesReceived, "Should count the bytes received")
+	assert.True(t, exchange.Duration > 0, "Should measure the duration")
+}
+
+var expvarRuns int32
+
+func TestExpvar(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	// expvar names are global, and published once per process
+	name := fmt.Sprintf("gorequest_%s_%d", t.Name(), atomic.AddInt32(&expvarRuns, 1))
+
+	c := NewClientBuilder().WithExpvar(name).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithMethod("POST").WithBody(newJsonBody(`{"id":1}`)).Build().Do()
+
+	vars := expvar.Get(name).(*expvar.Map)
+
+	assert.Equal(t, "1", vars.Get("requests").String(), "Should count requests")
+	assert.Equal(t, "0", vars.Get("errors").String(), "Should count errors")
+	assert.Equal(t, "8", vars.Get("bytes_sent").String(), "Should count bytes sent")
+	assert.Equal(t, "2", vars.Get("bytes_received").String(), "Should count bytes received")
+	assert.Equal(t, "0", vars.Get("in_flight").String(), "Should report requests in flight")
+
+	other := NewClientBuilder().WithExpvar(name).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(other).Build().Do()
+
+	assert.Equal(t, "2", vars.Get("requests").String(), "Should share the map of a name between clients")
+
+	stats := c.(*client).expvar
+
+	c.Close(context.Background())
+
+	_, held := stats.clients[c.(*client)]
+
+	assert.False(t, held, "Should release closed clients")
+
+	expvar.NewInt(name + "_taken")
+
+	assert.Panics(t, func() { NewClientBuilder().WithExpvar(name + "_taken").Build() }, "Should not take over names published by other means")
+}
+
+func TestDebug(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	var out bytes.Buffer
+
+	NewRequestBuilder().
+		WithUrl(ts.URL + "/items?page=2").
+		WithMethod("POST").
+		WithBearerAuth("secret").
+		WithBody(newJsonBody(`{"id":1}`)).
+		WithDebug(&out, true).
+		Build().
+		Do()
+
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	assert.Contains(t, out.String(), "> POST /items?page=2 HTTP/1.1\n> Host: "+host+"\n", "Should write the request line")
+	assert.Contains(t, out.String(), "> Authorization: [REDACTED]\n", "Should redact credentials")
+	assert.Contains(t, out.String(), ">\n{\"id\":1}\n", "Should write the request body")
+	assert.Contains(t, out.String(), "< HTTP/1.1 200 OK\n", "Should write the status line")
+	assert.Contains(t, out.String(), "< Content-Type: text/plain\n", "Should write the response headers")
+	assert.Contains(t, out.String(), "<\nOK\n", "Should write the response body")
+	assert.NotContains(t, out.String(), "secret", "Should not leak credentials")
+}
+
+type testMetrics struct {
+	counters map[string]float64
+	gauges   []float64
+	mutex    sync.Mutex
+	timers   int
+}
+
+func (m *testMetrics) AddCounter(name string, delta float64, tags map[string]string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.counters[name+" "+tags["status"]] += delta
+}
+
+func (m *testMetrics) ObserveTimer(name string, duration time.Duration, tags map[string]string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.timers++
+}
+
+func (m *testMetrics) SetGauge(name string, value float64, tags map[string]string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.gauges = append(m.gauges, value)
+}
+
+func TestMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	metrics := &testMetrics{counters: make(map[string]float64)}
+
+	c := NewClientBuilder().WithMetrics(metrics).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithMethod("POST").WithBody(newJsonBody(`{"id":1}`)).Build().Do()
+
+	assert.Equal(t, map[string]float64{"requests 200": 1, "bytes_sent ": 8, "bytes_received ": 2}, metrics.counters, "Should report counters")
+	assert.Equal(t, 1, metrics.timers, "Should report the duration")
+	assert.Equal(t, []float64{1, 0}, metrics.gauges, "Should report requests in flight")
+}
+
+func TestLatencyStats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/fail" {
+			resp.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithLatencyStats(0).Build()
+
+	for _, path := range []string{"/", "/", "/", "/fail"} {
+		NewRequestBuilder().WithUrl(ts.URL + path).WithClient(c).Build().Do()
+	}
+
+	stats := c.LatencyStats()[strings.TrimPrefix(ts.URL, "http://")]
+
+	assert.Equal(t, 4, stats.Count, "Should count exchanges")
+	assert.Equal(t, 0.25, stats.ErrorRate, "Should equal error rate")
+	assert.True(t, stats.P50 > 0 && stats.P50 <= stats.P90 && stats.P90 <= stats.P99, "Should order percentiles")
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	tracker := newLatencyTracker(systemClock{}, time.Minute)
+	now := time.Now()
+
+	for i := 1; i <= 100; i++ {
+		tracker.record("api.internal", now, time.Duration(i)*time.Millisecond, false)
+	}
+
+	tracker.record("api.internal", now.Add(-2*time.Minute), time.Second, true)
+
+	stats := tracker.snapshot()["api.internal"]
+
+	assert.Equal(t, 100, stats.Count, "Should ignore samples outside the window")
+	assert.Equal(t, 50*time.Millisecond, stats.P50, "Should equal p50")
+	assert.Equal(t, 90*time.Millisecond, stats.P90, "Should equal p90")
+	assert.Equal(t, 99*time.Millisecond, stats.P99, "Should equal p99")
+	assert.Equal(t, 0.0, stats.ErrorRate, "Should equal error rate")
+}
+
+func TestHARRecorder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(resp, `{"id":1}`)
+	}))
+
+	defer ts.Close()
+
+	recorder := NewHARRecorder(0)
+	c := NewClientBuilder().WithLogging(model.Logging{Logger: recorder, LogBodies: true, LogQuery: true}).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL + "/customers?page=2").WithClient(c).WithBearerAuth("secret").Build().Do()
+
+	recorder.Stop()
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, 1, recorder.Len(), "Should not record while stopped")
+
+	var buffer bytes.Buffer
+	recorder.WriteTo(&buffer)
+
+	var har struct {
+		Log struct {
+			Version string
+			Entries []struct {
+				Request struct {
+					Method      string
+					URL         string
+					Headers     []struct{ Name, Value string }
+					QueryString []struct{ Name, Value string }
+				}
+				Response struct {
+					Status  int
+					Content struct {
+						MimeType string
+						Text     string
+					}
+				}
+			}
+		}
+	}
+
+	assert.Nil(t, json.Unmarshal(buffer.Bytes(), &har), "Should write valid JSON")
+	assert.Equal(t, "1.2", har.Log.Version, "Should write HAR 1.2")
+
+	entry := har.Log.Entries[0]
+
+	assert.Equal(t, "GET", entry.Request.Method, "Should record the method")
+	assert.Equal(t, ts.URL+"/customers?page=2", entry.Request.URL, "Should record the URL")
+	assert.Contains(t, entry.Request.Headers, struct{ Name, Value string }{"Authorization", "[REDACTED]"}, "Should redact credentials")
+	assert.Equal(t, []struct{ Name, Value string }{{"page", "2"}}, entry.Request.QueryString, "Should record the query string")
+	assert.Equal(t, 200, entry.Response.Status, "Should record the status")
+	assert.Equal(t, "application/json", entry.Response.Content.MimeType, "Should record the content type")
+	assert.Equal(t, `{"id":1}`, entry.Response.Content.Text, "Should record the body")
+}
+
+func TestHARRecorderMaxEntries(t *testing.T) {
+	recorder := NewHARRecorder(2)
+
+	for i := 0; i < 3; i++ {
+		recorder.Log(model.LogEntry{Method: "GET", URL: fmt.Sprintf("http://example.com/%d", i)})
+	}
+
+	assert.Equal(t, 2, recorder.Len(), "Should keep the newest entries")
+
+	recorder.Reset()
+
+	assert.Equal(t, 0, recorder.Len(), "Should discard the entries")
+}
+
+type countedReader struct {
+	read int
+	size int
+}
+
+func (c *countedReader) Read(p []byte) (int, error) {
+	if c.read >= c.size {
+		return 0, io.EOF
+	}
+	n := len(p)
+	if n > c.size-c.read {
+		n = c.size - c.read
+	}
+	c.read += n
+	return n, nil
+}
+
+func TestLoggedBodyLimit(t *testing.T) {
+	upload := &countedReader{size: 10 << 20}
+
+	req, _ := http.NewRequest("PUT", testUrl, nil)
+	req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(upload), nil }
+
+	logger := &testLogger{}
+	l := newRequestLogger(model.Logging{Logger: logger, LogBodies: true, MaxBodyBytes: 100})
+
+	l.log(req, nil, errors.New("Connection refused"), time.Now(), time.Millisecond)
+
+	assert.Len(t, logger.entries[0].RequestBody, 100, "Should truncate logged bodies")
+	assert.True(t, upload.read <= 4096, "Should not read the whole body to log its start, read %d bytes", upload.read)
+}
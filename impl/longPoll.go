@@ -0,0 +1,117 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"time"
+)
+
+var defaultLongPollBackoff = model.Retry{
+	BaseDelay: time.Second,
+	MaxDelay:  30 * time.Second,
+}
+
+var defaultLongPollInterval = time.Second
+
+/**
+ * Polls until ctx is done, then closes the returned channel. Responses with
+ * data are delivered as they come; empty ones are followed by the next poll
+ * once MinInterval has passed since they were sent. Failures, whether errors or responses with a status of 400 or
+ * above (delivered along with their error), are followed by a growing
+ * delay, or by the delay a 429 or 503 response asks for.
+ */
+func LongPoll(ctx context.Context, poll model.LongPoll) <-chan model.Result {
+
+	if poll.Next == nil {
+		panic(errors.New("Long poll requires a Next function"))
+	}
+
+	if poll.Backoff.BaseDelay <= 0 {
+		poll.Backoff = defaultLongPollBackoff
+	}
+
+	if poll.Clock == nil {
+		poll.Clock = systemClock{}
+	}
+
+	if poll.Empty == nil {
+		poll.Empty = emptyPoll
+	}
+
+	if poll.MinInterval <= 0 {
+		poll.MinInterval = defaultLongPollInterval
+	}
+
+	results := make(chan model.Result)
+
+	go func() {
+
+		defer close(results)
+
+		var last model.Response
+		failures := 0
+
+		for ctx.Err() == nil {
+
+			sent := poll.Clock.Now()
+			result := run(poll.Next(last).WithContext(ctx).Build())
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			var rejected *http.Response
+
+			if result.Err == nil && result.Response.Response().StatusCode >= http.StatusBadRequest {
+				rejected = result.Response.Response()
+				result.Err = fmt.Errorf("Long poll failed with status %d", rejected.StatusCode)
+			}
+
+			if result.Err == nil {
+				failures = 0
+				if poll.Empty(result.Response) {
+					select {
+					case <-poll.Clock.After(poll.MinInterval - poll.Clock.Now().Sub(sent)):
+					case <-ctx.Done():
+						return
+					}
+					continue
+				}
+				last = result.Response
+			}
+
+			select {
+			case results <- result:
+			case <-ctx.Done():
+				return
+			}
+
+			if result.Err == nil {
+				continue
+			}
+
+			failures++
+			delay := backoff(&poll.Backoff, failures)
+
+			if requested, ok := retryAfter(rejected, poll.Clock.Now()); ok && requested > delay {
+				delay = requested
+			}
+
+			select {
+			case <-poll.Clock.After(delay):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+func emptyPoll(resp model.Response) bool {
+	status := resp.Response().StatusCode
+	return status == http.StatusNoContent || status == http.StatusNotModified || len(resp.Body()) == 0
+}
@@ -0,0 +1,67 @@
+package gorequest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongPoll(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/events").Times(1).Reply(http.StatusNoContent, "")
+	mock.On("GET", "/events").Times(1).ReplyJSON(http.StatusOK, map[string]int{"id": 1})
+	mock.On("GET", "/events").MatchQuery("cursor", "1").Times(1).ReplyHeader("Retry-After", "5").Reply(http.StatusServiceUnavailable, "")
+	mock.On("GET", "/events").MatchQuery("cursor", "1").Times(1).ReplyJSON(http.StatusOK, map[string]int{"id": 2})
+	mock.On("GET", "/events").Delay(time.Millisecond).Reply(http.StatusNoContent, "")
+
+	start := time.Now()
+	clock := requestmock.NewClock(start).AutoAdvance()
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := LongPoll(ctx, model.LongPoll{
+		Clock:       clock,
+		MinInterval: 2 * time.Second,
+		Next: func(last model.Response) model.RequestBuilder {
+			url := "https://api.example.com/events?wait=30"
+			if last != nil {
+				var event struct{ ID int }
+				json.Unmarshal(last.Body(), &event)
+				url += "&cursor=" + strconv.Itoa(event.ID)
+			}
+			return NewRequestBuilder().WithUrl(url).WithClient(c)
+		},
+	})
+
+	result := <-results
+
+	assert.Nil(t, result.Err, "Should skip empty polls")
+	assert.JSONEq(t, `{"id":1}`, string(result.Response.Body()), "Should deliver data")
+	assert.True(t, clock.Now().Sub(start) >= 2*time.Second, "Should space empty polls")
+
+	result = <-results
+
+	assert.EqualError(t, result.Err, "Long poll failed with status 503", "Should deliver failures")
+
+	result = <-results
+
+	assert.JSONEq(t, `{"id":2}`, string(result.Response.Body()), "Should reconnect after failures")
+	assert.True(t, clock.Now().Sub(start) >= 5*time.Second, "Should wait as long as the server asks")
+
+	cancel()
+
+	for range results {
+	}
+
+	assert.Panics(t, func() { LongPoll(ctx, model.LongPoll{}) }, "Should require a Next function")
+}
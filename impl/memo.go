@@ -0,0 +1,226 @@
+package gorequest
+
+import (
+	"container/list"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**
+ * Entries kept by the memoizer of a client unless configured otherwise
+ * with ClientBuilder.WithMemoizationLimit.
+ */
+var defaultMemoEntries int = 1024
+
+/**
+ * Request headers carrying credentials, always part of the memoization key.
+ */
+var memoCredentialHeaders []string = []string{"Authorization", "Cookie", "Proxy-Authorization", "X-Api-Key", "X-Auth-Token"}
+
+/**
+ * Remembers successful GET responses for the TTL chosen by each request
+ * (RequestBuilder.WithCacheTTL), keyed by URL, credentials (credential
+ * headers and TLS settings) and by the values of the request headers listed in the
+ * Vary header of the response. The least
+ * recently used entries are evicted beyond maxEntries. Unlike an HTTP
+ * cache, the caching headers of responses are ignored.
+ */
+type memoizer struct {
+	entries    map[string]*list.Element
+	lru        *list.List
+	maxEntries int
+	mutex      sync.Mutex
+	vary       map[string]*memoVary
+}
+
+type memoEntry struct {
+	expires time.Time
+	key     string
+	resp    *response
+	url     string
+}
+
+/**
+ * The Vary header names of a URL, shared by its entries.
+ */
+type memoVary struct {
+	entries int
+	names   []string
+}
+
+func newMemoizer(maxEntries int) *memoizer {
+	return &memoizer{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		vary:       make(map[string]*memoVary),
+	}
+}
+
+/**
+ * Returns a copy of the response remembered for req, if any and not expired.
+ * URLs are normalized, and their query sorted if sortQuery is set.
+ */
+func (m *memoizer) get(req *http.Request, variant tlsVariant, sortQuery bool, now time.Time) *response {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	url := normalizeURL(req.URL, sortQuery)
+
+	vary, ok := m.vary[url]
+
+	if !ok {
+		return nil
+	}
+
+	element, ok := m.entries[memoKey(url, vary.names, req.Header, variant)]
+
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*memoEntry)
+
+	if !now.Before(entry.expires) {
+		m.remove(element)
+		return nil
+	}
+
+	m.lru.MoveToFront(element)
+
+	return entry.resp.copy()
+}
+
+/**
+ * Remembers resp, the response to req, until now+ttl. Only 200 responses
+ * that do not vary on every header are kept.
+ */
+func (m *memoizer) put(req *http.Request, variant tlsVariant, sortQuery bool, resp *response, ttl time.Duration, now time.Time) {
+
+	if resp.streamed || resp.response.StatusCode != http.StatusOK {
+		return
+	}
+
+	names := varyNames(resp.response.Header)
+
+	for _, name := range names {
+		if name == "*" {
+			return
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	url := normalizeURL(req.URL, sortQuery)
+
+	vary, ok := m.vary[url]
+
+	if !ok || !equalNames(vary.names, names) {
+		// the resource now varies differently, its entries are stale
+		m.removeURL(url)
+		vary = &memoVary{names: names}
+		m.vary[url] = vary
+	}
+
+	key := memoKey(url, names, req.Header, variant)
+
+	if element, ok := m.entries[key]; ok {
+		entry := element.Value.(*memoEntry)
+		entry.expires = now.Add(ttl)
+		entry.resp = resp.copy()
+		m.lru.MoveToFront(element)
+		return
+	}
+
+	m.entries[key] = m.lru.PushFront(&memoEntry{
+		expires: now.Add(ttl),
+		key:     key,
+		resp:    resp.copy(),
+		url:     url,
+	})
+	vary.entries++
+
+	for m.maxEntries > 0 && m.lru.Len() > m.maxEntries {
+		m.remove(m.lru.Back())
+	}
+}
+
+func (m *memoizer) remove(element *list.Element) {
+
+	entry := element.Value.(*memoEntry)
+
+	m.lru.Remove(element)
+	delete(m.entries, entry.key)
+
+	if vary := m.vary[entry.url]; vary != nil {
+		if vary.entries--; vary.entries <= 0 {
+			delete(m.vary, entry.url)
+		}
+	}
+}
+
+func (m *memoizer) removeURL(url string) {
+	for element := m.lru.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*memoEntry).url == url {
+			m.remove(element)
+		}
+		element = next
+	}
+	delete(m.vary, url)
+}
+
+/**
+ * Identifies the entry of a URL for the values of the headers it varies on.
+ * Responses to one set of credentials are never served to another, whether
+ * or not the server lists the credential headers in Vary.
+ */
+func memoKey(url string, names []string, header http.Header, variant tlsVariant) string {
+
+	var key strings.Builder
+
+	fmt.Fprintf(&key, "%s %t %s", url, variant.insecureSkipVerify, variant.serverName)
+
+	for _, name := range memoCredentialHeaders {
+		fmt.Fprintf(&key, "\n%q", header.Values(name))
+	}
+
+	for _, name := range names {
+		key.WriteString("\n")
+		key.WriteString(strings.Join(header.Values(name), ","))
+	}
+
+	return key.String()
+}
+
+func varyNames(header http.Header) []string {
+
+	var names []string
+
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return names
+}
+
+func equalNames(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
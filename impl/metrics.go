@@ -0,0 +1,55 @@
+package gorequest
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	"strconv"
+)
+
+/**
+ * Reports exchanges to a model.MetricsCollector.
+ */
+type metricsReporter struct {
+	collector model.MetricsCollector
+}
+
+func newMetricsReporter(collector model.MetricsCollector) *metricsReporter {
+	return &metricsReporter{
+		collector: collector,
+	}
+}
+
+/**
+ * model.Observer implementation.
+ */
+func (m *metricsReporter) ObserveExchange(exchange model.Exchange) {
+
+	status := "error"
+
+	if exchange.Err != nil {
+		m.collector.AddCounter("errors", 1, map[string]string{"method": exchange.Method, "host": exchange.Host})
+	} else {
+		status = strconv.Itoa(exchange.StatusCode)
+	}
+
+	tags := map[string]string{"method": exchange.Method, "host": exchange.Host, "status": status}
+
+	m.collector.AddCounter("requests", 1, tags)
+	m.collector.ObserveTimer("request_duration", exchange.Duration, tags)
+
+	if exchange.Attempt > 1 {
+		m.collector.AddCounter("retries", 1, map[string]string{"method": exchange.Method, "host": exchange.Host})
+	}
+
+	host := map[string]string{"host": exchange.Host}
+
+	if exchange.BytesSent > 0 {
+		m.collector.AddCounter("bytes_sent", float64(exchange.BytesSent), host)
+	}
+	if exchange.BytesReceived > 0 {
+		m.collector.AddCounter("bytes_received", float64(exchange.BytesReceived), host)
+	}
+}
+
+func (m *metricsReporter) inFlight(count int32) {
+	m.collector.SetGauge("in_flight", float64(count), nil)
+}
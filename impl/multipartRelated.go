@@ -0,0 +1,74 @@
+package gorequest
+
+import (
+	"bytes"
+	"encoding/json"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+)
+
+/**
+ * Builds a multipart/related body (RFC 2387) made of a JSON metadata part
+ * followed by a media part of type mediaType, as taken by Google-style
+ * upload endpoints (uploadType=multipart). Metadata is sent as is when it
+ * is a string or bytes, marshalled otherwise. Requests built with a body
+ * that cannot be serialized, or whose media cannot be read, fail.
+ */
+func NewMultipartRelated(metadata interface{}, mediaType string, media io.Reader) model.RequestBody {
+
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	body := &requestBody{
+		contentType: mime.FormatMediaType("multipart/related", map[string]string{
+			"boundary": writer.Boundary(),
+			"type":     "application/json",
+		}),
+		data: &buffer,
+	}
+
+	var meta []byte
+
+	switch metadata := metadata.(type) {
+	case string:
+		meta = []byte(metadata)
+	case []byte:
+		meta = metadata
+	case json.RawMessage:
+		meta = metadata
+	default:
+		meta, body.err = json.Marshal(metadata)
+	}
+
+	if body.err != nil {
+		return body
+	}
+
+	body.err = writePart(writer, "application/json; charset=UTF-8", bytes.NewReader(meta))
+
+	if body.err == nil {
+		body.err = writePart(writer, mediaType, media)
+	}
+
+	if body.err == nil {
+		body.err = writer.Close()
+	}
+
+	return body
+}
+
+func writePart(writer *multipart.Writer, contentType string, content io.Reader) error {
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, content)
+
+	return err
+}
@@ -0,0 +1,67 @@
+package gorequest
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("Disk failure")
+}
+
+func TestMultipartRelated(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("POST", "/upload/drive/v3/files").MatchQuery("uploadType", "multipart").Reply(http.StatusOK, "")
+
+	media := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	metadata := struct {
+		Name string `json:"name"`
+	}{"logo.png"}
+
+	NewRequest(
+		WithClient(NewClient(WithTransport(mock))),
+		WithMethod("POST"),
+		WithUrl("https://www.googleapis.com/upload/drive/v3/files?uploadType=multipart"),
+		WithBody(NewMultipartRelated(metadata, "image/png", bytes.NewReader(media))),
+	).Do()
+
+	call := mock.Calls()[0]
+	mediaType, params, err := mime.ParseMediaType(call.Header.Get("Content-Type"))
+
+	assert.Nil(t, err, "Should send a valid content type")
+	assert.Equal(t, "multipart/related", mediaType, "Should send a multipart/related body")
+	assert.Equal(t, "application/json", params["type"], "Should declare the type of the root part")
+
+	reader := multipart.NewReader(bytes.NewReader(call.Body), params["boundary"])
+
+	part, err := reader.NextPart()
+	assert.Nil(t, err, "Should send the metadata part")
+	assert.Equal(t, "application/json; charset=UTF-8", part.Header.Get("Content-Type"), "Should type the metadata part")
+	content, _ := ioutil.ReadAll(part)
+	assert.Equal(t, `{"name":"logo.png"}`, string(content), "Should marshal the metadata")
+
+	part, err = reader.NextPart()
+	assert.Nil(t, err, "Should send the media part")
+	assert.Equal(t, "image/png", part.Header.Get("Content-Type"), "Should type the media part")
+	content, _ = ioutil.ReadAll(part)
+	assert.Equal(t, media, content, "Should send the media as is")
+
+	_, err = reader.NextPart()
+	assert.NotNil(t, err, "Should send two parts only")
+
+	_, err = NewRequestBuilder().WithUrl("https://www.googleapis.com/upload").WithMethod("POST").
+		WithBody(NewMultipartRelated(`{"name":"a"}`, "text/plain", failingReader{})).Build().Send()
+
+	assert.True(t, strings.HasSuffix(err.Error(), "Disk failure"), "Should fail requests whose media cannot be read, got %v", err)
+}
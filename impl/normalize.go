@@ -0,0 +1,140 @@
+package gorequest
+
+import (
+	"net/url"
+	"strings"
+)
+
+/**
+ * Normalizes rawURL so that URLs designating the same resource compare
+ * equal: the scheme and host are lowercased, default ports removed, dot
+ * segments resolved, percent-encodings made canonical and the fragment
+ * dropped. Query parameters are sorted by name only if sortQuery is set,
+ * since their order matters to some servers.
+ */
+func NormalizeURL(rawURL string, sortQuery bool) (string, error) {
+
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeURL(u, sortQuery), nil
+}
+
+func normalizeURL(u *url.URL, sortQuery bool) string {
+
+	normalized := *u
+
+	normalized.Scheme = strings.ToLower(u.Scheme)
+	normalized.Host = strings.ToLower(u.Host)
+	normalized.Fragment = ""
+	normalized.RawFragment = ""
+	normalized.ForceQuery = false
+
+	if port := u.Port(); port != "" && defaultPorts[normalized.Scheme] == port {
+		normalized.Host = strings.TrimSuffix(normalized.Host, ":"+port)
+	}
+
+	// Escapes that matter, e.g. %2F, are kept
+	if u.RawPath != "" {
+		normalized.RawPath = removeDotSegments(normalizeEscapes(u.RawPath))
+		normalized.Path, _ = url.PathUnescape(normalized.RawPath)
+	} else {
+		normalized.Path = removeDotSegments(u.Path)
+	}
+
+	if normalized.Path == "" && normalized.Host != "" {
+		normalized.Path = "/"
+	}
+
+	if sortQuery {
+		if query, err := url.ParseQuery(u.RawQuery); err == nil {
+			normalized.RawQuery = query.Encode()
+		}
+	} else {
+		normalized.RawQuery = normalizeEscapes(u.RawQuery)
+	}
+
+	return normalized.String()
+}
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+}
+
+/**
+ * Resolves the . and .. segments of path as RFC 3986 (5.2.4) does.
+ */
+func removeDotSegments(path string) string {
+
+	if !strings.Contains(path, ".") {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	resolved := make([]string, 0, len(segments))
+
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		switch segment {
+		case ".":
+			if last {
+				resolved = append(resolved, "")
+			}
+		case "..":
+			// Never above the root
+			if len(resolved) > 1 || len(resolved) == 1 && resolved[0] != "" {
+				resolved = resolved[:len(resolved)-1]
+			}
+			if last {
+				resolved = append(resolved, "")
+			}
+		default:
+			resolved = append(resolved, segment)
+		}
+	}
+
+	return strings.Join(resolved, "/")
+}
+
+/**
+ * Uppercases the hexadecimal digits of percent-encodings, and decodes those
+ * of unreserved characters (RFC 3986, 6.2.2).
+ */
+func normalizeEscapes(s string) string {
+
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			escape := strings.ToUpper(s[i : i+3])
+			if decoded, err := url.PathUnescape(escape); err == nil && isUnreserved(decoded[0]) {
+				b.WriteByte(decoded[0])
+			} else {
+				b.WriteString(escape)
+			}
+			i += 2
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
@@ -0,0 +1,69 @@
+package gorequest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	cases := []struct {
+		url       string
+		sortQuery bool
+		expected  string
+	}{
+		{"HTTPS://API.Example.COM:443/users", false, "https://api.example.com/users"},
+		{"http://api.example.com:80", false, "http://api.example.com/"},
+		{"http://api.example.com:8080/", false, "http://api.example.com:8080/"},
+		{"https://api.example.com/a/./b/../c/", false, "https://api.example.com/a/c/"},
+		{"https://api.example.com/a/b/..", false, "https://api.example.com/a/"},
+		{"https://api.example.com/../a", false, "https://api.example.com/a"},
+		{"https://api.example.com/caf%c3%a9/%7Euser", false, "https://api.example.com/caf%C3%A9/~user"},
+		{"https://api.example.com/files/a%2fb", false, "https://api.example.com/files/a%2Fb"},
+		{"https://api.example.com/users?b=2&a=1&a=0#top", false, "https://api.example.com/users?b=2&a=1&a=0"},
+		{"https://api.example.com/users?b=2&a=1&a=0", true, "https://api.example.com/users?a=1&a=0&b=2"},
+		{"https://api.example.com/users?", false, "https://api.example.com/users"},
+	}
+
+	for _, c := range cases {
+		normalized, err := NormalizeURL(c.url, c.sortQuery)
+		assert.Nil(t, err, "Should parse "+c.url)
+		assert.Equal(t, c.expected, normalized, "Should normalize "+c.url)
+	}
+
+	_, err := NormalizeURL("https://api.example.com/%zz", false)
+
+	assert.NotNil(t, err, "Should fail on invalid URLs")
+}
+
+func TestNormalizedCacheKeys(t *testing.T) {
+	mock := requestmock.New()
+
+	users := mock.On("GET", "/users").Reply(http.StatusOK, "users")
+
+	get := func(c model.Client, url string) {
+		NewRequestBuilder().WithUrl(url).WithCacheTTL(time.Minute).WithClient(c).Build().Do()
+	}
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	get(c, "https://api.example.com/users?page=1&size=10")
+	get(c, "HTTPS://API.EXAMPLE.COM:443/v1/../users?page=1&size=10#list")
+
+	assert.Equal(t, 1, users.Calls(), "Should memoize equivalent URLs together")
+
+	get(c, "https://api.example.com/users?size=10&page=1")
+
+	assert.Equal(t, 2, users.Calls(), "Should keep the order of the query by default")
+
+	c = NewClientBuilder().WithTransport(mock).WithCanonicalQuery().Build()
+
+	get(c, "https://api.example.com/users?page=1&size=10")
+	get(c, "https://api.example.com/users?size=10&page=1")
+
+	assert.Equal(t, 3, users.Calls(), "Should ignore the order of the query when asked to")
+}
@@ -0,0 +1,26 @@
+package gorequest
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+/**
+ * Transport of offline clients: requests never reach the network, only the
+ * replay transport, if any.
+ */
+type offlineTransport struct {
+	replay http.RoundTripper
+}
+
+func (t *offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if t.replay == nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, model.ErrOffline
+	}
+
+	return t.replay.RoundTrip(req)
+}
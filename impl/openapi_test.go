@@ -0,0 +1,121 @@
+package gorequest
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	openapi "github.com/demianlessa/gorequest/openapi"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIContract(t *testing.T) {
+	spec, err := openapi.Load(filepath.Join("testdata", "users.yaml"))
+
+	assert.Nil(t, err, "Should load the document")
+
+	mock := requestmock.New()
+
+	mock.On("GET", "/v1/users/1").ReplyJSON(http.StatusOK, map[string]interface{}{"id": 1, "name": "alice"})
+	mock.On("GET", "/v1/users/2").ReplyJSON(http.StatusOK, map[string]interface{}{"id": "2", "name": "bob", "admin": true})
+	mock.On("GET", "/v1/users/3").Reply(http.StatusInternalServerError, "")
+	mock.On("POST", "/v1/users").Reply(http.StatusCreated, "")
+
+	contract := &openapi.Contract{Spec: spec}
+	c := NewClientBuilder().WithTransport(mock).WithHooks(contract.Hooks()).Build()
+
+	do := func(method string, url string, body string) (violation *openapi.Violation) {
+		defer func() {
+			if err := recover(); err != nil {
+				violation = err.(*openapi.Violation)
+			}
+		}()
+		builder := NewRequestBuilder().WithUrl("https://api.example.com/v1" + url).WithMethod(method).WithClient(c)
+		if body != "" {
+			builder = builder.WithBody(newJsonBody(body))
+		}
+		builder.Build().Do()
+		return nil
+	}
+
+	assert.Nil(t, do("GET", "/users/1", ""), "Should accept a valid exchange")
+	assert.Nil(t, do("POST", "/users?notify=true", `{"id":3,"name":"carol","role":"admin"}`), "Should accept a valid request body")
+
+	violation := do("GET", "/users/2", "")
+
+	assert.True(t, violation.Response, "Should fail on the response")
+	assert.Equal(t, []string{`$: unexpected property "admin"`, "$.id: expected integer, got string"}, violation.Problems, "Should list the schema problems")
+
+	violation = do("GET", "/users/3", "")
+
+	assert.Equal(t, []string{"undocumented status 500"}, violation.Problems, "Should fail on undocumented statuses")
+
+	violation = do("POST", "/users?notify=maybe", `{"id":0,"role":"guest"}`)
+
+	assert.False(t, violation.Response, "Should fail on the request")
+	assert.Equal(t, []string{
+		`query parameter "notify": expected boolean, got string`,
+		`$: missing required property "name"`,
+		"$.id: 0 is below the minimum of 1",
+		"$.role: guest is not one of [admin member]",
+	}, violation.Problems, "Should validate parameters and bodies")
+
+	violation = do("GET", "/users/abc", "")
+
+	assert.Equal(t, []string{`path parameter "id": expected integer, got string`}, violation.Problems, "Should validate path parameters")
+
+	violation = do("DELETE", "/users/1", "")
+
+	assert.Equal(t, []string{"no operation documented for DELETE /v1/users/1"}, violation.Problems, "Should fail on undocumented operations")
+
+	var reported []*openapi.Violation
+
+	contract = &openapi.Contract{Spec: spec, Mode: openapi.ModeReport, OnViolation: func(v *openapi.Violation) { reported = append(reported, v) }}
+	c = NewClientBuilder().WithTransport(mock).WithHooks(contract.Hooks()).Build()
+
+	assert.Nil(t, do("GET", "/users/2", ""), "Should not fail when reporting")
+	assert.Len(t, reported, 1, "Should report the violation")
+}
+
+func TestOpenAPIRequestValidation(t *testing.T) {
+	spec, err := openapi.Load(filepath.Join("testdata", "users.yaml"))
+
+	assert.Nil(t, err, "Should load the document")
+
+	request := func(method string, url string) model.RequestBuilder {
+		return NewRequestBuilder().WithUrl("https://api.example.com/v1" + url).WithMethod(method)
+	}
+
+	assert.Nil(t, spec.Validate(request("GET", "/users?since=2026-10-01T00:00:00Z")), "Should accept valid requests")
+
+	err = spec.Validate(request("GET", "/users"))
+
+	assert.EqualError(t, err, `OpenAPI contract violated by the request GET https://api.example.com/v1/users: missing required query parameter "since"`, "Should name the missing parameter")
+
+	err = spec.Validate(request("POST", "/users").WithBody(newJsonBody(`{"id":1}`)))
+
+	violation, ok := err.(*openapi.Violation)
+
+	assert.True(t, ok, "Should return a violation")
+	assert.Equal(t, []string{`$: missing required property "name"`}, violation.Problems, "Should validate the body")
+
+	mock := requestmock.New()
+
+	mock.On("GET", "/v1/users/1").Reply(http.StatusInternalServerError, "")
+	mock.On("GET", "/v1/users").Reply(http.StatusOK, "")
+
+	c := NewClientBuilder().WithTransport(mock).WithHooks(spec.RequestHooks()).Build()
+
+	_, err = request("GET", "/users?since=yesterday").WithClient(c).Build().Send()
+
+	assert.True(t, errors.As(err, &violation), "Should fail invalid requests")
+	assert.Equal(t, []string{`query parameter "since": "yesterday" is not a valid date-time`}, violation.Problems, "Should validate formats")
+
+	resp, err := request("GET", "/users/1").WithClient(c).Build().Send()
+
+	assert.Nil(t, err, "Should not validate responses")
+	assert.Equal(t, http.StatusInternalServerError, resp.Response().StatusCode, "Should return the response")
+}
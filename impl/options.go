@@ -0,0 +1,213 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"time"
+)
+
+/**
+ * Builds a Client from options, e.g.
+ * NewClient(WithTimeout(10*time.Second), WithBaseURL(u), WithRetry(r)).
+ * Panics on options that only apply to requests.
+ */
+func NewClient(options ...model.Option) model.Client {
+
+	builder := NewClientBuilder()
+
+	for _, option := range options {
+		if option.Client == nil {
+			panic(errors.New("Option does not apply to clients"))
+		}
+		option.Client(builder)
+	}
+
+	return builder.Build()
+}
+
+/**
+ * Builds a Request from options, e.g.
+ * NewRequest(WithUrl(u), WithMethod("POST"), WithBody(b)).Do().
+ * Panics on options that only apply to clients.
+ */
+func NewRequest(options ...model.Option) model.Request {
+	return newRequestBuilder(options).Build()
+}
+
+func newRequestBuilder(options []model.Option) model.RequestBuilder {
+
+	builder := NewRequestBuilder()
+
+	for _, option := range options {
+		if option.Request == nil {
+			panic(errors.New("Option does not apply to requests"))
+		}
+		option.Request(builder)
+	}
+
+	return builder
+}
+
+/****************************************************
+ * Options for clients and requests
+ ****************************************************/
+
+func WithHedging(hedge model.Hedge) model.Option {
+	return model.Option{
+		Client:  func(b model.ClientBuilder) { b.WithHedging(hedge) },
+		Request: func(b model.RequestBuilder) { b.WithHedging(hedge) },
+	}
+}
+
+func WithRedirectPolicy(policy model.RedirectPolicy) model.Option {
+	return model.Option{
+		Client:  func(b model.ClientBuilder) { b.WithRedirectPolicy(policy) },
+		Request: func(b model.RequestBuilder) { b.WithRedirectPolicy(policy) },
+	}
+}
+
+func WithRetry(retry model.Retry) model.Option {
+	return model.Option{
+		Client:  func(b model.ClientBuilder) { b.WithRetry(retry) },
+		Request: func(b model.RequestBuilder) { b.WithRetry(retry) },
+	}
+}
+
+/****************************************************
+ * Client options
+ ****************************************************/
+
+/**
+ * Base URLs for requests with relative URLs; see ClientBuilder.WithEndpoints.
+ */
+func WithBaseURL(baseURLs ...string) model.Option {
+	return model.Option{
+		Client: func(b model.ClientBuilder) { b.WithEndpoints(baseURLs...) },
+	}
+}
+
+func WithHooks(hooks model.Hooks) model.Option {
+	return model.Option{
+		Client: func(b model.ClientBuilder) { b.WithHooks(hooks) },
+	}
+}
+
+func WithTimeout(timeout time.Duration) model.Option {
+	return model.Option{
+		Client: func(b model.ClientBuilder) { b.WithTimeout(timeout) },
+	}
+}
+
+func WithTransport(transport http.RoundTripper) model.Option {
+	return model.Option{
+		Client: func(b model.ClientBuilder) { b.WithTransport(transport) },
+	}
+}
+
+/****************************************************
+ * Request options
+ ****************************************************/
+
+func WithAcceptEncoding(encodings ...string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithAcceptEncoding(encodings...) },
+	}
+}
+
+func WithBasicAuth(user string, password string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithBasicAuth(user, password) },
+	}
+}
+
+func WithBearerAuth(token string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithBearerAuth(token) },
+	}
+}
+
+func WithBody(body model.RequestBody) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithBody(body) },
+	}
+}
+
+func WithChecksum(checksum model.Checksum) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithChecksum(checksum) },
+	}
+}
+
+func WithChunking(chunking model.Chunking) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithChunking(chunking) },
+	}
+}
+
+func WithClient(client model.Client) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithClient(client) },
+	}
+}
+
+func WithContentDigest(algorithm string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithContentDigest(algorithm) },
+	}
+}
+
+func WithContext(ctx context.Context) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithContext(ctx) },
+	}
+}
+
+func WithHeader(name, value string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithHeader(name, value) },
+	}
+}
+
+func WithHeaders(headers model.Headers) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithHeaders(headers) },
+	}
+}
+
+func WithMethod(method string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithMethod(method) },
+	}
+}
+
+/**
+ * Asks for length bytes of the resource from offset: to its end if length
+ * is not positive, its last -offset bytes if offset is negative. Check the
+ * response with ContentRange.
+ */
+func WithRange(offset int64, length int64) model.Option {
+	return WithRangeHeader(byteRange(offset, length))
+}
+
+/**
+ * Sets the Range header as is, e.g. "bytes=0-99,200-299".
+ */
+func WithRangeHeader(value string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithHeader("Range", value) },
+	}
+}
+
+func WithUrl(url string) model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithUrl(url) },
+	}
+}
+
+func WithoutDecompression() model.Option {
+	return model.Option{
+		Request: func(b model.RequestBuilder) { b.WithoutDecompression() },
+	}
+}
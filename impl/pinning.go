@@ -0,0 +1,98 @@
+package gorequest
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"strings"
+)
+
+/**
+ * SPKI pins are base64 encoded SHA-256 digests of a certificate's
+ * SubjectPublicKeyInfo, optionally prefixed with "sha256/" (the format
+ * printed by most pinning tools).
+ */
+const pinPrefix = "sha256/"
+
+type pinSet struct {
+	pins     map[string]map[string]bool
+	reporter model.PinningReporter
+}
+
+func newPinSet(pins map[string][]string, reporter model.PinningReporter) *pinSet {
+
+	set := &pinSet{
+		pins:     make(map[string]map[string]bool),
+		reporter: reporter,
+	}
+
+	for host, hostPins := range pins {
+		set.pins[strings.ToLower(host)] = make(map[string]bool)
+		for _, pin := range hostPins {
+			set.pins[strings.ToLower(host)][normalizePin(pin)] = true
+		}
+	}
+
+	return set
+}
+
+/**
+ * Intended for tls.Config.VerifyConnection. Pins are matched against the TLS
+ * server name, so IP-dialed hosts need an explicit server name to be pinned.
+ * Hosts without pins are accepted; for pinned hosts at least one certificate
+ * of a verified chain must match, which is what allows backup pins. The
+ * chain as sent by the peer is not trusted: anyone can append a pinned
+ * certificate to it.
+ */
+func (s *pinSet) verify(state tls.ConnectionState) error {
+
+	hostPins, ok := s.pins[strings.ToLower(state.ServerName)]
+
+	if !ok {
+		return nil
+	}
+
+	for _, chain := range state.VerifiedChains {
+		for _, cert := range chain {
+			if hostPins[spkiPin(cert.RawSubjectPublicKeyInfo)] {
+				return nil
+			}
+		}
+	}
+
+	presented := make([]string, 0, len(state.PeerCertificates))
+
+	for _, cert := range state.PeerCertificates {
+		presented = append(presented, pinPrefix+spkiPin(cert.RawSubjectPublicKeyInfo))
+	}
+
+	err := &model.PinningError{
+		Host:      state.ServerName,
+		Presented: presented,
+	}
+
+	if s.reporter != nil {
+		s.reporter(err)
+		return nil
+	}
+
+	return err
+}
+
+func normalizePin(pin string) string {
+
+	pin = strings.TrimPrefix(strings.TrimSpace(pin), pinPrefix)
+
+	if digest, err := base64.StdEncoding.DecodeString(pin); err != nil || len(digest) != sha256.Size {
+		panic(fmt.Errorf("Invalid SPKI pin: %s", pin))
+	}
+
+	return pin
+}
+
+func spkiPin(spki []byte) string {
+	digest := sha256.Sum256(spki)
+	return base64.StdEncoding.EncodeToString(digest[:])
+}
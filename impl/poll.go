@@ -0,0 +1,74 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"math/rand"
+	"time"
+)
+
+/**
+ * Sends the request of options every interval until until reports it done
+ * or fails, e.g. to wait on the status endpoint of an asynchronous job.
+ * Intervals are jittered by up to 20% either way, so that pollers started
+ * together drift apart. 429 and 503 responses asking for a delay (see
+ * Retry-After) are not passed to until; the next request waits as asked
+ * instead, for at most the MaxRetryAfter of the retry policy of the
+ * request (one minute by default). Returns the response until was done with, or the first error:
+ * that of a request, of until or of ctx, which bounds the whole wait.
+ */
+func Poll(ctx context.Context, options []model.Option, interval time.Duration, until func(resp model.Response) (bool, error)) (model.Response, error) {
+
+	for {
+
+		req := NewRequest(append(append([]model.Option(nil), options...), WithContext(ctx))...)
+		clock := model.Clock(systemClock{})
+		policy := &model.Retry{}
+
+		if r, ok := req.(*request); ok {
+			if r.client != nil {
+				clock = r.client.clock
+			}
+			if r.options.retry != nil {
+				policy = r.options.retry
+			}
+		}
+
+		resp, err := req.Send()
+
+		if err != nil {
+			return resp, err
+		}
+
+		received, _ := resp.(*response)
+		delay, throttled := requestedDelay(policy, received, clock.Now())
+
+		if !throttled {
+
+			done, err := until(resp)
+
+			if err != nil || done {
+				return resp, err
+			}
+
+			delay = jitter(interval)
+		}
+
+		select {
+		case <-clock.After(delay):
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		}
+	}
+}
+
+/**
+ * Returns interval shifted randomly by up to 20% either way.
+ */
+func jitter(interval time.Duration) time.Duration {
+	spread := int64(interval) * 2 / 5
+	if spread <= 0 {
+		return interval
+	}
+	return interval - time.Duration(spread/2) + time.Duration(rand.Int63n(spread+1))
+}
@@ -0,0 +1,99 @@
+package gorequest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoll(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/jobs/7").Times(1).ReplyJSON(http.StatusOK, map[string]string{"status": "pending"})
+	mock.On("GET", "/jobs/7").Times(1).ReplyHeader("Retry-After", "20").Reply(http.StatusTooManyRequests, "")
+	mock.On("GET", "/jobs/7").Times(1).ReplyJSON(http.StatusOK, map[string]string{"status": "pending"})
+	mock.On("GET", "/jobs/7").ReplyJSON(http.StatusOK, map[string]string{"status": "complete"})
+
+	start := time.Now()
+	clock := requestmock.NewClock(start).AutoAdvance()
+	c := NewClientBuilder().WithTransport(mock).WithClock(clock).Build()
+
+	polls := 0
+
+	resp, err := Poll(context.Background(), []model.Option{WithClient(c), WithUrl("https://api.example.com/jobs/7")}, 10*time.Second,
+		func(resp model.Response) (bool, error) {
+			polls++
+			var job struct{ Status string }
+			err := json.Unmarshal(resp.Body(), &job)
+			return job.Status == "complete", err
+		})
+
+	assert.Nil(t, err, "Should poll until complete")
+	assert.JSONEq(t, `{"status":"complete"}`, string(resp.Body()), "Should return the last response")
+	assert.Equal(t, 3, polls, "Should not pass throttled responses to until")
+	assert.Len(t, mock.Calls(), 4, "Should send a request per poll")
+
+	elapsed := clock.Now().Sub(start)
+
+	assert.True(t, elapsed >= 36*time.Second && elapsed <= 44*time.Second, "Should wait jittered intervals and Retry-After, waited %s", elapsed)
+
+	failed := errors.New("Job failed")
+
+	_, err = Poll(context.Background(), []model.Option{WithClient(c), WithUrl("https://api.example.com/jobs/7")}, time.Second,
+		func(resp model.Response) (bool, error) { return false, failed })
+
+	assert.Equal(t, failed, err, "Should stop on errors of until")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = Poll(ctx, []model.Option{WithClient(NewClientBuilder().WithTransport(mock).Build()), WithUrl("https://api.example.com/jobs/7")}, time.Millisecond,
+		func(resp model.Response) (bool, error) { return false, nil })
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Should stop when ctx is done, got %v", err)
+}
+
+func TestPollCapsRetryAfter(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/jobs/7").Times(1).ReplyHeader("Retry-After", "86400").Reply(http.StatusServiceUnavailable, "")
+	mock.On("GET", "/jobs/7").ReplyJSON(http.StatusOK, map[string]string{"status": "complete"})
+
+	start := time.Now()
+	clock := requestmock.NewClock(start).AutoAdvance()
+	c := NewClientBuilder().WithTransport(mock).WithClock(clock).Build()
+
+	_, err := Poll(context.Background(), []model.Option{WithClient(c), WithUrl("https://api.example.com/jobs/7")}, time.Second,
+		func(resp model.Response) (bool, error) { return true, nil })
+
+	assert.Nil(t, err, "Should poll until complete")
+	assert.Equal(t, time.Minute, clock.Now().Sub(start), "Should cap the delay asked for by a minute")
+}
+
+func TestPollResendsBody(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("POST", "/jobs/search").ReplyJSON(http.StatusOK, map[string]string{"status": "pending"})
+
+	c := NewClientBuilder().WithTransport(mock).WithClock(requestmock.NewClock(time.Now()).AutoAdvance()).Build()
+
+	polls := 0
+
+	Poll(context.Background(), []model.Option{WithClient(c), WithMethod("POST"), WithUrl("https://api.example.com/jobs/search"), WithBody(newJsonBody(`{"job":1}`))}, time.Second,
+		func(resp model.Response) (bool, error) {
+			polls++
+			return polls == 3, nil
+		})
+
+	for _, call := range mock.Calls() {
+		assert.Equal(t, `{"job":1}`, string(call.Body), "Should send the whole body on every poll")
+	}
+
+	assert.Len(t, mock.Calls(), 3, "Should send a request per poll")
+}
@@ -0,0 +1,477 @@
+package gorequest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/****************************************************
+ * model.PostmanCollection implementation
+ ****************************************************/
+
+type postmanCollection struct {
+	names     []string
+	requests  map[string]*postmanRequest
+	variables map[string]string
+}
+
+/**
+ * A request along with the auth it inherits from its folders.
+ */
+type postmanRequest struct {
+	auth    *postmanAuth
+	request *postmanRequestSpec
+}
+
+/**
+ * Parses a Postman collection (v2.1) and, if not nil, an environment
+ * exported from Postman. Of the requests, the method, URL (path variables
+ * included), enabled headers, raw, urlencoded, text form-data and GraphQL
+ * bodies and the basic, bearer, API key and no-auth authorizations
+ * (inherited from folders and the collection) are kept; scripts are
+ * ignored. Besides those defined, the {{$guid}}, {{$timestamp}} and
+ * {{$randomInt}} dynamic variables are known.
+ */
+func LoadPostmanCollection(collection []byte, environment []byte) (model.PostmanCollection, error) {
+
+	var document struct {
+		Auth *postmanAuth `json:"auth"`
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+		Item     []*postmanItem `json:"item"`
+		Variable []postmanValue `json:"variable"`
+	}
+
+	if err := json.Unmarshal(collection, &document); err != nil {
+		return nil, fmt.Errorf("Cannot parse Postman collection: %w", err)
+	}
+
+	if !strings.Contains(document.Info.Schema, "/v2.1.") {
+		return nil, fmt.Errorf("Unsupported Postman collection schema %q", document.Info.Schema)
+	}
+
+	c := &postmanCollection{
+		requests:  map[string]*postmanRequest{},
+		variables: map[string]string{},
+	}
+
+	for _, variable := range document.Variable {
+		if !variable.Disabled {
+			c.variables[variable.Key] = variable.Value.String()
+		}
+	}
+
+	if environment != nil {
+
+		var env struct {
+			Values []postmanValue `json:"values"`
+		}
+
+		if err := json.Unmarshal(environment, &env); err != nil {
+			return nil, fmt.Errorf("Cannot parse Postman environment: %w", err)
+		}
+
+		for _, variable := range env.Values {
+			if variable.Enabled == nil || *variable.Enabled {
+				c.variables[variable.Key] = variable.Value.String()
+			}
+		}
+	}
+
+	c.add("", document.Item, document.Auth)
+
+	return c, nil
+}
+
+/**
+ * Adds items, the content of the folder prefix, recursively. Of requests
+ * sharing a name, the first one is kept.
+ */
+func (c *postmanCollection) add(prefix string, items []*postmanItem, auth *postmanAuth) {
+	for _, item := range items {
+
+		inherited := auth
+		if item.Auth != nil {
+			inherited = item.Auth
+		}
+
+		if item.Request == nil {
+			c.add(prefix+item.Name+"/", item.Item, inherited)
+			continue
+		}
+
+		name := prefix + item.Name
+
+		if _, exists := c.requests[name]; exists {
+			continue
+		}
+
+		if item.Request.Auth != nil {
+			inherited = item.Request.Auth
+		}
+
+		c.names = append(c.names, name)
+		c.requests[name] = &postmanRequest{auth: inherited, request: item.Request}
+	}
+}
+
+func (c *postmanCollection) Names() []string {
+	return append([]string(nil), c.names...)
+}
+
+func (c *postmanCollection) Options(name string, variables map[string]string) ([]model.Option, error) {
+
+	entry, ok := c.requests[name]
+
+	if !ok {
+		return nil, fmt.Errorf("No request named %q in the Postman collection", name)
+	}
+
+	r := &postmanResolver{collection: c, variables: variables}
+	spec := entry.request
+
+	method := strings.ToUpper(spec.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	options := []model.Option{WithMethod(method)}
+
+	target := r.url(spec.URL)
+	headers := model.Headers{}
+
+	for _, header := range spec.Header {
+		if !header.Disabled {
+			headers.Set(r.resolve(header.Key), r.resolve(header.Value.String()))
+		}
+	}
+
+	if auth := entry.auth; auth != nil {
+		switch auth.Type {
+		case "apikey":
+			key, value := r.resolve(auth.value("key")), r.resolve(auth.value("value"))
+			if auth.value("in") == "query" {
+				target = appendQuery(target, url.QueryEscape(key)+"="+url.QueryEscape(value))
+			} else {
+				headers.Set(key, value)
+			}
+		case "basic":
+			options = append(options, WithBasicAuth(r.resolve(auth.value("username")), r.resolve(auth.value("password"))))
+		case "bearer":
+			options = append(options, WithBearerAuth(r.resolve(auth.value("token"))))
+		case "noauth":
+		default:
+			return nil, fmt.Errorf("Unsupported Postman auth type %q in %q", auth.Type, name)
+		}
+	}
+
+	if body := spec.Body; body != nil && !body.Disabled {
+
+		contentType := headers.Get(model.HeaderContentType)
+
+		var data string
+
+		switch body.Mode {
+		case "raw":
+			data = r.resolve(body.Raw)
+			if contentType == "" {
+				contentType = rawContentTypes[body.Options.Raw.Language]
+			}
+		case "urlencoded", "formdata":
+			var pairs []string
+			for _, field := range body.fields() {
+				if field.Disabled {
+					continue
+				}
+				if field.Type == "file" {
+					return nil, fmt.Errorf("Cannot send the file of form field %q in %q", field.Key, name)
+				}
+				pairs = append(pairs, url.QueryEscape(r.resolve(field.Key))+"="+url.QueryEscape(r.resolve(field.Value.String())))
+			}
+			// In the order of the collection, unlike url.Values
+			data = strings.Join(pairs, "&")
+			// Form data is sent urlencoded: its fields are all text
+			contentType = "application/x-www-form-urlencoded"
+		case "graphql":
+			raw, err := json.Marshal(map[string]interface{}{
+				"query":     r.resolve(body.GraphQL.Query),
+				"variables": json.RawMessage(orNull(r.resolve(body.GraphQL.Variables))),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Invalid GraphQL variables in %q: %w", name, err)
+			}
+			data = string(raw)
+			contentType = "application/json"
+		case "", "none":
+		default:
+			return nil, fmt.Errorf("Unsupported Postman body mode %q in %q", body.Mode, name)
+		}
+
+		if data != "" {
+			if contentType == "" {
+				contentType = "text/plain"
+			}
+			headers.Del(model.HeaderContentType)
+			options = append(options, WithBody(&requestBody{contentType: contentType, data: bytes.NewBufferString(data)}))
+		}
+	}
+
+	if r.err != nil {
+		return nil, fmt.Errorf("%w in %q", r.err, name)
+	}
+
+	if len(headers) > 0 {
+		options = append(options, WithHeaders(headers))
+	}
+
+	return append(options, WithUrl(target)), nil
+}
+
+/****************************************************
+ * Variables
+ ****************************************************/
+
+var postmanVariable = regexp.MustCompile(`\{\{([^{}]+)\}\}`)
+
+/**
+ * Replaces variables, remembering the first one that is not defined.
+ */
+type postmanResolver struct {
+	collection *postmanCollection
+	err        error
+	variables  map[string]string
+}
+
+func (r *postmanResolver) resolve(text string) string {
+	// Values may refer to other variables, up to a few levels deep
+	for depth := 0; depth < 10 && strings.Contains(text, "{{"); depth++ {
+		text = postmanVariable.ReplaceAllStringFunc(text, func(match string) string {
+			name := strings.TrimSpace(match[2 : len(match)-2])
+			if value, ok := r.lookup(name); ok {
+				return value
+			}
+			if r.err == nil {
+				r.err = fmt.Errorf("Undefined variable {{%s}}", name)
+			}
+			return match
+		})
+		if r.err != nil {
+			break
+		}
+	}
+	return text
+}
+
+func (r *postmanResolver) lookup(name string) (string, bool) {
+
+	if value, ok := r.variables[name]; ok {
+		return value, true
+	}
+
+	if value, ok := r.collection.variables[name]; ok {
+		return value, true
+	}
+
+	switch name {
+	case "$guid":
+		var id [16]byte
+		rand.Read(id[:])
+		id[6] = id[6]&0x0f | 0x40
+		id[8] = id[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), true
+	case "$randomInt":
+		n, _ := rand.Int(rand.Reader, big.NewInt(1001))
+		return n.String(), true
+	case "$timestamp":
+		return strconv.FormatInt(time.Now().Unix(), 10), true
+	}
+
+	return "", false
+}
+
+/**
+ * Resolves the URL of a request: its raw form, with the :name path
+ * variables replaced.
+ */
+func (r *postmanResolver) url(u postmanURL) string {
+
+	target := r.resolve(u.Raw)
+
+	for _, variable := range u.Variable {
+		value := url.PathEscape(r.resolve(variable.Value.String()))
+		target = postmanPathVariable(variable.Key).ReplaceAllString(target, "/"+strings.Replace(value, "$", "$$", -1)+"$1")
+	}
+
+	return target
+}
+
+func postmanPathVariable(name string) *regexp.Regexp {
+	return regexp.MustCompile(`/:` + regexp.QuoteMeta(name) + `([/?#]|$)`)
+}
+
+func appendQuery(target string, query string) string {
+	if strings.Contains(target, "?") {
+		return target + "&" + query
+	}
+	return target + "?" + query
+}
+
+func orNull(raw string) string {
+	if strings.TrimSpace(raw) == "" {
+		return "null"
+	}
+	return raw
+}
+
+var rawContentTypes = map[string]string{
+	"html":       "text/html",
+	"javascript": "application/javascript",
+	"json":       "application/json",
+	"text":       "text/plain",
+	"xml":        "application/xml",
+}
+
+/****************************************************
+ * Collection format
+ ****************************************************/
+
+type postmanItem struct {
+	Auth    *postmanAuth        `json:"auth"`
+	Item    []*postmanItem      `json:"item"`
+	Name    string              `json:"name"`
+	Request *postmanRequestSpec `json:"request"`
+}
+
+type postmanRequestSpec struct {
+	Auth   *postmanAuth   `json:"auth"`
+	Body   *postmanBody   `json:"body"`
+	Header []postmanValue `json:"header"`
+	Method string         `json:"method"`
+	URL    postmanURL     `json:"url"`
+}
+
+/**
+ * Either a string or an object with the raw URL and its parts.
+ */
+type postmanURL struct {
+	Raw      string         `json:"raw"`
+	Variable []postmanValue `json:"variable"`
+}
+
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &u.Raw)
+	}
+
+	type plain postmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+type postmanBody struct {
+	Disabled bool           `json:"disabled"`
+	FormData []postmanValue `json:"formdata"`
+	GraphQL  struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Mode    string `json:"mode"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Raw        string         `json:"raw"`
+	URLEncoded []postmanValue `json:"urlencoded"`
+}
+
+func (b *postmanBody) fields() []postmanValue {
+	if b.Mode == "formdata" {
+		return b.FormData
+	}
+	return b.URLEncoded
+}
+
+/**
+ * The auth of a request, a folder or the collection. Its parameters are
+ * listed under the name of its type, e.g. "bearer": [{"key": "token", ...}].
+ */
+type postmanAuth struct {
+	Type   string
+	params []postmanValue
+}
+
+func (a *postmanAuth) UnmarshalJSON(data []byte) error {
+
+	var raw map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw["type"], &a.Type); err != nil {
+		return err
+	}
+
+	// Absent for noauth
+	if params, ok := raw[a.Type]; ok {
+		return json.Unmarshal(params, &a.params)
+	}
+
+	return nil
+}
+
+func (a *postmanAuth) value(key string) string {
+	for _, param := range a.params {
+		if param.Key == key {
+			return param.Value.String()
+		}
+	}
+	return ""
+}
+
+/**
+ * A key/value pair: a header, a variable, a form field or an auth
+ * parameter.
+ */
+type postmanValue struct {
+	Disabled bool             `json:"disabled"`
+	Enabled  *bool            `json:"enabled"`
+	Key      string           `json:"key"`
+	Type     string           `json:"type"`
+	Value    postmanValueText `json:"value"`
+}
+
+/**
+ * Values are usually strings, but auth parameters and variables may be
+ * numbers or booleans.
+ */
+type postmanValueText struct {
+	raw json.RawMessage
+}
+
+func (v *postmanValueText) UnmarshalJSON(data []byte) error {
+	v.raw = append(v.raw[:0], data...)
+	return nil
+}
+
+func (v postmanValueText) String() string {
+	var text string
+	if err := json.Unmarshal(v.raw, &text); err == nil {
+		return text
+	}
+	if string(v.raw) == "null" {
+		return ""
+	}
+	return string(v.raw)
+}
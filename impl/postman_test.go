@@ -0,0 +1,86 @@
+package gorequest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostmanCollection(t *testing.T) {
+	collection, err := ioutil.ReadFile(filepath.Join("testdata", "users.postman_collection.json"))
+	assert.Nil(t, err, "Should read the collection")
+
+	environment, err := ioutil.ReadFile(filepath.Join("testdata", "staging.postman_environment.json"))
+	assert.Nil(t, err, "Should read the environment")
+
+	postman, err := LoadPostmanCollection(collection, environment)
+
+	assert.Nil(t, err, "Should load the collection")
+	assert.Equal(t, []string{"Users/Get user", "Users/Create user", "Login", "Status"}, postman.Names(), "Should name requests after their folders")
+
+	mock := requestmock.New()
+
+	var received *http.Request
+	var body string
+
+	record := func(req *http.Request, raw []byte) bool {
+		received, body = req, string(raw)
+		return true
+	}
+
+	mock.On("GET", "/v1/users/42").Match(record).Reply(http.StatusOK, "")
+	mock.On("POST", "/v1/users").Match(record).Reply(http.StatusCreated, "")
+	mock.On("POST", "/login").Match(record).Reply(http.StatusOK, "")
+	mock.On("GET", "/status").Match(record).Reply(http.StatusOK, "")
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	send := func(name string, variables map[string]string) {
+		options, err := postman.Options(name, variables)
+		assert.Nil(t, err, "Should resolve "+name)
+		_, err = NewRequest(append(options, WithClient(c))...).Send()
+		assert.Nil(t, err, "Should send "+name)
+	}
+
+	send("Users/Get user", map[string]string{"userId": "42"})
+
+	assert.Equal(t, "https://staging.example.com/v1/users/42?fields=name", received.URL.String(), "Should resolve the URL")
+	assert.Equal(t, "application/json", received.Header.Get("Accept"), "Should send enabled headers")
+	assert.Equal(t, "", received.Header.Get("X-Debug"), "Should skip disabled headers")
+	assert.Equal(t, "Bearer staging-token", received.Header.Get("Authorization"), "Should inherit the auth of the collection")
+
+	send("Users/Create user", map[string]string{"name": "bob", "token": "override"})
+
+	assert.Equal(t, `{"name": "bob"}`, body, "Should send the raw body")
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"), "Should derive the content type from the language")
+	assert.Equal(t, "Bearer override", received.Header.Get("Authorization"), "Should let variables override the environment")
+
+	send("Login", nil)
+
+	assert.Equal(t, "user=alice&password=secret", body, "Should send enabled form fields")
+	assert.Equal(t, "application/x-www-form-urlencoded", received.Header.Get("Content-Type"), "Should send urlencoded bodies")
+	assert.Equal(t, "", received.Header.Get("Authorization"), "Should honour noauth")
+
+	_, err = postman.Options("Status", nil)
+
+	assert.EqualError(t, err, `Undefined variable {{apiKey}} in "Status"`, "Should report undefined variables")
+
+	send("Status", map[string]string{"apiKey": "k3y"})
+
+	assert.Equal(t, "k3y", received.URL.Query().Get("api_key"), "Should send API keys in the query")
+
+	_, err = postman.Options("Missing", nil)
+
+	assert.NotNil(t, err, "Should fail on unknown requests")
+
+	_, err = LoadPostmanCollection([]byte(`{"info": {"schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}}`), nil)
+
+	assert.NotNil(t, err, "Should reject other versions of the format")
+
+	var _ model.PostmanCollection = postman
+}
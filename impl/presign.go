@@ -0,0 +1,111 @@
+package gorequest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/****************************************************
+ * model.URLSigner implementation
+ ****************************************************/
+
+const (
+	presignExpires   = "X-Expires"
+	presignSignature = "X-Signature"
+)
+
+type urlSigner struct {
+	clock model.Clock
+	keys  [][]byte
+}
+
+/**
+ * Returns a signer using the settings of signing, which must have a key.
+ */
+func NewURLSigner(signing model.URLSigning) model.URLSigner {
+
+	if len(signing.Key) == 0 {
+		panic(errors.New("URL signing key cannot be empty"))
+	}
+
+	clock := signing.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &urlSigner{
+		clock: clock,
+		keys:  append([][]byte{signing.Key}, signing.PreviousKeys...),
+	}
+}
+
+func (s *urlSigner) Sign(method string, rawURL string, ttl time.Duration) (string, error) {
+
+	u, err := url.Parse(rawURL)
+
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Del(presignSignature)
+	query.Set(presignExpires, strconv.FormatInt(s.clock.Now().Add(ttl).Unix(), 10))
+
+	u.RawQuery = query.Encode()
+	query.Set(presignSignature, s.signature(s.keys[0], method, u))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func (s *urlSigner) Verify(method string, u *url.URL) error {
+
+	query := u.Query()
+	signature := query.Get(presignSignature)
+	expires, err := strconv.ParseInt(query.Get(presignExpires), 10, 64)
+
+	if signature == "" || err != nil {
+		return model.ErrURLSignature
+	}
+
+	query.Del(presignSignature)
+
+	signed := *u
+	signed.RawQuery = query.Encode()
+
+	valid := false
+
+	for _, key := range s.keys {
+		if hmac.Equal([]byte(signature), []byte(s.signature(key, method, &signed))) {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		return model.ErrURLSignature
+	}
+
+	if s.clock.Now().Unix() > expires {
+		return model.ErrURLExpired
+	}
+
+	return nil
+}
+
+/**
+ * Signs the method, the path and the query (sorted, as url.Values encodes
+ * it) of u.
+ */
+func (s *urlSigner) signature(key []byte, method string, u *url.URL) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToUpper(method) + "\n" + u.EscapedPath() + "\n" + u.RawQuery))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package gorequest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresignedURLs(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	clock := requestmock.NewClock(now)
+	signer := NewURLSigner(model.URLSigning{Clock: clock, Key: []byte("secret")})
+
+	signed, err := signer.Sign("GET", "https://files.example.com/reports/q3.pdf?download=1", 10*time.Minute)
+
+	assert.Nil(t, err, "Should sign the URL")
+
+	u, _ := url.Parse(signed)
+
+	assert.Equal(t, "1", u.Query().Get("download"), "Should keep the query")
+	assert.Equal(t, strconv.FormatInt(now.Add(10*time.Minute).Unix(), 10), u.Query().Get("X-Expires"), "Should add the expiry")
+
+	// As received by a server
+	req, _ := http.NewRequest("GET", u.RequestURI(), nil)
+
+	assert.Nil(t, signer.Verify(req.Method, req.URL), "Should accept the URL")
+	assert.Equal(t, model.ErrURLSignature, signer.Verify("PUT", req.URL), "Should bind the URL to its method")
+
+	tampered, _ := url.Parse(signed)
+	query := tampered.Query()
+	query.Set("download", "0")
+	tampered.RawQuery = query.Encode()
+
+	assert.Equal(t, model.ErrURLSignature, signer.Verify("GET", tampered), "Should reject modified queries")
+
+	tampered, _ = url.Parse(signed)
+	tampered.Path = "/reports/q4.pdf"
+
+	assert.Equal(t, model.ErrURLSignature, signer.Verify("GET", tampered), "Should reject modified paths")
+
+	rotated := NewURLSigner(model.URLSigning{Clock: clock, Key: []byte("new"), PreviousKeys: [][]byte{[]byte("secret")}})
+
+	assert.Nil(t, rotated.Verify("GET", req.URL), "Should accept previous keys")
+	assert.Equal(t, model.ErrURLSignature, NewURLSigner(model.URLSigning{Key: []byte("other")}).Verify("GET", req.URL), "Should reject other keys")
+
+	clock.Advance(11 * time.Minute)
+
+	assert.Equal(t, model.ErrURLExpired, signer.Verify("GET", req.URL), "Should reject expired URLs")
+
+	assert.Panics(t, func() { NewURLSigner(model.URLSigning{}) }, "Should require a key")
+}
@@ -0,0 +1,93 @@
+package gorequest
+
+import (
+	"container/heap"
+	model "github.com/demianlessa/gorequest/model"
+)
+
+/**
+ * Values waiting their turn: higher priorities first, then first come,
+ * first served.
+ */
+type priorityHeap struct {
+	items []*prioritized
+	seq   uint64
+}
+
+type prioritized struct {
+	priority model.Priority
+	seq      uint64
+	value    interface{}
+}
+
+func (h *priorityHeap) push(priority model.Priority, value interface{}) {
+	h.seq++
+	heap.Push((*priorityItems)(&h.items), &prioritized{priority: priority, seq: h.seq, value: value})
+}
+
+func (h *priorityHeap) pop() interface{} {
+	return heap.Pop((*priorityItems)(&h.items)).(*prioritized).value
+}
+
+/**
+ * Removes and returns the value that has waited the longest, whatever its
+ * priority.
+ */
+func (h *priorityHeap) dropOldest() interface{} {
+	oldest := 0
+	for i, item := range h.items {
+		if item.seq < h.items[oldest].seq {
+			oldest = i
+		}
+	}
+	return heap.Remove((*priorityItems)(&h.items), oldest).(*prioritized).value
+}
+
+/**
+ * Removes value, reporting whether it was waiting.
+ */
+func (h *priorityHeap) remove(value interface{}) bool {
+	for i, item := range h.items {
+		if item.value == value {
+			heap.Remove((*priorityItems)(&h.items), i)
+			return true
+		}
+	}
+	return false
+}
+
+func (h *priorityHeap) len() int {
+	return len(h.items)
+}
+
+/**
+ * heap.Interface implementation.
+ */
+type priorityItems []*prioritized
+
+func (p priorityItems) Len() int {
+	return len(p)
+}
+
+func (p priorityItems) Less(i, j int) bool {
+	if p[i].priority != p[j].priority {
+		return p[i].priority > p[j].priority
+	}
+	return p[i].seq < p[j].seq
+}
+
+func (p priorityItems) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+func (p *priorityItems) Push(x interface{}) {
+	*p = append(*p, x.(*prioritized))
+}
+
+func (p *priorityItems) Pop() interface{} {
+	old := *p
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*p = old[:len(old)-1]
+	return item
+}
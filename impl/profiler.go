@@ -0,0 +1,28 @@
+package gorequest
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+/**
+ * Runs fn with the pprof labels of the request, if any. The labels are also
+ * added to the request context, where pprof.Label finds them.
+ */
+func (r *request) labelled(fn func()) {
+
+	if !r.client.profilerLabels && len(r.options.profilerLabels) == 0 {
+		fn()
+		return
+	}
+
+	labels := append([]string{
+		"gorequest.host", r.request.URL.Host,
+		"gorequest.method", r.request.Method,
+	}, r.options.profilerLabels...)
+
+	pprof.Do(r.request.Context(), pprof.Labels(labels...), func(ctx context.Context) {
+		r.request = r.request.WithContext(ctx)
+		fn()
+	})
+}
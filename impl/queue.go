@@ -0,0 +1,266 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"sync"
+	"time"
+)
+
+/****************************************************
+ * model.Queue implementation
+ ****************************************************/
+
+var defaultQueueWorkers int = 4
+var defaultQueueCapacity int = 100
+
+type queue struct {
+	capacity  int
+	closed    bool
+	cond      *sync.Cond
+	idle      int
+	jobs      priorityHeap
+	mutex     sync.Mutex
+	policy    model.OverflowPolicy
+	scheduled map[*scheduledRequest]bool
+	size      int
+	workers   sync.WaitGroup
+}
+
+type queuedRequest struct {
+	handler model.ResultHandler
+	request model.Request
+}
+
+type scheduledRequest struct {
+	handler model.ResultHandler
+	timer   *time.Timer
+}
+
+func newQueue(workers int, capacity int, policy model.OverflowPolicy) *queue {
+
+	q := &queue{
+		capacity:  capacity,
+		policy:    policy,
+		scheduled: make(map[*scheduledRequest]bool),
+		size:      workers,
+	}
+
+	q.cond = sync.NewCond(&q.mutex)
+	q.workers.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+/**
+ * Workers keep running the requests already queued after the queue is
+ * closed, even when Close gives up waiting for them.
+ */
+func (q *queue) Close(ctx context.Context) error {
+
+	q.mutex.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	dropped := q.scheduled
+	q.scheduled = make(map[*scheduledRequest]bool)
+	q.mutex.Unlock()
+
+	for s := range dropped {
+		s.timer.Stop()
+		s.handler(model.Result{Err: model.ErrQueueClosed})
+	}
+
+	drained := make(chan struct{})
+
+	go func() {
+		q.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *queue) Async(req model.Request) (model.Future, error) {
+
+	f := newFuture()
+
+	if err := q.Enqueue(req, f.complete); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+/**
+ * Requests are run by priority (see RequestBuilder.WithPriority), then in
+ * the order they were queued.
+ */
+func (q *queue) Enqueue(req model.Request, handler model.ResultHandler) error {
+
+	var dropped *queuedRequest
+
+	defer func() {
+		// outside the lock, as the handlers called by workers
+		if dropped != nil {
+			dropped.handler(model.Result{Err: model.ErrRequestDropped})
+		}
+	}()
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for !q.closed && q.full() && q.policy == model.OverflowBlock {
+		q.cond.Wait()
+	}
+
+	if q.closed {
+		return model.ErrQueueClosed
+	}
+
+	if q.full() && q.policy == model.OverflowDropOldest && q.jobs.len() > 0 {
+		job := q.jobs.dropOldest().(queuedRequest)
+		dropped = &job
+	}
+
+	if q.full() {
+		return &model.QueueFullError{Limit: q.size, QueueSize: q.capacity}
+	}
+
+	q.jobs.push(priorityOf(req), queuedRequest{handler: handler, request: req})
+	q.cond.Broadcast()
+
+	return nil
+}
+
+func (q *queue) EnqueueAfter(d time.Duration, req model.Request, handler model.ResultHandler) (model.CancelFunc, error) {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return nil, model.ErrQueueClosed
+	}
+
+	s := &scheduledRequest{handler: handler}
+	q.scheduled[s] = true
+
+	s.timer = time.AfterFunc(d, func() {
+		if !q.unschedule(s) {
+			return
+		}
+		if err := q.Enqueue(req, handler); err != nil {
+			handler(model.Result{Err: err})
+		}
+	})
+
+	return func() bool {
+		if !q.unschedule(s) {
+			return false
+		}
+		s.timer.Stop()
+		return true
+	}, nil
+}
+
+func (q *queue) EnqueueAt(t time.Time, req model.Request, handler model.ResultHandler) (model.CancelFunc, error) {
+	return q.EnqueueAfter(time.Until(t), req, handler)
+}
+
+/**
+ * Removes a scheduled request, reporting whether it was still pending.
+ */
+func (q *queue) unschedule(s *scheduledRequest) bool {
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !q.scheduled[s] {
+		return false
+	}
+
+	delete(q.scheduled, s)
+
+	return true
+}
+
+/**
+ * Idle workers take requests beyond the capacity right away.
+ */
+func (q *queue) full() bool {
+	return q.jobs.len() >= q.capacity+q.idle
+}
+
+func (q *queue) Submit(req model.Request) (<-chan model.Result, error) {
+
+	results := make(chan model.Result, 1)
+
+	err := q.Enqueue(req, func(result model.Result) {
+		results <- result
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (q *queue) work() {
+
+	defer q.workers.Done()
+
+	q.mutex.Lock()
+
+	for {
+		for q.jobs.len() == 0 && !q.closed {
+			q.idle++
+			q.cond.Wait()
+			q.idle--
+		}
+
+		if q.jobs.len() == 0 {
+			q.mutex.Unlock()
+			return
+		}
+
+		job := q.jobs.pop().(queuedRequest)
+		q.cond.Broadcast()
+
+		q.mutex.Unlock()
+		job.handler(run(job.request))
+		q.mutex.Lock()
+	}
+}
+
+func priorityOf(req model.Request) model.Priority {
+	if r, ok := req.(*request); ok {
+		return r.options.priority
+	}
+	return model.PriorityNormal
+}
+
+/**
+ * Runs req, turning its error, or a panic raised by hooks, fallbacks and
+ * other caller code, into its result.
+ */
+func run(req model.Request) (result model.Result) {
+
+	defer func() {
+		if p := recover(); p != nil {
+			result = model.Result{Err: panicError(p)}
+		}
+	}()
+
+	resp, err := req.Send()
+
+	return model.Result{Err: err, Response: resp}
+}
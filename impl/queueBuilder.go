@@ -0,0 +1,54 @@
+package gorequest
+
+import (
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+)
+
+/****************************************************
+ * model.QueueBuilder implementation
+ ****************************************************/
+
+type queueBuilder struct {
+	capacity int
+	policy   model.OverflowPolicy
+	workers  int
+}
+
+func (b *queueBuilder) Build() model.Queue {
+
+	if b.workers <= 0 {
+		panic(errors.New("Queue needs at least one worker"))
+	}
+
+	if b.capacity < 0 {
+		panic(errors.New("Queue capacity cannot be negative"))
+	}
+
+	return newQueue(b.workers, b.capacity, b.policy)
+}
+
+/**
+ * Sets how many requests may wait for a worker.
+ */
+func (b *queueBuilder) WithCapacity(capacity int) model.QueueBuilder {
+	b.capacity = capacity
+	return b
+}
+
+/**
+ * Sets whether Enqueue waits for room in a full queue, fails with a
+ * *model.QueueFullError or drops the oldest queued request.
+ */
+func (b *queueBuilder) WithOverflowPolicy(policy model.OverflowPolicy) model.QueueBuilder {
+	b.policy = policy
+	return b
+}
+
+/**
+ * Sets how many requests run at once.
+ */
+func (b *queueBuilder) WithWorkers(workers int) model.QueueBuilder {
+	b.workers = workers
+	return b
+}
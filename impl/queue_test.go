@@ -0,0 +1,306 @@
+package gorequest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueRunsRequests(t *testing.T) {
+	var inFlight, peak int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		fmt.Fprint(resp, req.URL.Query().Get("id"))
+	}))
+
+	defer ts.Close()
+
+	q := NewQueueBuilder().WithWorkers(2).Build()
+
+	var mutex sync.Mutex
+	received := map[string]bool{}
+
+	for i := 0; i < 6; i++ {
+		err := q.Enqueue(NewRequestBuilder().WithUrl(fmt.Sprintf("%s?id=%d", ts.URL, i)).Build(), func(result model.Result) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			received[string(result.Response.Body())] = true
+		})
+		assert.Nil(t, err, "Should enqueue the request")
+	}
+
+	assert.Nil(t, q.Close(context.Background()), "Should drain the queue")
+	assert.Equal(t, 6, len(received), "Should deliver every result")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak), "Should run two requests at once")
+
+	assert.Equal(t, model.ErrQueueClosed, q.Enqueue(NewRequestBuilder().WithUrl(ts.URL).Build(), nil), "Should reject requests once closed")
+}
+
+func TestQueueSubmitDeliversErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	q := NewQueueBuilder().Build()
+	defer q.Close(context.Background())
+
+	results, err := q.Submit(NewRequestBuilder().WithUrl(url).Build())
+
+	assert.Nil(t, err, "Should enqueue the request")
+
+	result := <-results
+
+	assert.Nil(t, result.Response, "Should not have a response")
+	assert.NotNil(t, result.Err, "Should deliver the error of the request")
+}
+
+func TestQueueRejectsOverflow(t *testing.T) {
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+
+	defer ts.Close()
+
+	q := NewQueueBuilder().WithWorkers(1).WithCapacity(1).WithOverflowPolicy(model.OverflowReject).Build()
+
+	started := make(chan struct{})
+	q.Enqueue(NewRequestBuilder().WithUrl(ts.URL).WithClientTrace(&httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) { close(started) },
+	}).Build(), func(model.Result) {})
+	<-started
+
+	assert.Nil(t, q.Enqueue(NewRequestBuilder().WithUrl(ts.URL).Build(), func(model.Result) {}), "Should queue one request")
+
+	err := q.Enqueue(NewRequestBuilder().WithUrl(ts.URL).Build(), func(model.Result) {})
+
+	assert.Equal(t, "Request queue is full (1 in flight, 1 queued)", err.Error(), "Should equal error message")
+
+	close(release)
+	q.Close(context.Background())
+}
+
+func TestQueueDropsOldest(t *testing.T) {
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("id") == "blocker" {
+			<-release
+		}
+		fmt.Fprint(resp, req.URL.Query().Get("id"))
+	}))
+
+	defer ts.Close()
+
+	q := NewQueueBuilder().WithWorkers(1).WithCapacity(2).WithOverflowPolicy(model.OverflowDropOldest).Build()
+
+	started := make(chan struct{})
+	q.Enqueue(NewRequestBuilder().WithUrl(ts.URL+"?id=blocker").WithClientTrace(&httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) { close(started) },
+	}).Build(), func(model.Result) {})
+	<-started
+
+	var futures []model.Future
+
+	for _, id := range []string{"a", "b", "c"} {
+		f, err := q.Async(NewRequestBuilder().WithUrl(ts.URL + "?id=" + id).Build())
+		assert.Nil(t, err, "Should make room for newer requests")
+		futures = append(futures, f)
+	}
+
+	close(release)
+
+	assert.Equal(t, model.ErrRequestDropped, futures[0].Result().Err, "Should drop the oldest request")
+	assert.Equal(t, "b", string(futures[1].Result().Response.Body()), "Should run the others")
+	assert.Equal(t, "c", string(futures[2].Result().Response.Body()), "Should run the others")
+
+	q.Close(context.Background())
+}
+
+func TestQueuePriority(t *testing.T) {
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("id") == "blocker" {
+			<-release
+		}
+		fmt.Fprint(resp, req.URL.Query().Get("id"))
+	}))
+
+	defer ts.Close()
+
+	q := NewQueueBuilder().WithWorkers(1).Build()
+
+	started := make(chan struct{})
+	q.Enqueue(NewRequestBuilder().WithUrl(ts.URL+"?id=blocker").WithClientTrace(&httptrace.ClientTrace{
+		WroteRequest: func(httptrace.WroteRequestInfo) { close(started) },
+	}).Build(), func(model.Result) {})
+	<-started
+
+	var order []string
+
+	for _, priority := range []model.Priority{model.PriorityLow, model.PriorityNormal, model.PriorityHigh} {
+		q.Enqueue(NewRequestBuilder().WithUrl(fmt.Sprintf("%s?id=%d", ts.URL, priority)).WithPriority(priority).Build(), func(result model.Result) {
+			order = append(order, string(result.Response.Body()))
+		})
+	}
+
+	close(release)
+	q.Close(context.Background())
+
+	assert.Equal(t, []string{"10", "0", "-10"}, order, "Should run higher priorities first")
+}
+
+func TestQueueEnqueueAfter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "later")
+	}))
+
+	defer ts.Close()
+
+	q := NewQueueBuilder().Build()
+	defer q.Close(context.Background())
+
+	results := make(chan model.Result, 1)
+	start := time.Now()
+
+	_, err := q.EnqueueAfter(30*time.Millisecond, NewRequestBuilder().WithUrl(ts.URL).Build(), func(result model.Result) {
+		results <- result
+	})
+
+	assert.Nil(t, err, "Should schedule the request")
+
+	result := <-results
+
+	assert.Equal(t, "later", string(result.Response.Body()), "Should run the request")
+	assert.True(t, time.Since(start) >= 30*time.Millisecond, "Should wait for the delay")
+}
+
+func TestQueueCancelScheduled(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+
+	defer ts.Close()
+
+	q := NewQueueBuilder().Build()
+
+	cancel, _ := q.EnqueueAt(time.Now().Add(20*time.Millisecond), NewRequestBuilder().WithUrl(ts.URL).Build(), func(model.Result) {})
+
+	assert.True(t, cancel(), "Should cancel the pending request")
+	assert.False(t, cancel(), "Should report it was already cancelled")
+
+	var dropped error
+	q.EnqueueAfter(time.Hour, NewRequestBuilder().WithUrl(ts.URL).Build(), func(result model.Result) {
+		dropped = result.Err
+	})
+
+	time.Sleep(40 * time.Millisecond)
+	q.Close(context.Background())
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls), "Should not run cancelled requests")
+	assert.Equal(t, model.ErrQueueClosed, dropped, "Should drop pending requests on close")
+}
+
+func TestAll(t *testing.T) {
+	var inFlight, peak int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			previous := atomic.LoadInt32(&peak)
+			if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if req.URL.Path == "/fail" {
+			resp.WriteHeader(http.StatusInternalServerError)
+		}
+		fmt.Fprint(resp, req.URL.Path)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithRetry(model.Retry{MaxAttempts: 1}).Build()
+
+	var requests []model.Request
+
+	for i := 0; i < 10; i++ {
+		requests = append(requests, NewRequestBuilder().WithUrl(fmt.Sprintf("%s/%d", ts.URL, i)).WithClient(c).Build())
+	}
+
+	requests = append(requests, NewRequestBuilder().WithUrl("http://127.0.0.1:1/").WithClient(c).Build())
+
+	results := All(context.Background(), requests, 3)
+
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, results[i].Err, "Should succeed")
+		assert.Equal(t, fmt.Sprintf("/%d", i), string(results[i].Response.Body()), "Should return results in order")
+	}
+
+	assert.NotNil(t, results[10].Err, "Should report errors per request")
+	assert.True(t, atomic.LoadInt32(&peak) <= 3, "Should bound the parallelism")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results = All(ctx, requests[:2], 1)
+
+	assert.Equal(t, []model.Result{{Err: context.Canceled}, {Err: context.Canceled}}, results, "Should not start requests once the context is done")
+}
+
+func TestFutures(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		fmt.Fprint(resp, req.URL.Path)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithRetry(model.Retry{MaxAttempts: 1}).Build()
+
+	request := func(url string) model.Request {
+		return NewRequestBuilder().WithUrl(url).WithClient(c).Build()
+	}
+
+	fast := Async(request(ts.URL + "/fast"))
+
+	<-fast.Done()
+
+	assert.Equal(t, "/fast", string(fast.Result().Response.Body()), "Should deliver the response")
+
+	first := First(Async(request("http://127.0.0.1:1/")), Async(request(ts.URL+"/slow")), Async(request(ts.URL+"/fast")))
+
+	assert.Equal(t, "/fast", string(first.Result().Response.Body()), "Should complete with the first success")
+	assert.NotNil(t, First(Async(request("http://127.0.0.1:1/"))).Result().Err, "Should fail when every future fails")
+
+	results := AllSettled(Async(request(ts.URL+"/slow")), Async(request("http://127.0.0.1:1/")))
+
+	assert.Equal(t, "/slow", string(results[0].Response.Body()), "Should wait for every future")
+	assert.NotNil(t, results[1].Err, "Should include failures")
+}
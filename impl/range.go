@@ -0,0 +1,107 @@
+package gorequest
+
+import (
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+/**
+ * Returns the Range header value asking for length bytes from offset: to
+ * the end of the resource if length is not positive, and for its last
+ * -offset bytes if offset is negative.
+ */
+func byteRange(offset int64, length int64) string {
+	switch {
+	case offset < 0:
+		return "bytes=" + strconv.FormatInt(offset, 10)
+	case length <= 0:
+		return "bytes=" + strconv.FormatInt(offset, 10) + "-"
+	default:
+		return "bytes=" + strconv.FormatInt(offset, 10) + "-" + strconv.FormatInt(offset+length-1, 10)
+	}
+}
+
+/**
+ * Checks that resp is a 206 Partial Content response to a single range
+ * request and returns the range it holds. Servers ignoring Range answer
+ * 200 with the whole resource, which is reported as an error rather than
+ * mistaken for the part; so are 416 responses, with the size of the
+ * resource, and bodies whose length does not match the range.
+ */
+func ContentRange(resp model.Response) (model.ContentRange, error) {
+
+	header := resp.Response().Header.Get("Content-Range")
+
+	switch status := resp.Response().StatusCode; status {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		return model.ContentRange{}, fmt.Errorf("Range not satisfiable: %s", header)
+	default:
+		return model.ContentRange{}, fmt.Errorf("Expected a partial content response, got status %d", status)
+	}
+
+	if header == "" {
+		if strings.HasPrefix(resp.Response().Header.Get("Content-Type"), "multipart/byteranges") {
+			return model.ContentRange{}, fmt.Errorf("Multiple ranges are not supported")
+		}
+		return model.ContentRange{}, fmt.Errorf("Partial content response without Content-Range")
+	}
+
+	part, err := parseContentRange(header)
+
+	if err != nil {
+		return model.ContentRange{}, err
+	}
+
+	if r, ok := resp.(*response); ok && !r.streamed && int64(len(r.body)) != part.Length() {
+		return part, fmt.Errorf("Received %d bytes for range %s", len(resp.Body()), header)
+	}
+
+	return part, nil
+}
+
+/**
+ * Parses a Content-Range value such as "bytes 0-499/1234" or
+ * "bytes 0-499/*".
+ */
+func parseContentRange(header string) (model.ContentRange, error) {
+
+	invalid := fmt.Errorf("Invalid Content-Range: %s", header)
+
+	if !strings.HasPrefix(header, "bytes ") {
+		return model.ContentRange{}, invalid
+	}
+
+	spec := strings.SplitN(strings.TrimPrefix(header, "bytes "), "/", 2)
+
+	if len(spec) != 2 {
+		return model.ContentRange{}, invalid
+	}
+
+	span := strings.SplitN(spec[0], "-", 2)
+
+	if len(span) != 2 {
+		return model.ContentRange{}, invalid
+	}
+
+	first, last, size := span[0], span[1], spec[1]
+
+	part := model.ContentRange{Size: -1}
+	var errFirst, errLast, errSize error
+
+	part.First, errFirst = strconv.ParseInt(first, 10, 64)
+	part.Last, errLast = strconv.ParseInt(last, 10, 64)
+
+	if size != "*" {
+		part.Size, errSize = strconv.ParseInt(size, 10, 64)
+	}
+
+	if errFirst != nil || errLast != nil || errSize != nil || part.First < 0 || part.Last < part.First || part.Size >= 0 && part.Last >= part.Size {
+		return model.ContentRange{}, invalid
+	}
+
+	return part, nil
+}
@@ -0,0 +1,52 @@
+package gorequest
+
+import (
+	"net/http"
+	"testing"
+
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRange(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/video.mp4").MatchHeader("Range", "bytes=100-104").ReplyHeader("Content-Range", "bytes 100-104/1000").Reply(http.StatusPartialContent, "01234")
+	mock.On("GET", "/video.mp4").MatchHeader("Range", "bytes=995-").ReplyHeader("Content-Range", "bytes 995-999/*").Reply(http.StatusPartialContent, "0123")
+	mock.On("GET", "/video.mp4").MatchHeader("Range", "bytes=-5").ReplyHeader("Content-Range", "bytes 995-999/1000").Reply(http.StatusPartialContent, "01234")
+	mock.On("GET", "/video.mp4").MatchHeader("Range", "bytes=2000-").ReplyHeader("Content-Range", "bytes */1000").Reply(http.StatusRequestedRangeNotSatisfiable, "")
+	mock.On("GET", "/video.mp4").Reply(http.StatusOK, "whole")
+
+	c := NewClient(WithTransport(mock))
+	url := WithUrl("https://media.example.com/video.mp4")
+
+	part, err := ContentRange(NewRequest(WithClient(c), url, WithRange(100, 5)).Do())
+
+	assert.Nil(t, err, "Should accept the part asked for")
+	assert.Equal(t, int64(100), part.First, "Should read the first byte")
+	assert.Equal(t, int64(104), part.Last, "Should read the last byte")
+	assert.Equal(t, int64(1000), part.Size, "Should read the size")
+	assert.Equal(t, int64(5), part.Length(), "Should count the bytes of the part")
+
+	_, err = ContentRange(NewRequest(WithClient(c), url, WithRange(995, 0)).Do())
+
+	assert.EqualError(t, err, "Received 4 bytes for range bytes 995-999/*", "Should check the length of the body")
+
+	part, err = ContentRange(NewRequest(WithClient(c), url, WithRange(-5, 0)).Do())
+
+	assert.Nil(t, err, "Should ask for suffixes")
+	assert.Equal(t, int64(995), part.First, "Should read suffix ranges")
+
+	_, err = ContentRange(NewRequest(WithClient(c), url, WithRange(2000, 0)).Do())
+
+	assert.EqualError(t, err, "Range not satisfiable: bytes */1000", "Should report unsatisfiable ranges")
+
+	_, err = ContentRange(NewRequest(WithClient(c), url, WithRangeHeader("bytes=0-1,4-5")).Do())
+
+	assert.EqualError(t, err, "Expected a partial content response, got status 200", "Should not mistake whole resources for parts")
+
+	for _, header := range []string{"0-4/10", "bytes 4-0/10", "bytes 0-10/10", "bytes a-4/10", "bytes 0-4"} {
+		_, err := parseContentRange(header)
+		assert.EqualError(t, err, "Invalid Content-Range: "+header, "Should reject %q", header)
+	}
+}
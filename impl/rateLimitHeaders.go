@@ -0,0 +1,207 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**
+ * Reads the quota announced by header: the RateLimit and RateLimit-Policy
+ * fields of the IETF draft (r, t and q parameters of their first item),
+ * its earlier RateLimit-Limit/-Remaining/-Reset form, or the
+ * X-RateLimit-Limit/-Remaining/-Reset headers, in that order of
+ * preference. Resets given as epoch seconds are counted from now.
+ */
+func parseRateLimit(header http.Header, now time.Time) (model.RateLimit, bool) {
+
+	limit := model.RateLimit{Limit: -1, Remaining: -1, Reset: -1}
+
+	if fields := firstItemParams(header.Get("RateLimit")); fields != nil {
+		limit.Remaining = parseCount(fields["r"])
+		limit.Reset = parseReset(fields["t"], now)
+		limit.Limit = parseCount(firstItemParams(header.Get("RateLimit-Policy"))["q"])
+		return limit, limit.Remaining >= 0
+	}
+
+	for _, prefix := range []string{"RateLimit-", "X-RateLimit-"} {
+		limit.Limit = parseCount(header.Get(prefix + "Limit"))
+		limit.Remaining = parseCount(header.Get(prefix + "Remaining"))
+		limit.Reset = parseReset(header.Get(prefix+"Reset"), now)
+		if limit.Limit >= 0 || limit.Remaining >= 0 {
+			return limit, true
+		}
+	}
+
+	return limit, false
+}
+
+/**
+ * Returns the parameters of the first item of a structured field list such
+ * as `"default";r=50;t=30`; nil if there is none.
+ */
+func firstItemParams(field string) map[string]string {
+
+	if strings.TrimSpace(field) == "" {
+		return nil
+	}
+
+	item := strings.SplitN(field, ",", 2)[0]
+	params := map[string]string{}
+
+	for _, param := range strings.Split(item, ";")[1:] {
+		pair := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(pair) == 2 {
+			params[pair[0]] = pair[1]
+		}
+	}
+
+	return params
+}
+
+func parseCount(value string) int64 {
+	count, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || count < 0 {
+		return -1
+	}
+	return count
+}
+
+func parseReset(value string, now time.Time) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || seconds < 0 {
+		return -1
+	}
+	if seconds >= epochThreshold {
+		return nonNegative(time.Unix(0, int64(seconds*float64(time.Second))).Sub(now))
+	}
+	return secondsDuration(seconds)
+}
+
+/****************************************************
+ * Pacing of requests by announced quotas
+ ****************************************************/
+
+/**
+ * Spaces requests to hosts whose announced quota runs low, so the quota
+ * lasts until the window resets instead of running out with 429s.
+ */
+type quotaPacer struct {
+	clock   model.Clock
+	hosts   map[string]*hostQuota
+	maxWait time.Duration
+	mutex   sync.Mutex
+	reserve int64
+}
+
+/**
+ * Configuration of a quotaPacer, as given to the client builder.
+ */
+type quotaPacing struct {
+	maxWait time.Duration
+	reserve int64
+}
+
+type hostQuota struct {
+	// Time of the next paced request.
+	next      time.Time
+	remaining int64
+	resetAt   time.Time
+}
+
+func newQuotaPacer(clock model.Clock, pacing quotaPacing) *quotaPacer {
+	return &quotaPacer{
+		clock:   clock,
+		hosts:   make(map[string]*hostQuota),
+		maxWait: pacing.maxWait,
+		reserve: pacing.reserve,
+	}
+}
+
+/**
+ * Records the quota announced by a response of host.
+ */
+func (p *quotaPacer) observe(host string, resp *http.Response) {
+
+	if resp == nil {
+		return
+	}
+
+	now := p.clock.Now()
+	limit, ok := parseRateLimit(resp.Header, now)
+
+	if !ok || limit.Remaining < 0 || limit.Reset < 0 {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	quota, ok := p.hosts[host]
+
+	if !ok {
+		quota = &hostQuota{}
+		p.hosts[host] = quota
+	}
+
+	quota.remaining = limit.Remaining
+	quota.resetAt = now.Add(limit.Reset)
+}
+
+/**
+ * Waits for the turn of a request to host: right away while the quota is
+ * above the reserve, at evenly spaced times once it is not, at the reset
+ * once it is spent. Fails with a *model.QuotaExhaustedError, without
+ * taking a turn, when it is further than the maximum wait, or with the
+ * error of ctx if it ends first.
+ */
+func (p *quotaPacer) wait(ctx context.Context, host string) error {
+
+	now := p.clock.Now()
+
+	p.mutex.Lock()
+
+	quota, ok := p.hosts[host]
+
+	if !ok || quota.remaining > p.reserve || !now.Before(quota.resetAt) {
+		p.mutex.Unlock()
+		return nil
+	}
+
+	turn := now
+
+	if quota.next.After(turn) {
+		turn = quota.next
+	}
+
+	if quota.remaining <= 0 {
+		turn = quota.resetAt
+	}
+
+	if wait := turn.Sub(now); p.maxWait >= 0 && wait > p.maxWait {
+		p.mutex.Unlock()
+		return &model.QuotaExhaustedError{Host: host, Wait: wait}
+	}
+
+	if quota.remaining > 0 {
+		quota.next = turn.Add(quota.resetAt.Sub(now) / time.Duration(quota.remaining+1))
+		quota.remaining--
+	}
+
+	p.mutex.Unlock()
+
+	if !turn.After(now) {
+		return nil
+	}
+
+	select {
+	case <-p.clock.After(turn.Sub(now)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,126 @@
+package gorequest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitHeaders(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	headers := func(pairs ...string) http.Header {
+		header := http.Header{}
+		for i := 0; i < len(pairs); i += 2 {
+			header.Set(pairs[i], pairs[i+1])
+		}
+		return header
+	}
+
+	limit, ok := parseRateLimit(headers("RateLimit", `"default";r=50;t=30`, "RateLimit-Policy", `"default";q=100;w=60`), now)
+
+	assert.True(t, ok, "Should read the IETF fields")
+	assert.Equal(t, model.RateLimit{Limit: 100, Remaining: 50, Reset: 30 * time.Second}, limit, "Should read the first item of the fields")
+
+	limit, ok = parseRateLimit(headers("RateLimit-Limit", "10", "RateLimit-Remaining", "0", "RateLimit-Reset", "5"), now)
+
+	assert.True(t, ok, "Should read the earlier IETF fields")
+	assert.Equal(t, model.RateLimit{Limit: 10, Remaining: 0, Reset: 5 * time.Second}, limit, "Should read the earlier IETF fields")
+
+	limit, ok = parseRateLimit(headers("X-RateLimit-Limit", "5000", "X-RateLimit-Remaining", "4999", "X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Minute).Unix(), 10)), now)
+
+	assert.True(t, ok, "Should read the X-RateLimit headers")
+	assert.Equal(t, model.RateLimit{Limit: 5000, Remaining: 4999, Reset: time.Minute}, limit, "Should count epoch resets from now")
+
+	limit, ok = parseRateLimit(headers("X-RateLimit-Remaining", "many"), now)
+
+	assert.False(t, ok, "Should report responses without a quota")
+	assert.Equal(t, model.RateLimit{Limit: -1, Remaining: -1, Reset: -1}, limit, "Should mark missing values")
+}
+
+func TestResponseRateLimitFollowsClock(t *testing.T) {
+	clock := requestmock.NewClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	mock := requestmock.New()
+	mock.On("GET", "/quota").ReplyHeader("X-RateLimit-Reset", strconv.FormatInt(clock.Now().Add(time.Minute).Unix(), 10))
+
+	c := NewClientBuilder().WithTransport(mock).WithClock(clock).Build()
+
+	limit, _ := NewRequestBuilder().WithUrl("https://api.example.com/quota").WithClient(c).Build().Do().RateLimit()
+
+	assert.Equal(t, time.Minute, limit.Reset, "Should count epoch resets from the client clock")
+}
+
+func TestRateLimitPacing(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("GET", "/repos").Times(4).ReplyHeader("X-RateLimit-Limit", "60").ReplyHeader("X-RateLimit-Remaining", "2").ReplyHeader("X-RateLimit-Reset", "30").Reply(http.StatusOK, "")
+	mock.On("GET", "/repos").Times(1).ReplyHeader("X-RateLimit-Remaining", "0").ReplyHeader("X-RateLimit-Reset", "45").Reply(http.StatusOK, "")
+	mock.On("GET", "/repos").ReplyHeader("X-RateLimit-Remaining", "59").ReplyHeader("X-RateLimit-Reset", "60").Reply(http.StatusOK, "")
+
+	start := time.Now()
+	clock := requestmock.NewClock(start).AutoAdvance()
+	c := NewClientBuilder().WithTransport(mock).WithClock(clock).WithRateLimitPacing(5, 0).Build()
+
+	send := func() model.Response {
+		return NewRequestBuilder().WithClient(c).WithUrl("https://api.example.com/repos").Build().Do()
+	}
+
+	resp := send()
+
+	limit, ok := resp.RateLimit()
+
+	assert.True(t, ok, "Should expose the quota of responses")
+	assert.Equal(t, int64(2), limit.Remaining, "Should read the remaining quota")
+
+	send()
+	assert.Equal(t, time.Duration(0), clock.Now().Sub(start), "Should not delay the first paced request")
+
+	send()
+	assert.Equal(t, 10*time.Second, clock.Now().Sub(start), "Should spread the quota left over the window")
+
+	send()
+	assert.Equal(t, 20*time.Second, clock.Now().Sub(start), "Should keep spacing requests")
+
+	send()
+	assert.Equal(t, 30*time.Second, clock.Now().Sub(start), "Should keep spacing requests")
+
+	send()
+	assert.Equal(t, 75*time.Second, clock.Now().Sub(start), "Should hold requests until the reset once the quota is spent")
+
+	send()
+	assert.Equal(t, 75*time.Second, clock.Now().Sub(start), "Should stop pacing once the quota is back above the reserve")
+}
+
+func TestRateLimitPacingMaxWait(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("GET", "/repos").ReplyHeader("X-RateLimit-Remaining", "0").ReplyHeader("X-RateLimit-Reset", "120").Reply(http.StatusOK, "")
+
+	start := time.Now()
+	clock := requestmock.NewClock(start).AutoAdvance()
+	c := NewClientBuilder().WithTransport(mock).WithClock(clock).WithRateLimitPacing(5, 0).Build()
+
+	_, err := NewRequestBuilder().WithClient(c).WithUrl("https://api.example.com/repos").Build().Send()
+
+	assert.Nil(t, err, "Should send the request reporting the quota")
+
+	_, err = NewRequestBuilder().WithClient(c).WithUrl("https://api.example.com/repos").Build().Send()
+
+	var exhausted *model.QuotaExhaustedError
+
+	assert.True(t, errors.As(err, &exhausted), "Should fail requests held past the maximum wait, got %v", err)
+	assert.Equal(t, 2*time.Minute, exhausted.Wait, "Should report the wait")
+	assert.Equal(t, time.Duration(0), clock.Now().Sub(start), "Should fail without waiting")
+	assert.Len(t, mock.Calls(), 1, "Should not send the request")
+
+	c = NewClientBuilder().WithTransport(mock).WithClock(clock).WithRateLimitPacing(5, -1).Build()
+
+	NewRequestBuilder().WithClient(c).WithUrl("https://api.example.com/repos").Build().Do()
+	NewRequestBuilder().WithClient(c).WithUrl("https://api.example.com/repos").Build().Do()
+
+	assert.Equal(t, 2*time.Minute, clock.Now().Sub(start), "Should wait for the reset without a maximum")
+}
@@ -0,0 +1,57 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"strings"
+)
+
+/**
+ * Per-host request rate limits, each backed by its own token bucket. Limits
+ * are only configured while the client is built, so lookups need no locking.
+ */
+type rateLimiter struct {
+	clock model.Clock
+	hosts map[string]*hostRate
+}
+
+type hostRate struct {
+	bucket *tokenBucket
+	policy model.OverflowPolicy
+	rate   float64
+}
+
+func newRateLimiter(clock model.Clock) *rateLimiter {
+	return &rateLimiter{clock: clock, hosts: make(map[string]*hostRate)}
+}
+
+func (l *rateLimiter) limit(host string, rate float64, burst int, policy model.OverflowPolicy) {
+	l.hosts[strings.ToLower(host)] = &hostRate{
+		bucket: newTokenBucket(l.clock, rate, burst),
+		policy: policy,
+		rate:   rate,
+	}
+}
+
+/**
+ * Waits until a request to host is allowed. Fails with a
+ * *model.RateLimitError when the limit is exceeded and the policy rejects,
+ * or with the error of ctx if it is done while waiting.
+ */
+func (l *rateLimiter) wait(ctx context.Context, host string) error {
+
+	limit, ok := l.hosts[strings.ToLower(host)]
+
+	if !ok {
+		return nil
+	}
+
+	if limit.policy == model.OverflowReject {
+		if !limit.bucket.take(1) {
+			return &model.RateLimitError{Host: host, Rate: limit.rate}
+		}
+		return nil
+	}
+
+	return limit.bucket.wait(ctx, 1)
+}
@@ -0,0 +1,84 @@
+package gorequest
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/**
+ * Headers that carry credentials and therefore never follow a redirect to
+ * a different origin unless explicitly preserved.
+ */
+var sensitiveRedirectHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Cookie2",
+	"Proxy-Authorization",
+	"WWW-Authenticate",
+}
+
+/**
+ * Rewrites the headers of a redirect request, which net/http has already
+ * copied from the original request, according to the given rules.
+ */
+func applyRedirectHeaders(rules *model.RedirectHeaders, req *http.Request, via []*http.Request) {
+
+	if rules == nil {
+		rules = &model.RedirectHeaders{}
+	}
+
+	original := via[0]
+
+	for _, name := range rules.Drop {
+		req.Header.Del(name)
+	}
+
+	preserved := make(map[string]bool)
+	for _, name := range rules.Preserve {
+		preserved[http.CanonicalHeaderKey(name)] = true
+	}
+
+	if !sameOrigin(original.URL, req.URL) {
+		for _, name := range append(sensitiveRedirectHeaders, rules.StripCrossOrigin...) {
+			if !preserved[http.CanonicalHeaderKey(name)] {
+				req.Header.Del(name)
+			}
+		}
+	}
+
+	for name := range preserved {
+		if values := original.Header.Values(name); len(values) > 0 && !containsHeader(rules.Drop, name) {
+			req.Header[name] = append([]string(nil), values...)
+		}
+	}
+}
+
+func containsHeader(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(hostPort(a), hostPort(b))
+}
+
+/**
+ * Returns host:port, filling in the default port of the scheme.
+ */
+func hostPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return u.Hostname() + ":" + port
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+		return u.Hostname() + ":443"
+	default:
+		return u.Hostname() + ":80"
+	}
+}
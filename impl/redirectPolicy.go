@@ -0,0 +1,147 @@
+package gorequest
+
+import (
+	"context"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/****************************************************
+ * model.RedirectPolicy implementations
+ ****************************************************/
+
+type redirectNone struct {
+}
+
+/**
+ * Never follows redirects; the 3xx response is returned as is.
+ */
+func NewNoRedirectPolicy() model.RedirectPolicy {
+	return &redirectNone{}
+}
+
+func (p *redirectNone) CheckRedirect(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+type redirectMax struct {
+	hops     int
+	sameHost bool
+}
+
+/**
+ * Follows at most hops redirects.
+ */
+func NewMaxRedirectPolicy(hops int) model.RedirectPolicy {
+	return &redirectMax{
+		hops: hops,
+	}
+}
+
+/**
+ * Follows at most hops redirects, and only those that stay on the host of
+ * the original request.
+ */
+func NewSameHostRedirectPolicy(hops int) model.RedirectPolicy {
+	return &redirectMax{
+		hops:     hops,
+		sameHost: true,
+	}
+}
+
+func (p *redirectMax) CheckRedirect(req *http.Request, via []*http.Request) error {
+
+	if len(via) > p.hops {
+		return &model.RedirectError{
+			Location: req.URL,
+			Reason:   fmt.Sprintf("stopped after %d redirects", p.hops),
+		}
+	}
+
+	if p.sameHost && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		return &model.RedirectError{
+			Location: req.URL,
+			Reason:   fmt.Sprintf("host differs from %s", via[0].URL.Host),
+		}
+	}
+
+	return nil
+}
+
+/****************************************************
+ * Redirect bookkeeping
+ ****************************************************/
+
+/**
+ * Go's own default: follow up to 10 redirects.
+ */
+var defaultRedirectPolicy model.RedirectPolicy = NewMaxRedirectPolicy(10)
+
+type redirectContextKey struct{}
+
+/**
+ * Travels in the request context so that the shared http.Client can apply
+ * the policy of the request being executed and record the hops it made.
+ */
+type redirectState struct {
+	chain   []*url.URL
+	headers *model.RedirectHeaders
+	policy  model.RedirectPolicy
+}
+
+func withRedirectState(req *http.Request, policy model.RedirectPolicy, headers *model.RedirectHeaders) (*http.Request, *redirectState) {
+
+	state := &redirectState{
+		chain:   []*url.URL{req.URL},
+		headers: headers,
+		policy:  policy,
+	}
+
+	return req.WithContext(context.WithValue(req.Context(), redirectContextKey{}, state)), state
+}
+
+/**
+ * Installed as CheckRedirect on every http.Client created by this package.
+ */
+func checkRedirect(req *http.Request, via []*http.Request) error {
+
+	state, _ := req.Context().Value(redirectContextKey{}).(*redirectState)
+
+	if state == nil {
+		applyRedirectHeaders(nil, req, via)
+		return defaultRedirectPolicy.CheckRedirect(req, via)
+	}
+
+	policy := state.policy
+	if policy == nil {
+		policy = defaultRedirectPolicy
+	}
+
+	if err := policy.CheckRedirect(req, via); err != nil {
+		return err
+	}
+
+	applyRedirectHeaders(state.headers, req, via)
+	state.chain = append(state.chain, req.URL)
+
+	return nil
+}
+
+/**
+ * Returns a CheckRedirect running checkRedirect, then next if it allows the
+ * redirect.
+ */
+func chainCheckRedirect(next func(req *http.Request, via []*http.Request) error) func(req *http.Request, via []*http.Request) error {
+	if next == nil {
+		return checkRedirect
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		if err := checkRedirect(req, via); err != nil {
+			return err
+		}
+		return next(req, via)
+	}
+}
@@ -0,0 +1,126 @@
+package gorequest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRedirectServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(resp http.ResponseWriter, req *http.Request) {
+		http.Redirect(resp, req, "/b", http.StatusFound)
+	})
+	mux.HandleFunc("/b", func(resp http.ResponseWriter, req *http.Request) {
+		http.Redirect(resp, req, "/c", http.StatusFound)
+	})
+	mux.HandleFunc("/c", func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "done")
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRedirectChain(t *testing.T) {
+	ts := newRedirectServer()
+
+	defer ts.Close()
+
+	response := NewRequestBuilder().WithUrl(ts.URL + "/a").Build().Do()
+
+	chain := response.RedirectChain()
+
+	assert.Equal(t, "done", string(response.Body()), "Should equal body")
+	assert.True(t, len(chain) == 3, "Should have three URLs")
+	assert.Equal(t, "/a", chain[0].Path, "Should start with the original URL")
+	assert.Equal(t, "/c", chain[2].Path, "Should end with the final URL")
+}
+
+func TestNoRedirectPolicy(t *testing.T) {
+	ts := newRedirectServer()
+
+	defer ts.Close()
+
+	response := NewRequestBuilder().WithUrl(ts.URL + "/a").WithRedirectPolicy(NewNoRedirectPolicy()).Build().Do()
+
+	assert.Equal(t, 302, response.Response().StatusCode, "Should equal HTTP Status 302 (Found)")
+	assert.Equal(t, "/b", response.Response().Header.Get("Location"), "Should expose the redirect location")
+	assert.True(t, len(response.RedirectChain()) == 1, "Should only contain the original URL")
+}
+
+func TestMaxRedirectPolicyFromClient(t *testing.T) {
+	ts := newRedirectServer()
+
+	defer ts.Close()
+
+	defer func() {
+		err, ok := recover().(error)
+
+		assert.True(t, ok, "Should have panicked with an error")
+		assert.Contains(t, err.Error(), "stopped after 1 redirects", "Should equal error message")
+	}()
+
+	c := NewClientBuilder().WithRedirectPolicy(NewMaxRedirectPolicy(1)).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL + "/a").WithClient(c).Build().Do()
+
+	assert.True(t, false, "Should not have completed test")
+}
+
+func TestSameHostRedirectPolicy(t *testing.T) {
+	policy := NewSameHostRedirectPolicy(10)
+	origin, _ := http.NewRequest("GET", "http://api.example.com/a", nil)
+	sameHost, _ := http.NewRequest("GET", "http://api.example.com/b", nil)
+	otherHost, _ := http.NewRequest("GET", "http://evil.example.com/b", nil)
+
+	err := policy.CheckRedirect(otherHost, []*http.Request{origin})
+
+	assert.NotNil(t, err, "Should not be nil")
+	assert.IsType(t, &model.RedirectError{}, err, "Should be a redirect error")
+	assert.Nil(t, policy.CheckRedirect(sameHost, []*http.Request{origin}), "Should follow same-host redirects")
+}
+
+func TestRedirectHeadersStripCredentialsCrossOrigin(t *testing.T) {
+	original, _ := http.NewRequest("GET", "https://api.example.com/a", nil)
+	original.Header.Set("Authorization", "Bearer secret")
+	original.Header.Set("X-Tenant", "acme")
+	original.Header.Set("X-Debug", "1")
+
+	next, _ := http.NewRequest("GET", "https://cdn.example.com/b", nil)
+	next.Header = original.Header.Clone()
+
+	applyRedirectHeaders(&model.RedirectHeaders{
+		StripCrossOrigin: []string{"x-tenant"},
+		Drop:             []string{"X-Debug"},
+	}, next, []*http.Request{original})
+
+	assert.Empty(t, next.Header.Get("Authorization"), "Should strip credentials cross-origin")
+	assert.Empty(t, next.Header.Get("X-Tenant"), "Should strip configured headers cross-origin")
+	assert.Empty(t, next.Header.Get("X-Debug"), "Should always drop configured headers")
+}
+
+func TestRedirectHeadersSameOriginAndPreserve(t *testing.T) {
+	original, _ := http.NewRequest("GET", "https://api.example.com/a", nil)
+	original.Header.Set("Authorization", "Bearer secret")
+	original.Header.Set("X-Api-Key", "key")
+
+	sameOrigin, _ := http.NewRequest("GET", "https://api.example.com:443/b", nil)
+	sameOrigin.Header = original.Header.Clone()
+
+	applyRedirectHeaders(nil, sameOrigin, []*http.Request{original})
+
+	assert.Equal(t, "Bearer secret", sameOrigin.Header.Get("Authorization"), "Should keep credentials on the same origin")
+
+	crossOrigin, _ := http.NewRequest("GET", "https://auth.example.net/b", nil)
+	crossOrigin.Header = http.Header{}
+
+	applyRedirectHeaders(&model.RedirectHeaders{
+		Preserve: []string{"authorization"},
+	}, crossOrigin, []*http.Request{original})
+
+	assert.Equal(t, "Bearer secret", crossOrigin.Header.Get("Authorization"), "Should forward preserved headers cross-origin")
+	assert.Empty(t, crossOrigin.Header.Get("X-Api-Key"), "Should not add headers that were not preserved")
+}
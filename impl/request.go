@@ -1,9 +1,12 @@
 package gorequest
 
 import (
+	"fmt"
 	model "github.com/demianlessa/gorequest/model"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"net/http/httptrace"
+	"time"
 )
 
 /****************************************************
@@ -11,35 +14,492 @@ import (
  ****************************************************/
 
 type request struct {
+	client  *client
+	// Set when the request could not be built; returned by Send.
+	err     error
+	options requestOptions
 	request *http.Request
 }
 
-func newRequest(req *http.Request) model.Request {
+/**
+ * Per-request settings collected by the builder. Unset values fall back to
+ * the settings of the client.
+ */
+type requestOptions struct {
+	cacheTTL        time.Duration
+	checksum        *model.Checksum
+	chunking        model.Chunking
+	clientTrace     *httptrace.ClientTrace
+	debug           *requestLogger
+	decompress      bool
+	fallback        model.Fallback
+	hedge           *model.Hedge
+	priority        model.Priority
+	profilerLabels  []string
+	redirectHeaders *model.RedirectHeaders
+	redirectPolicy  model.RedirectPolicy
+	retry           *model.Retry
+	sink            io.Writer
+	stream          bool
+	variant         tlsVariant
+}
+
+func newRequest(req *http.Request, client *client, options requestOptions) model.Request {
+	if options.redirectPolicy == nil {
+		options.redirectPolicy = client.redirectPolicy
+	}
+	if options.redirectHeaders == nil {
+		options.redirectHeaders = client.redirectHeaders
+	}
+	if options.retry == nil {
+		options.retry = client.retry
+	}
+	if options.hedge == nil {
+		options.hedge = client.hedge
+	}
 	return &request{
+		client:  client,
+		options: options,
 		request: req,
 	}
 }
 
+/**
+ * Wraps a *http.Request built by the caller (or by
+ * RequestBuilder.BuildHttpRequest) into a Request sent through the pipeline
+ * of client, or of the default client if client is nil: hooks, retries,
+ * circuit breakers and the other client settings apply as to any request.
+ * Resending the body needs req.GetBody (set by http.NewRequest for the usual
+ * body types) or model.Retry.BufferBody.
+ */
+func NewRequestFromHttp(client model.Client, req *http.Request) model.Request {
+	return newRequest(req, asClient(client), requestOptions{})
+}
+
+/**
+ * Same as Send, panicking with the error of a failed request.
+ */
 func (r *request) Do() model.Response {
 
-	client := getDefaultHttpClient()
+	resp, err := r.Send()
 
-	resp, err := client.Do(r.request)
+	if err != nil {
+		panic(err)
+	}
 
-	defer resp.Body.Close()
+	return resp
+}
+
+func (r *request) Send() (model.Response, error) {
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	leave, err := r.client.enter()
 
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	defer leave()
+
+	r.client.applyDefaults(r.request)
+
+	memoize := r.options.cacheTTL > 0 && r.request.Method == http.MethodGet && !r.options.stream && r.options.checksum == nil
+
+	if memoize {
+		if resp := r.client.memo.get(r.request, r.options.variant, r.client.canonicalQuery, r.client.clock.Now()); resp != nil {
+			return resp, nil
+		}
+	}
+
+	var resp *response
+
+	r.labelled(func() {
+		if r.client.flights != nil && r.request.Method == http.MethodGet && !r.options.stream && r.options.checksum == nil {
+			resp, err = r.client.flights.do(flightKey(r.request, r.options.variant, r.client.canonicalQuery), r.execute)
+		} else {
+			resp, err = r.execute()
+		}
+	})
+
+	if err == nil && r.options.sink != nil {
+		err = drain(resp, r.options.sink)
+	}
+
+	if err == nil && memoize {
+		r.client.memo.put(r.request, r.options.variant, r.client.canonicalQuery, resp, r.options.cacheTTL, r.client.clock.Now())
+	}
+
+	var result model.Response = resp
+
+	if err == nil && len(r.client.hooks) > 0 {
+		result, err = r.afterResponse(resp)
+	}
 
 	if err != nil {
-		panic(err)
+		resp.discard()
+		err = r.wrapError(err)
+		r.onError(err)
+	}
+
+	if err != nil && r.options.fallback != nil {
+		return r.options.fallback(err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+/**
+ * Runs the request, retrying failed attempts as configured. Attempts after
+ * the first one are sent with a fresh copy of the body; a body that cannot
+ * be replayed fails the request with a *model.BodyNotReplayableError.
+ */
+func (r *request) execute() (*response, error) {
+
+	policy := r.options.retry
+
+	if policy == nil && replayable(r.request) {
+		policy = &defaultRetry
+	}
+
+	retrier := &retrier{
+		budget: r.client.retryBudget,
+		clock:  r.client.clock,
+		ctx:    r.request.Context(),
+		method: r.request.Method,
+		policy: policy,
+		stats:  r.client.retryStats,
+	}
+
+	if len(r.client.hooks) > 0 {
+		retrier.onRetry = r.onRetry
+	}
+
+	injectTrace(r.request, r.client.tracePropagation)
+	injectContextHeaders(r.request, r.client.contextHeaders)
+
+	if policy := r.options.retry; policy != nil && policy.BufferBody && policy.MaxAttempts > 1 {
+		if err := bufferBody(r.request); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := frameBody(r.request, r.options.chunking); err != nil {
+		return nil, err
+	}
+
+	return retrier.do(func(attempt int) (*response, error) {
+
+		req := withAttempt(r.request, attempt)
+
+		if attempt > 1 {
+			clone, err := cloneRequest(req, req.URL)
+			if err != nil {
+				// resending a truncated or empty body is never safe
+				return nil, err
+			}
+			req = clone
+		}
+
+		return r.attempt(req)
+	})
+}
+
+/**
+ * Runs a single attempt, hedged if configured.
+ */
+func (r *request) attempt(req *http.Request) (*response, error) {
+	if r.options.hedge != nil && idempotent(req.Method) && !r.options.stream {
+		return hedge(r.options.hedge, r.client.clock, req, r.route)
+	}
+	return r.route(req)
+}
+
+/**
+ * Sends a copy of the request, failing over across the client's endpoints
+ * if any.
+ */
+func (r *request) route(req *http.Request) (*response, error) {
+	if r.client.endpoints != nil {
+		return r.client.endpoints.do(req, r.exchange)
+	}
+	return r.exchange(req)
+}
+
+/**
+ * Performs a single exchange through the circuit breaker of the host.
+ */
+func (r *request) exchange(req *http.Request) (*response, error) {
+
+	// hooks see the request as it is sent, resolved against its endpoint
+	if err := r.beforeRequest(req); err != nil {
+		return nil, err
+	}
+
+	if r.client.strictIDNA {
+		if err := checkHostScripts(req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+
+	release, err := r.limit(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer release()
+
+	if r.client.breakers == nil {
+		return r.send(req)
+	}
+
+	done, err := r.client.breakers.circuitFor(req.URL.Host).allow()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.send(req)
+
+	done(outcomeOf(req, resp, err))
+
+	return resp, err
+}
+
+
+/**
+ * Waits for the rate limit, bulkhead and concurrency limits of the client
+ * and returns the function releasing the slots taken. Rejections are
+ * returned before the circuit breaker is consulted, so they never count as
+ * failures of the host.
+ */
+func (r *request) limit(req *http.Request) (func(), error) {
+
+	var releases []func()
+
+	release := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+
+	if r.client.quotaPacer != nil {
+		if err := r.client.quotaPacer.wait(req.Context(), req.URL.Host); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.client.rateLimiter != nil {
+		if err := r.client.rateLimiter.wait(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+
+	acquirers := []func() (func(), error){}
+
+	if r.client.bulkheads != nil {
+		acquirers = append(acquirers, func() (func(), error) {
+			return r.client.bulkheads.acquire(req.Context(), req.URL.Hostname(), r.options.priority)
+		})
+	}
+
+	if r.client.concurrency != nil {
+		acquirers = append(acquirers, func() (func(), error) {
+			return r.client.concurrency.acquire(req.Context(), r.options.priority)
+		})
+	}
+
+	if r.client.hostLimiter != nil {
+		acquirers = append(acquirers, func() (func(), error) {
+			return r.client.hostLimiter.acquire(req.Context(), req.URL.Host)
+		})
+	}
+
+	for _, acquire := range acquirers {
+		slot, err := acquire()
+		if err != nil {
+			release()
+			return nil, err
+		}
+		releases = append(releases, slot)
+	}
+
+	return release, nil
+}
+
+/**
+ * Sends the request over the network unless a fault is injected instead.
+ */
+func (r *request) do(req *http.Request) (*http.Response, error) {
+	if r.client.faults != nil {
+		if resp, injected, err := r.client.faults.inject(req); injected {
+			return resp, err
+		}
+	}
+	httpClient, err := r.client.httpClientFor(r.options.variant)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return httpClient.Do(req)
+}
+
+/**
+ * Sends the request, reporting the exchange to the loggers, observers and
+ * audit sink.
+ */
+func (r *request) send(req *http.Request) (*response, error) {
+
+	if len(r.client.loggers) == 0 && len(r.client.observers) == 0 && r.options.debug == nil && r.client.auditor == nil {
+		return r.transfer(req)
+	}
+
+	start := time.Now()
+	resp, err := r.transfer(req)
+	duration := time.Since(start)
+
+	for _, logger := range r.client.loggers {
+		logger.log(req, resp, err, start, duration)
+	}
+
+	if r.options.debug != nil {
+		r.options.debug.log(req, resp, err, start, duration)
+	}
+
+	if r.client.auditor != nil {
+		r.client.auditor.record(req, resp, err, start, duration)
+	}
+
+	if len(r.client.observers) > 0 {
+		exchange := newExchange(req, resp, err, duration)
+		for _, observer := range r.client.observers {
+			observer.ObserveExchange(exchange)
+		}
+	}
+
+	return resp, err
+}
+
+/**
+ * Sends the request (following redirects) and reads the body, unless it is
+ * streamed.
+ */
+func (r *request) transfer(req *http.Request) (*response, error) {
+
+	req, redirects := withRedirectState(req, r.options.redirectPolicy, r.options.redirectHeaders)
+
+	if r.client.stats != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), r.client.stats.trace()))
+	}
+
+	if r.options.clientTrace != nil {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), r.options.clientTrace))
+	}
+
+	recorder := &timingRecorder{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), recorder.trace()))
+
+	if r.client.uploadRate != nil && req.Body != nil && req.Body != http.NoBody {
+		req.Body = newThrottledReader(req.Context(), req.Body, r.client.uploadRate)
+		if getBody := req.GetBody; getBody != nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				body, err := getBody()
+				return newThrottledReader(req.Context(), body, r.client.uploadRate), err
+			}
+		}
+	}
+
+	start := time.Now()
+
+	resp, err := r.do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if r.client.quotaPacer != nil {
+		r.client.quotaPacer.observe(req.URL.Host, resp)
+	}
+
+	verify := r.options.checksum != nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 && resp.StatusCode != http.StatusPartialContent
+
+	// announced digests are of the bytes received, so they are checked before
+	// decompression and not at all once the transport has decompressed them
+	if verify && r.options.checksum.Expected == "" && !resp.Uncompressed {
+		if resp.Body, err = newChecksumReader(resp.Body, *r.options.checksum, resp.Header); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	var decoded *decodedBody
+
+	if r.options.decompress {
+		if decoded, err = decodeBody(resp); err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("Cannot decompress response body: %w", err)
+		}
+	}
+
+	if verify && r.options.checksum.Expected != "" {
+		if resp.Body, err = newChecksumReader(resp.Body, *r.options.checksum, resp.Header); err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+	}
+
+	if r.options.stream {
+		resp.Body = newThrottledReader(req.Context(), resp.Body, r.client.downloadRate)
+		return &response{
+			clock:              r.client.clock,
+			decoded:            decoded,
+			insecureSkipVerify: r.options.variant.insecureSkipVerify,
+			redirectChain:      redirects.chain,
+			response:           resp,
+			streamed:           true,
+			timings:            recorder.snapshot(0, time.Since(start)),
+		}, nil
+	}
+
+	defer resp.Body.Close()
+
+	downloadStart := time.Now()
+
+	size := resp.ContentLength
+
+	// responses to HEAD announce the length of a body they do not carry
+	if resp.Body == http.NoBody {
+		size = 0
+	}
+
+	body, err := readAll(newThrottledReader(req.Context(), resp.Body, r.client.downloadRate), size)
+
+	if err != nil {
+		return nil, err
 	}
 
 	return &response{
-		body: body,
-		response: resp,
+		body:               body,
+		clock:              r.client.clock,
+		decoded:            decoded,
+		insecureSkipVerify: r.options.variant.insecureSkipVerify,
+		redirectChain:      redirects.chain,
+		response:           resp,
+		timings:            recorder.snapshot(time.Since(downloadStart), time.Since(start)),
+	}, nil
+}
+
+func (r *request) wrapError(err error) error {
+	if r.options.variant.insecureSkipVerify {
+		return &model.InsecureRequestError{Err: err}
 	}
+	return err
 }
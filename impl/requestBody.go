@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	model "github.com/demianlessa/gorequest/model"
 	"reflect"
 )
@@ -15,32 +16,58 @@ import (
 type requestBody struct {
 	contentType string
 	data *bytes.Buffer
+	// Set when the content could not be serialized; requests built with the
+	// body fail with it.
+	err error
 }
 
+/**
+ * Strings, byte slices (json.RawMessage included) and readers are sent as
+ * is, structs are marshalled. Only the latter, and pointers to strings, go
+ * through reflection.
+ */
 func newJsonBody(data interface{}) model.RequestBody {
 
 	var buffer *bytes.Buffer
+	var err error
+
+	switch data := data.(type) {
+	case string:
+		buffer = bytes.NewBufferString(data)
+	case []byte:
+		buffer = bytes.NewBuffer(data)
+	case json.RawMessage:
+		buffer = bytes.NewBuffer(data)
+	case io.Reader:
+		var raw []byte
+		raw, err = readAll(data, -1)
+		buffer = bytes.NewBuffer(raw)
+	default:
+		buffer, err = reflectJsonBody(data)
+	}
+
+	return &requestBody{
+		contentType: "application/json",
+		data: buffer,
+		err: err,
+	}
+}
+
+func reflectJsonBody(data interface{}) (*bytes.Buffer, error) {
 
 	indirect := reflect.Indirect(reflect.ValueOf(data))
 
 	switch indirect.Kind() {
 	case reflect.String:
-		buffer = bytes.NewBuffer([]byte(indirect.String()))
-		break
+		return bytes.NewBuffer([]byte(indirect.String())), nil
 	case reflect.Struct:
-		if rawBytes, err := json.Marshal(indirect.Interface()); err == nil {
-			buffer = bytes.NewBuffer(rawBytes)
-		} else {
-			panic(err)
+		rawBytes, err := json.Marshal(indirect.Interface())
+		if err != nil {
+			return nil, err
 		}
-		break
+		return bytes.NewBuffer(rawBytes), nil
 	default:
-		panic(errors.New("Can only serialize a string or a struct as JSON content."))
-	}
-
-	return &requestBody{
-		contentType: "application/json",
-		data: buffer,
+		return nil, errors.New("Can only serialize a string, bytes, a reader or a struct as JSON content.")
 	}
 }
 
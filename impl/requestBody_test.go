@@ -0,0 +1,42 @@
+package gorequest
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonBody(t *testing.T) {
+	text := `{"id":1}`
+	value := struct {
+		ID int `json:"id"`
+	}{1}
+
+	for _, data := range []interface{}{text, &text, []byte(text), json.RawMessage(text), strings.NewReader(text), value, &value} {
+		body := newJsonBody(data)
+
+		assert.Equal(t, text, body.RawData().String(), "Should serialize %T", data)
+		assert.Equal(t, "application/json", body.ContentType(), "Should be JSON")
+	}
+
+	_, err := NewRequestBuilder().WithUrl(testUrl).WithMethod("POST").WithBody(newJsonBody(42)).Build().Send()
+
+	assert.Equal(t, "Cannot serialize request body: Can only serialize a string, bytes, a reader or a struct as JSON content.", err.Error(), "Should fail on unsupported types")
+	assert.NotNil(t, errors.Unwrap(err), "Should wrap the cause")
+}
+
+func BenchmarkJsonBody(b *testing.B) {
+	text := `{"id":1,"name":"alice"}`
+
+	for name, data := range map[string]interface{}{"string": text, "bytes": []byte(text), "struct": struct{ ID int }{1}} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				newJsonBody(data)
+			}
+		})
+	}
+}
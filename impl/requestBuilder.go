@@ -2,10 +2,15 @@ package gorequest
 
 import (
 	"bytes"
+	"context"
 	model "github.com/demianlessa/gorequest/model"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptrace"
 	"strings"
+	"time"
 )
 
 /****************************************************
@@ -13,28 +18,116 @@ import (
  ****************************************************/
 
 type requestBuilder struct {
-	auth    	model.AuthorizationMethod
-	body    	model.RequestBody
-	headers 	map[string]string
-	method  	string
-	url     	string
+	acceptEncoding     string
+	auth               model.AuthorizationMethod
+	body               model.RequestBody
+	cacheTTL           time.Duration
+	checksum           *model.Checksum
+	chunking           model.Chunking
+	client             model.Client
+	clientTrace        *httptrace.ClientTrace
+	contentDigest      string
+	ctx                context.Context
+	debug              *requestLogger
+	fallback           model.Fallback
+	headers            map[string]string
+	hedge              *model.Hedge
+	insecureSkipVerify bool
+	method             string
+	priority           model.Priority
+	profilerLabels     []string
+	redirectHeaders    *model.RedirectHeaders
+	redirectPolicy     model.RedirectPolicy
+	retry              *model.Retry
+	serverName         string
+	sink               io.Writer
+	stream             bool
+	url                string
+	withoutDecompress  bool
 }
 
+/**
+ * A request that cannot be built (no URL, a body that cannot be serialized)
+ * fails when it is sent.
+ */
 func (b *requestBuilder) Build() model.Request {
 
-	b.validate()
-	
-	var body *bytes.Buffer = &bytes.Buffer{}
+	req, err := b.BuildHttpRequest(b.ctx)
+
+	if err != nil {
+		return &request{client: asClient(b.client), err: err}
+	}
+
+	return newRequest(req, asClient(b.client), requestOptions{
+		cacheTTL:        b.cacheTTL,
+		checksum:        b.checksum,
+		chunking:        b.chunking,
+		clientTrace:     b.clientTrace,
+		debug:           b.debug,
+		decompress:      b.acceptEncoding != "" && !b.withoutDecompress,
+		fallback:        b.fallback,
+		hedge:           b.hedge,
+		priority:        b.priority,
+		profilerLabels:  append([]string(nil), b.profilerLabels...),
+		redirectHeaders: b.redirectHeaders,
+		redirectPolicy:  b.redirectPolicy,
+		retry:           b.retry,
+		sink:            b.sink,
+		stream:          b.stream,
+		variant: tlsVariant{
+			insecureSkipVerify: b.insecureSkipVerify,
+			serverName:         b.serverName,
+		},
+	})
+}
+
+/**
+ * Builds the *http.Request that Build would send, with its headers,
+ * authorization and body, bound to ctx. Options that apply while sending
+ * (client, retries, hedging...) are not part of it; the request can be sent
+ * through the client pipeline with NewRequestFromHttp.
+ */
+func (b *requestBuilder) BuildHttpRequest(ctx context.Context) (*http.Request, error) {
+
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+
+	if failed, ok := b.body.(*requestBody); ok && failed.err != nil {
+		return nil, fmt.Errorf("Cannot serialize request body: %w", failed.err)
+	}
 
 	if b.body != nil {
-		body = b.body.RawData()
+		// a reader of its own, so that building again (polling, paging)
+		// resends the whole body rather than what the last send left
+		if data := b.body.RawData(); data != nil {
+			body = bytes.NewReader(data.Bytes())
+		}
 		b.headers["Content-Type"] = b.body.ContentType()
 	}
 
-	req, err := http.NewRequest(b.method, b.url, body)
+	req, err := http.NewRequestWithContext(ctx, b.method, b.url, body)
 
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("Invalid request: %w", err)
+	}
+
+	// Internationalized hosts are sent, logged and keyed in punycode
+	if req.URL.Host, err = HostToASCII(req.URL.Host); err != nil {
+		return nil, fmt.Errorf("Invalid request: %w", err)
+	}
+	req.Host = req.URL.Host
+
+	if b.contentDigest != "" {
+		if err := setContentDigest(req, b.contentDigest); err != nil {
+			return nil, fmt.Errorf("Cannot digest request body: %w", err)
+		}
+	}
+
+	if b.acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", b.acceptEncoding)
 	}
 
 	// delegate the authorization configuration
@@ -49,7 +142,26 @@ func (b *requestBuilder) Build() model.Request {
 		req.Header.Add(k, v)
 	}
 
-	return newRequest(req)
+	// the transport only decompresses the gzip it advertises itself
+	if b.withoutDecompress && req.Header.Get("Accept-Encoding") == "" && req.Header.Get("Range") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	return req, nil
+}
+
+/**
+ * Advertises encodings as the Accept-Encoding of the request instead of the
+ * gzip the transport adds, e.g. "identity" for uncompressed bodies. gzip
+ * and deflate bodies are decompressed, unless WithoutDecompression is set,
+ * and report their size as received; see Response.ContentEncoding.
+ */
+func (b *requestBuilder) WithAcceptEncoding(encodings ...string) model.RequestBuilder {
+	if len(encodings) == 0 {
+		panic(errors.New("At least one encoding is required"))
+	}
+	b.acceptEncoding = strings.Join(encodings, ", ")
+	return b
 }
 
 func (b *requestBuilder) WithBasicAuth(user string, password string) model.RequestBuilder {
@@ -67,6 +179,90 @@ func (b *requestBuilder) WithBody(body model.RequestBody) model.RequestBuilder {
 	return b
 }
 
+/**
+ * Memoizes the response of this GET request for ttl: identical requests
+ * (same URL and same values of the headers the response varies on) sent on
+ * the same client within ttl get a copy of it without reaching the network,
+ * hooks or observers. Only 200 responses are kept; caching headers are
+ * ignored. The number of entries is bounded per client, see
+ * ClientBuilder.WithMemoizationLimit.
+ */
+func (b *requestBuilder) WithCacheTTL(ttl time.Duration) model.RequestBuilder {
+	if ttl < 0 {
+		panic(errors.New("Cache TTL cannot be negative"))
+	}
+	b.cacheTTL = ttl
+	return b
+}
+
+/**
+ * Verifies the body of 2xx responses (206 excepted) against checksum as it
+ * is read: reading fails with a *model.ChecksumError on a mismatch, so the
+ * request fails, or, for streamed responses, the last read of the body.
+ * An expected digest is of the body as returned, after decompression;
+ * digests announced by the response are of the bytes received, so they go
+ * unchecked when the transport decompressed the body itself. Verified
+ * requests are neither memoized nor deduplicated, so they never get a body
+ * that was not verified. Panics on unknown algorithms.
+ */
+func (b *requestBuilder) WithChecksum(checksum model.Checksum) model.RequestBuilder {
+	if newDigest(checksum.Algorithm) == nil {
+		panic(fmt.Errorf("Unknown checksum algorithm: %s", checksum.Algorithm))
+	}
+	b.checksum = &checksum
+	return b
+}
+
+/**
+ * Forces or forbids the chunked encoding of the request body, see
+ * model.Chunking.
+ */
+func (b *requestBuilder) WithChunking(chunking model.Chunking) model.RequestBuilder {
+	b.chunking = chunking
+	return b
+}
+
+func (b *requestBuilder) WithClient(client model.Client) model.RequestBuilder {
+	b.client = client
+	return b
+}
+
+/**
+ * Observes the connection lifecycle (DNS, connect, TLS handshake, first
+ * response byte) of this request. Hooks are called for every redirect hop.
+ */
+func (b *requestBuilder) WithClientTrace(trace *httptrace.ClientTrace) model.RequestBuilder {
+	b.clientTrace = trace
+	return b
+}
+
+/**
+ * Sends the digest of the request body computed with algorithm: as
+ * Content-MD5 for model.ChecksumMD5, as an RFC 9530 Content-Digest for
+ * model.ChecksumSHA256. The body is hashed as it is read, without copying
+ * it. Panics on unknown algorithms.
+ */
+func (b *requestBuilder) WithContentDigest(algorithm string) model.RequestBuilder {
+	if newDigest(algorithm) == nil {
+		panic(fmt.Errorf("Unknown checksum algorithm: %s", algorithm))
+	}
+	b.contentDigest = algorithm
+	return b
+}
+
+/**
+ * Sets the context of the request. Its cancellation aborts the request,
+ * including pending retries; its deadline also cuts retries short when the
+ * next attempt would not finish in time.
+ */
+func (b *requestBuilder) WithContext(ctx context.Context) model.RequestBuilder {
+	if ctx == nil {
+		panic(errors.New("Context cannot be nil"))
+	}
+	b.ctx = ctx
+	return b
+}
+
 func (b *requestBuilder) WithCustomAuth(auth model.AuthorizationMethod) model.RequestBuilder {
 	if auth != nil {
 		b.auth = auth
@@ -76,41 +272,192 @@ func (b *requestBuilder) WithCustomAuth(auth model.AuthorizationMethod) model.Re
 	return b
 }
 
+/**
+ * Writes every exchange of this request to output (stderr if nil) as curl -v
+ * does: request line, headers and, if bodies is set, bodies truncated to
+ * 4 KiB. Credentials headers are redacted, the query is written as sent.
+ */
+func (b *requestBuilder) WithDebug(output io.Writer, bodies bool) model.RequestBuilder {
+	b.debug = newRequestLogger(model.Logging{
+		LogBodies: bodies,
+		LogQuery:  true,
+		Logger:    newDebugLogger(output),
+	})
+	return b
+}
+
+/**
+ * Sets the function called when the request fails after all of its
+ * attempts; its outcome replaces the failure.
+ */
+func (b *requestBuilder) WithFallback(fallback model.Fallback) model.RequestBuilder {
+	b.fallback = fallback
+	return b
+}
+
 func (b *requestBuilder) WithHeader(name, value string) model.RequestBuilder {
 	b.headers[name] = value
 	return b
 }
 
+/**
+ * Adds every header of headers, as WithHeader would.
+ */
+func (b *requestBuilder) WithHeaders(headers model.Headers) model.RequestBuilder {
+	for name, value := range headers {
+		b.headers[name] = value
+	}
+	return b
+}
+
+/**
+ * Overrides the hedging settings of the client for this request.
+ */
+func (b *requestBuilder) WithHedging(hedge model.Hedge) model.RequestBuilder {
+	b.hedge = &hedge
+	return b
+}
+
+/**
+ * Disables certificate verification for this request only. Other requests
+ * sharing the client keep verifying; the response (or the error) is tagged.
+ */
+func (b *requestBuilder) WithInsecureSkipVerify() model.RequestBuilder {
+	b.insecureSkipVerify = true
+	return b
+}
+
 func (b *requestBuilder) WithMethod(method string) model.RequestBuilder {
 	b.method = method
 	return b
 }
 
+/**
+ * Sets the dispatch order of the request when a concurrency limit, a
+ * bulkhead or a Queue is saturated.
+ */
+func (b *requestBuilder) WithPriority(priority model.Priority) model.RequestBuilder {
+	b.priority = priority
+	return b
+}
+
+/**
+ * Adds pprof labels, as key/value pairs, to the goroutines running the
+ * request, e.g. "route", "/users/{id}"; the host and method labels of
+ * ClientBuilder.WithProfilerLabels are added too.
+ */
+func (b *requestBuilder) WithProfilerLabels(labels ...string) model.RequestBuilder {
+	if len(labels)%2 != 0 {
+		panic(errors.New("Profiler labels must be key/value pairs"))
+	}
+	b.profilerLabels = append(b.profilerLabels, labels...)
+	return b
+}
+
+/**
+ * Overrides the header forwarding rules of the client for this request.
+ */
+func (b *requestBuilder) WithRedirectHeaders(rules model.RedirectHeaders) model.RequestBuilder {
+	b.redirectHeaders = &rules
+	return b
+}
+
+/**
+ * Overrides the redirect policy of the client for this request.
+ */
+func (b *requestBuilder) WithRedirectPolicy(policy model.RedirectPolicy) model.RequestBuilder {
+	b.redirectPolicy = policy
+	return b
+}
+
+/**
+ * Copies the response body to sink instead of keeping it in memory:
+ * Response.Body() is then nil. Only the body of the final response is
+ * copied, once retries are over; failing to copy it fails the request. As
+ * with WithResponseStream, the request is neither hedged nor deduplicated.
+ */
+func (b *requestBuilder) WithResponseSink(sink io.Writer) model.RequestBuilder {
+	if sink == nil {
+		panic(errors.New("Response sink cannot be nil"))
+	}
+	b.sink = sink
+	b.stream = true
+	return b
+}
+
+/**
+ * Leaves the response body unread: it is read from Response().Body, which
+ * the caller must close, and Response.Body() is nil. Responses discarded by
+ * retries are closed. Streamed requests are neither hedged (the losing
+ * copies would cancel the winner) nor deduplicated, and loggers, observers
+ * and timings do not see the body.
+ */
+func (b *requestBuilder) WithResponseStream() model.RequestBuilder {
+	b.stream = true
+	return b
+}
+
+/**
+ * Overrides the retry settings of the client for this request.
+ */
+func (b *requestBuilder) WithRetry(retry model.Retry) model.RequestBuilder {
+	b.retry = &retry
+	return b
+}
+
+/**
+ * Overrides the TLS server name (SNI and certificate verification) without
+ * changing the dialed address, e.g. to reach an origin behind a CDN by IP.
+ */
+func (b *requestBuilder) WithServerName(name string) model.RequestBuilder {
+	b.serverName = name
+	return b
+}
+
 func (b *requestBuilder) WithUrl(url string) model.RequestBuilder {
 	b.url = url
 	return b
 }
 
-func (b *requestBuilder) validate() {
+/**
+ * Hands compressed response bodies over as received, Content-Encoding and
+ * Content-Length headers included, e.g. to store or proxy them as they are.
+ */
+func (b *requestBuilder) WithoutDecompression() model.RequestBuilder {
+	b.withoutDecompress = true
+	return b
+}
+
+func (b *requestBuilder) validate() error {
 
 	if strings.Trim(b.url, " ") == "" {
-		panic(errors.New("URL is required"))
+		return errors.New("URL is required")
 	}
 
 	// validate method and synchronize the body
-	switch strings.ToUpper(b.method) {
-	case "POST":
-		b.method = "POST"
-	case "PUT":
-		b.method = "PUT"
-	case "DELETE":
-		b.method = "DELETE"
-		b.body = nil
-	case "HEAD":
-		b.method = "HEAD"
+	switch method := strings.ToUpper(b.method); method {
+	case "POST", "PUT", "PATCH":
+		b.method = method
+	case "DELETE", "HEAD":
+		b.method = method
 		b.body = nil
-	default:
+	case "", "GET":
 		b.method = "GET"
 		b.body = nil
-	}	
+	default:
+		// extension methods, e.g. those of WebDAV, keep their body
+		if strings.IndexFunc(method, notTokenChar) >= 0 {
+			return fmt.Errorf("Invalid method: %s", b.method)
+		}
+		b.method = method
+	}
+
+	return nil
+}
+
+/**
+ * Reports whether r cannot appear in an HTTP token such as a method name.
+ */
+func notTokenChar(r rune) bool {
+	return !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r))
 }
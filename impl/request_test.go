@@ -1,14 +1,21 @@
 package gorequest
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/http/httptrace"
+	"net/url"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	model "github.com/demianlessa/gorequest/model"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
@@ -68,6 +75,24 @@ func TestValidateMultipleInstances(t *testing.T) {
 	assert.False(t, i1 == i2, "Should be different instances")
 }
 
+func TestValidateConcurrentDefaultClient(t *testing.T) {
+	httpClient = nil
+
+	clients := make(chan *client, 8)
+
+	for i := 0; i < cap(clients); i++ {
+		go func() {
+			clients <- getDefaultClient()
+		}()
+	}
+
+	first := <-clients
+
+	for i := 1; i < cap(clients); i++ {
+		assert.True(t, first == <-clients, "Should create a single instance")
+	}
+}
+
 func TestValidateNewAuth(t *testing.T) {
 	auth := newAuthBearer(hash)
 	
@@ -211,16 +236,50 @@ func TestNewRequestPanicWhenInvalidStructType(t *testing.T) {
 */
 
 func TestNewRequestWithoutURL(t *testing.T) {
-	defer func() {
-		err := recover().(error)
+	resp, err := NewRequestBuilder().Build().Send()
 
-		assert.NotNil(t, err, "Should not be nil")
-		assert.Equal(t, "URL is required", err.Error(), "Should equal error message")
+	assert.Nil(t, resp, "Should not have a response")
+	assert.Equal(t, "URL is required", err.Error(), "Should equal error message")
+}
+
+func TestSendReturnsErrors(t *testing.T) {
+	_, err := NewRequestBuilder().WithUrl("http://[::1").Build().Send()
+
+	var urlErr *url.Error
+
+	assert.True(t, errors.As(err, &urlErr), "Should wrap the parse error")
+
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithMaxRequestsPerHost(1, model.OverflowReject).Build()
+
+	started := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithClientTrace(&httptrace.ClientTrace{
+			WroteRequest: func(httptrace.WroteRequestInfo) { close(started) },
+		}).Build().Send()
+		close(done)
 	}()
 
-	NewRequestBuilder().Build()
+	<-started
 
-	assert.True(t, false, "Should not have completed test")
+	resp, err := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Send()
+
+	_, ok := err.(*model.HostLimitError)
+
+	assert.Nil(t, resp, "Should not have a response")
+	assert.True(t, ok, "Should return the rejection")
+
+	close(release)
+	<-done
 }
 
 func TestBasicAuthentication(t *testing.T) {
@@ -305,3 +364,98 @@ func TestHeadRequest(t *testing.T) {
 	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
 	assert.Empty(t, string(response.Body()), "Should be empty")
 }
+
+func TestHttpRequestInterop(t *testing.T) {
+	var attempts int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := ioutil.ReadAll(req.Body)
+		fmt.Fprintf(resp, "%s %s %s", req.Header.Get("Authorization"), req.Header.Get("Content-Type"), body)
+	}))
+
+	defer ts.Close()
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	req, err := NewRequestBuilder().
+		WithUrl(ts.URL).
+		WithMethod("POST").
+		WithBearerAuth("token").
+		WithBody(newJsonBody(`{"id":1}`)).
+		BuildHttpRequest(ctx)
+
+	assert.Nil(t, err, "Should build the request")
+	assert.Equal(t, "value", req.Context().Value(ctxKey{}), "Should bind the context")
+
+	c := NewClientBuilder().WithRetry(model.Retry{MaxAttempts: 2, BaseDelay: time.Millisecond, RetryNonIdempotent: true}).Build()
+
+	resp := NewRequestFromHttp(c, req).Do()
+
+	assert.Equal(t, `Bearer token application/json {"id":1}`, string(resp.Body()), "Should send the request through the client")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts), "Should retry as configured on the client")
+
+	_, err = NewRequestBuilder().BuildHttpRequest(ctx)
+
+	assert.Equal(t, "URL is required", err.Error(), "Should return validation errors")
+}
+
+func TestFunctionalOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		fmt.Fprintf(resp, "%s %s %s %s", req.Method, req.URL.Path, req.Header.Get("Authorization"), body)
+	}))
+
+	defer ts.Close()
+
+	c := NewClient(WithTimeout(time.Second), WithBaseURL(ts.URL), WithRetry(model.Retry{MaxAttempts: 2}))
+
+	assert.Equal(t, time.Second, c.HttpClient().Timeout, "Should apply the client options")
+
+	resp := NewRequest(
+		WithClient(c),
+		WithUrl("/users"),
+		WithMethod("POST"),
+		WithBearerAuth("token"),
+		WithBody(newJsonBody(`{"id":1}`)),
+	).Do()
+
+	assert.Equal(t, `POST /users Bearer token {"id":1}`, string(resp.Body()), "Should apply the request options")
+
+	defer func() {
+		assert.Equal(t, "Option does not apply to requests", recover().(error).Error(), "Should reject client options")
+	}()
+
+	NewRequest(WithTimeout(time.Second))
+}
+
+func TestHeaders(t *testing.T) {
+	headers := model.Headers{}.JSON().BearerToken("token").Set("x-tenant", "a").Add("X-Tenant", "b")
+
+	assert.Equal(t, "a, b", headers.Get("X-TENANT"), "Should find headers whatever their case")
+	assert.Equal(t, map[string]string{
+		"Accept":        "application/json",
+		"Authorization": "Bearer token",
+		"Content-Type":  "application/json",
+		"X-Tenant":      "a, b",
+	}, map[string]string(headers), "Should canonicalize names")
+
+	headers.Del("x-tenant")
+
+	assert.Equal(t, "", headers.Get(model.HeaderAccept+"-Language"), "Should return an empty value for missing headers")
+	assert.Equal(t, 3, len(headers), "Should delete headers whatever their case")
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(resp, "%s %s", req.Header.Get("Accept"), req.Header.Get("Authorization"))
+	}))
+
+	defer ts.Close()
+
+	resp := NewRequestBuilder().WithUrl(ts.URL).WithHeaders(headers).Build().Do()
+
+	assert.Equal(t, "application/json Bearer token", string(resp.Body()), "Should send the headers")
+}
@@ -0,0 +1,309 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMock(t *testing.T) {
+	mock := requestmock.New()
+
+	users := mock.On("GET", "https://api.example.com/users/*").
+		MatchHeader("Accept", "application/json").
+		ReplyJSON(http.StatusOK, map[string]int{"id": 1})
+
+	created := mock.On("POST", "/users").
+		MatchJSON(`{"name": "alice"}`).
+		Reply(http.StatusCreated, "").
+		ReplyHeader("Location", "/users/2").
+		Times(1)
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	response := NewRequestBuilder().WithUrl("https://api.example.com/users/1?fields=id").WithHeader("Accept", "application/json").WithClient(c).Build().Do()
+
+	assert.Equal(t, `{"id":1}`, string(response.Body()), "Should reply with the stubbed body")
+	assert.Equal(t, "application/json", response.Response().Header.Get("Content-Type"), "Should reply with the stubbed headers")
+
+	response = NewRequestBuilder().WithUrl("https://api.example.com/users").WithMethod("POST").WithBody(newJsonBody(`{"name":"alice"}`)).WithClient(c).Build().Do()
+
+	assert.Equal(t, http.StatusCreated, response.Response().StatusCode, "Should equal status")
+	assert.Equal(t, "/users/2", response.Response().Header.Get("Location"), "Should equal header")
+	assert.Equal(t, 1, users.Calls(), "Should count calls")
+	assert.Equal(t, 1, created.Calls(), "Should count calls")
+	assert.Nil(t, mock.Verify(), "Should meet expectations")
+
+	func() {
+		defer func() {
+			err := recover()
+
+			var unmatched *requestmock.UnmatchedError
+
+			assert.True(t, errors.As(err.(error), &unmatched), "Should fail with an unmatched error")
+			assert.Contains(t, mock.Verify().Error(), "unmatched POST https://api.example.com/users", "Should report unmatched requests")
+		}()
+
+		NewRequestBuilder().WithUrl("https://api.example.com/users").WithMethod("POST").WithBody(newJsonBody(`{"name":"alice"}`)).WithClient(c).Build().Do()
+	}()
+}
+
+func TestRequestMockErrorsAndDelays(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/flaky").ReplyError(syscall.ECONNREFUSED).Times(1)
+	mock.On("GET", "/flaky").Reply(http.StatusOK, "OK")
+	mock.On("*", "/slow").Delay(time.Second)
+
+	c := NewClientBuilder().WithTransport(mock).WithRetry(model.Retry{MaxAttempts: 2}).Build()
+
+	response := NewRequestBuilder().WithUrl("http://service.internal/flaky").WithClient(c).Build().Do()
+
+	assert.Equal(t, "OK", string(response.Body()), "Should retry the stubbed connection error")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	defer func() {
+		err := recover()
+
+		assert.True(t, errors.Is(err.(error), context.DeadlineExceeded), "Should honor the request context")
+	}()
+
+	NewRequestBuilder().WithUrl("http://service.internal/slow").WithContext(ctx).WithClient(c).Build().Do()
+}
+
+func TestCassette(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		resp.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprintf(resp, "call %d", n)
+	}))
+
+	defer ts.Close()
+
+	cassette := requestmock.Cassette{
+		Path:        filepath.Join(t.TempDir(), "cassette.json"),
+		RedactQuery: []string{"api_key"},
+	}
+
+	send := func(c model.Client) []string {
+		var bodies []string
+		for i := 0; i < 2; i++ {
+			response := NewRequestBuilder().WithUrl(ts.URL + "/items?api_key=secret").WithBearerAuth("token").WithClient(c).Build().Do()
+			bodies = append(bodies, string(response.Body()))
+		}
+		return bodies
+	}
+
+	recorder := requestmock.NewRecorder(cassette)
+
+	assert.True(t, recorder.Recording(), "Should record without a cassette")
+	assert.Equal(t, []string{"call 1", "call 2"}, send(NewClientBuilder().WithTransport(recorder).Build()), "Should hit the server")
+	assert.Nil(t, recorder.Stop(), "Should save the cassette")
+
+	data, _ := ioutil.ReadFile(cassette.Path)
+
+	assert.NotContains(t, string(data), "secret", "Should redact secrets")
+	assert.NotContains(t, string(data), "token", "Should redact credentials")
+
+	recorder = requestmock.NewRecorder(cassette)
+
+	assert.False(t, recorder.Recording(), "Should replay an existing cassette")
+	assert.Equal(t, []string{"call 1", "call 2"}, send(NewClientBuilder().WithTransport(recorder).Build()), "Should replay in order")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "Should not hit the server")
+}
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) Helper() {
+}
+
+func TestRequestMockAssertions(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("POST", "/v1/users").Reply(http.StatusCreated, "")
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	for _, name := range []string{"alice", "bob"} {
+		NewRequestBuilder().
+			WithUrl("https://api.example.com/v1/users?notify=true").
+			WithMethod("POST").
+			WithHeader("X-Tenant", "acme").
+			WithBody(newJsonBody(`{"name":"` + name + `"}`)).
+			WithClient(c).
+			Build().
+			Do()
+	}
+
+	assert.True(t, mock.AssertCalled(t, "POST", "/v1/users", requestmock.Times(2)), "Should count calls")
+	assert.True(t, mock.AssertCalled(t, "POST", "https://api.example.com/v1/*", requestmock.WithHeader("X-Tenant", "acme"), requestmock.WithQuery("notify", "true")), "Should match headers and query")
+	assert.True(t, mock.AssertCalled(t, "POST", "/v1/users", requestmock.WithJSON(`{"name": "bob"}`), requestmock.Times(1)), "Should match JSON bodies")
+	assert.True(t, mock.AssertNotCalled(t, "DELETE", "/v1/users"), "Should not count other methods")
+
+	failing := &recordingT{}
+
+	assert.False(t, mock.AssertCalled(failing, "POST", "/v1/users", requestmock.Times(3)), "Should fail on a wrong count")
+	assert.Contains(t, failing.errors[0], "Expected POST /v1/users to be called 3 times, got 2 calls", "Should describe the failure")
+	assert.Contains(t, failing.errors[0], "POST https://api.example.com/v1/users?notify=true", "Should list the calls received")
+}
+
+func TestRequestMockFixtures(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("POST", "/users").
+		ReplyFixture(filepath.Join("testdata", "user.http"), map[string]int{"Status": http.StatusCreated}).
+		ReplyHeader("X-Fixture", "user")
+
+	mock.On("GET", "/logo").ReplyFile(http.StatusOK, filepath.Join("testdata", "logo.png"))
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	response := NewRequestBuilder().WithUrl("https://api.example.com/users?id=42").WithMethod("POST").WithClient(c).Build().Do()
+
+	assert.Equal(t, http.StatusCreated, response.Response().StatusCode, "Should render the status")
+	assert.Equal(t, "/users/42", response.Response().Header.Get("Location"), "Should render the headers")
+	assert.Equal(t, "user", response.Response().Header.Get("X-Fixture"), "Should add the stubbed headers")
+	assert.Equal(t, "{\"id\":42,\"name\":\"Ada\"}\n", string(response.Body()), "Should reply with the fixture body")
+
+	expected, _ := ioutil.ReadFile(filepath.Join("testdata", "logo.png"))
+
+	response = NewRequestBuilder().WithUrl("https://api.example.com/logo").WithClient(c).Build().Do()
+
+	assert.Equal(t, expected, response.Body(), "Should reply with the file")
+	assert.Equal(t, "image/png", response.Response().Header.Get("Content-Type"), "Should derive the content type")
+
+	func() {
+		defer func() { assert.NotNil(t, recover(), "Should panic on a missing file") }()
+		mock.On("GET", "/missing").ReplyFile(http.StatusOK, filepath.Join("testdata", "missing.json"))
+	}()
+}
+
+func TestDoer(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/users/1").Reply(http.StatusOK, `{"id":1}`)
+	mock.On("POST", "/users").MatchJSON(`{"name":"alice"}`).Reply(http.StatusCreated, "")
+
+	var doer model.Doer = NewDoer(NewClientBuilder().WithTransport(mock).Build())
+
+	assert.Equal(t, `{"id":1}`, string(doer.Get("https://api.example.com/users/1").Body()), "Should send GET requests")
+	assert.Equal(t, http.StatusCreated, doer.Post("https://api.example.com/users", newJsonBody(`{"name":"alice"}`)).Response().StatusCode, "Should send POST requests with a body")
+
+	response, err := doer.Send("GET", "https://api.example.com/users/1", nil)
+
+	assert.Nil(t, err, "Should send requests without panicking")
+	assert.Equal(t, `{"id":1}`, string(response.Body()), "Should return the response")
+
+	_, err = doer.Send("GET", "https://api.example.com/unknown", nil)
+
+	var unmatched *requestmock.UnmatchedError
+	assert.True(t, errors.As(err, &unmatched), "Should return the error of failed requests")
+
+	type tenantKey struct{}
+
+	tenant := mock.On("GET", "/tenant").
+		Match(func(req *http.Request, body []byte) bool { return req.Context().Value(tenantKey{}) == "acme" }).
+		Reply(http.StatusOK, "")
+
+	doer.WithContext(context.WithValue(context.Background(), tenantKey{}, "acme")).Get("https://api.example.com/tenant")
+
+	assert.Equal(t, 1, tenant.Calls(), "Should send requests with the context")
+}
+
+func TestGolden(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/users/1").
+		Reply(http.StatusOK, `{"name":"alice","id":1,"created":"2024-05-01T10:00:00Z"}`).
+		ReplyHeader("Content-Type", "application/json").
+		ReplyHeader("Date", "Wed, 01 May 2024 10:00:00 GMT").
+		ReplyHeader("X-Request-Id", "a1b2c3")
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	response := NewRequestBuilder().WithUrl("https://api.example.com/users/1").WithClient(c).Build().Do()
+
+	assert.True(t, requestmock.AssertGolden(t, filepath.Join("testdata", "user.golden"), response, requestmock.MaskHeaders("X-Request-Id")), "Should match the golden file")
+
+	failing := &recordingT{}
+
+	assert.False(t, requestmock.AssertGolden(failing, filepath.Join("testdata", "user.golden"), response), "Should fail on differences")
+	assert.Contains(t, failing.errors[0], `actual:   "X-Request-Id: a1b2c3"`, "Should describe the first difference")
+
+	path := filepath.Join(t.TempDir(), "golden", "user.golden")
+
+	*requestmock.UpdateGolden = true
+	defer func() { *requestmock.UpdateGolden = false }()
+
+	assert.True(t, requestmock.AssertGolden(t, path, response), "Should write the golden file")
+
+	written, _ := ioutil.ReadFile(path)
+
+	assert.Equal(t, string(requestmock.NormalizeResponse(response)), string(written), "Should write the normalized response")
+}
+
+func TestOffline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, "live")
+	}))
+
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	recorder := requestmock.NewRecorder(requestmock.Cassette{Path: path, Mode: requestmock.ModeRecord})
+	NewRequestBuilder().WithUrl(ts.URL + "/items").WithClient(NewClientBuilder().WithTransport(recorder).Build()).Build().Do()
+	recorder.Stop()
+
+	func() {
+		defer func() { assert.NotNil(t, recover(), "Should refuse a recording transport") }()
+		NewClientBuilder().WithOffline(requestmock.NewRecorder(requestmock.Cassette{Path: path, Mode: requestmock.ModeRecord}))
+	}()
+
+	t.Setenv(requestmock.OfflineEnv, "1")
+
+	recorder = requestmock.NewRecorder(requestmock.Cassette{Path: path, Mode: requestmock.ModeRecord})
+
+	assert.False(t, recorder.Recording(), "Should force replay offline")
+
+	c := NewClientBuilder().WithTransport(http.DefaultTransport).WithOffline(recorder).Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL + "/items").WithClient(c).Build().Do()
+
+	assert.Equal(t, "live", string(response.Body()), "Should replay the cassette")
+
+	func() {
+		defer func() {
+			var unmatched *requestmock.UnmatchedError
+			assert.True(t, errors.As(recover().(error), &unmatched), "Should fail on unmatched requests")
+		}()
+		NewRequestBuilder().WithUrl(ts.URL + "/other").WithClient(c).Build().Do()
+	}()
+
+	func() {
+		defer func() { assert.True(t, errors.Is(recover().(error), model.ErrOffline), "Should refuse network access") }()
+		NewRequestBuilder().WithUrl(ts.URL + "/items").WithClient(NewClientBuilder().WithOffline(nil).Build()).Build().Do()
+	}()
+}
@@ -1,7 +1,15 @@
 package gorequest
 
 import (
+	"bytes"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
 )
 
 /****************************************************
@@ -9,14 +17,148 @@ import (
  ****************************************************/
 
 type response struct {
-	body []byte
-	response *http.Response
+	body               []byte
+	clock              model.Clock
+	closed             bool
+	decoded            *decodedBody
+	insecureSkipVerify bool
+	redirectChain      []*url.URL
+	response           *http.Response
+	streamed           bool
+	timings            model.Timings
 }
 
 func (r *response) Body() []byte {
 	return r.body
 }
 
+/**
+ * Describes how the body was encoded on the wire and whether it was
+ * decompressed on the way in.
+ */
+func (r *response) ContentEncoding() model.ContentEncoding {
+
+	if r.decoded != nil {
+		return model.ContentEncoding{
+			Decompressed: true,
+			Encoding:     r.decoded.encoding,
+			EncodedSize:  atomic.LoadInt64(&r.decoded.received),
+		}
+	}
+
+	if r.response == nil {
+		return model.ContentEncoding{EncodedSize: int64(len(r.body))}
+	}
+
+	if r.response.Uncompressed {
+		return model.ContentEncoding{Decompressed: true, Encoding: "gzip", EncodedSize: -1}
+	}
+
+	size := r.response.ContentLength
+
+	if !r.streamed {
+		size = int64(len(r.body))
+	}
+
+	return model.ContentEncoding{Encoding: r.response.Header.Get("Content-Encoding"), EncodedSize: size}
+}
+
+func (r *response) InsecureSkipVerify() bool {
+	return r.insecureSkipVerify
+}
+
+/**
+ * Returns the request quota announced by the response headers, if any;
+ * resets given as epoch seconds are counted from now.
+ */
+func (r *response) RateLimit() (model.RateLimit, bool) {
+	if r.response == nil {
+		return model.RateLimit{Limit: -1, Remaining: -1, Reset: -1}, false
+	}
+	if r.clock == nil {
+		return parseRateLimit(r.response.Header, time.Now())
+	}
+	return parseRateLimit(r.response.Header, r.clock.Now())
+}
+
+/**
+ * Returns the URL of the original request followed by every redirect that
+ * was followed, in order.
+ */
+func (r *response) RedirectChain() []*url.URL {
+	return r.redirectChain
+}
+
 func (r *response) Response() *http.Response {
 	return r.response
 }
+
+/**
+ * Returns the timings of the exchange that produced the response. Static
+ * responses report zeros.
+ */
+func (r *response) Timings() model.Timings {
+	return r.timings
+}
+
+/**
+ * Closes the body of a streamed response that is not handed to the caller.
+ */
+func (r *response) discard() {
+	if r != nil && r.streamed {
+		r.closeBody()
+	}
+}
+
+/**
+ * Closes the body of the response unless it was closed already, so that
+ * error paths running after drain do not close it twice.
+ */
+func (r *response) closeBody() {
+	if !r.closed {
+		r.closed = true
+		r.response.Body.Close()
+	}
+}
+
+/**
+ * Copies the body of a streamed response to sink and closes it.
+ */
+func drain(r *response, sink io.Writer) error {
+	defer r.closeBody()
+	_, err := io.Copy(sink, r.response.Body)
+	return err
+}
+
+/**
+ * Returns a copy with its own body, so callers sharing a response cannot
+ * see each other's changes to it.
+ */
+func (r *response) copy() *response {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	clone.body = append([]byte(nil), r.body...)
+	return &clone
+}
+
+/**
+ * Builds a response that was not received over the network, e.g. a default
+ * payload returned by a model.Fallback.
+ */
+func NewStaticResponse(status int, body []byte) model.Response {
+	return &response{
+		body: body,
+		response: &http.Response{
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Header:        make(http.Header),
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+		},
+	}
+}
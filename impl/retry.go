@@ -0,0 +1,315 @@
+package gorequest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	model "github.com/demianlessa/gorequest/model"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var defaultRetryMultiplier float64 = 2
+
+/**
+ * Retry settings of requests that have none: one more attempt of idempotent
+ * requests with a replayable body after a spurious transport failure (see
+ * spuriousError). Responses are never retried. Setting any retry policy,
+ * e.g. Retry{MaxAttempts: 1}, replaces it.
+ */
+var defaultRetry = model.Retry{
+	MaxAttempts: 2,
+	BaseDelay:   50 * time.Millisecond,
+	RetryIf: func(resp *http.Response, err error) bool {
+		return err != nil && spuriousError(err)
+	},
+}
+
+/**
+ * Runs the attempts of a single request.
+ */
+type retrier struct {
+	budget  *retryBudget
+	clock   model.Clock
+	ctx     context.Context
+	method  string
+	onRetry func(attempt int, resp *response, err error)
+	policy  *model.Retry
+	stats   *retryStats
+}
+
+/**
+ * Calls send until the outcome is not retryable or the attempts, the budget
+ * or the elapsed time are exhausted, sleeping between attempts. send receives
+ * the attempt number starting at 1. The outcome of the last attempt is
+ * returned, unless the context deadline cuts the retries short.
+ */
+func (r *retrier) do(send func(attempt int) (*response, error)) (*response, error) {
+
+	attempts := 1
+
+	if r.policy != nil && r.policy.MaxAttempts > 1 {
+		attempts = r.policy.MaxAttempts
+	}
+
+	if r.budget != nil {
+		r.budget.deposit()
+	}
+
+	start := r.clock.Now()
+
+	// the context deadline is on the system clock, measure it on ours
+	deadline, hasDeadline := r.ctx.Deadline()
+	if hasDeadline {
+		deadline = start.Add(time.Until(deadline))
+	}
+
+	for attempt := 1; ; attempt++ {
+
+		attemptStart := r.clock.Now()
+
+		resp, err := send(attempt)
+
+		if attempt >= attempts || !retryable(r.policy, r.method, resp, err) {
+			return resp, err
+		}
+
+		delay := backoff(r.policy, attempt)
+
+		if requested, ok := requestedDelay(r.policy, resp, r.clock.Now()); ok {
+			delay = requested
+		}
+
+		now := r.clock.Now()
+
+		if r.policy.MaxElapsed > 0 && now.Sub(start)+delay > r.policy.MaxElapsed {
+			r.stats.add(&r.stats.maxElapsedExceeded)
+			return resp, err
+		}
+
+		// assume the next attempt takes as long as this one
+		if hasDeadline && now.Add(delay+now.Sub(attemptStart)).After(deadline) {
+			r.stats.add(&r.stats.deadlineExceeded)
+			resp.discard()
+			return nil, deadlineError(attempt, resp, err)
+		}
+
+		if r.budget != nil && !r.budget.withdraw() {
+			r.stats.add(&r.stats.budgetExhausted)
+			return resp, err
+		}
+
+		r.stats.add(&r.stats.retries)
+
+		if r.onRetry != nil {
+			r.onRetry(attempt, resp, err)
+		}
+
+		resp.discard()
+
+		select {
+		case <-r.clock.After(delay):
+		case <-r.ctx.Done():
+			if r.ctx.Err() == context.DeadlineExceeded {
+				return nil, deadlineError(attempt, resp, err)
+			}
+			return nil, r.ctx.Err()
+		}
+	}
+}
+
+func deadlineError(attempts int, resp *response, err error) error {
+	deadlineErr := &model.RetryDeadlineError{Attempts: attempts, Err: err}
+	if resp != nil {
+		deadlineErr.StatusCode = resp.response.StatusCode
+	}
+	return deadlineErr
+}
+
+func retryable(policy *model.Retry, method string, resp *response, err error) bool {
+
+	if !policy.RetryNonIdempotent && !idempotent(method) {
+		return false
+	}
+
+	condition := policy.RetryIf
+
+	if condition == nil {
+		condition = DefaultRetryIf
+	}
+
+	var httpResp *http.Response
+
+	if resp != nil {
+		httpResp = resp.response
+	}
+
+	return condition(httpResp, err)
+}
+
+/**
+ * The default model.RetryCondition: retries 429, 502, 503 and 504 responses
+ * and transient network errors (timeouts, refused or reset connections,
+ * connections closed mid-response, temporary DNS failures).
+ */
+func DefaultRetryIf(resp *http.Response, err error) bool {
+
+	if err != nil {
+		return transientError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+/**
+ * Reports whether err is a network failure worth another attempt. Policy
+ * decisions (TLS verification, pinning, redirects, limits) never are.
+ */
+func transientError(err error) bool {
+
+	var netErr net.Error
+
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	return spuriousError(err) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+/**
+ * Reports whether err is one of the failures that come and go at scale
+ * without the server being down: reset connections, connections closed
+ * mid-response and temporary DNS failures.
+ */
+func spuriousError(err error) bool {
+
+	var dnsErr *net.DNSError
+
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary
+	}
+
+	// a reused idle connection closed by the server surfaces as EOF
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET)
+}
+
+/**
+ * Returns the delay requested by the server, capped by the policy.
+ */
+func requestedDelay(policy *model.Retry, resp *response, now time.Time) (time.Duration, bool) {
+
+	if policy.MaxRetryAfter < 0 || resp == nil {
+		return 0, false
+	}
+
+	delay, ok := retryAfter(resp.response, now)
+
+	if !ok {
+		return 0, false
+	}
+
+	max := policy.MaxRetryAfter
+
+	if max == 0 {
+		max = defaultMaxRetryAfter
+	}
+
+	if delay > max {
+		delay = max
+	}
+
+	return delay, true
+}
+
+/**
+ * Reports whether req can be sent again: it has no body, or a GetBody.
+ */
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+/**
+ * Reads a body that cannot be replayed into memory and sets GetBody.
+ */
+func bufferBody(req *http.Request) error {
+
+	if replayable(req) {
+		return nil
+	}
+
+	body, err := readAll(req.Body, req.ContentLength)
+	req.Body.Close()
+
+	if err != nil {
+		return err
+	}
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(body))
+
+	return nil
+}
+
+/**
+ * Methods that can be repeated without changing the outcome (RFC 7231).
+ */
+func idempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE", "PROPFIND":
+		return true
+	}
+	return false
+}
+
+/**
+ * Returns the delay following the given attempt.
+ */
+func backoff(policy *model.Retry, attempt int) time.Duration {
+
+	multiplier := policy.Multiplier
+
+	if multiplier < 1 {
+		multiplier = defaultRetryMultiplier
+	}
+
+	delay := float64(policy.BaseDelay) * math.Pow(multiplier, float64(attempt-1))
+
+	if policy.MaxDelay > 0 && delay > float64(policy.MaxDelay) {
+		delay = float64(policy.MaxDelay)
+	}
+
+	if delay > math.MaxInt64 {
+		delay = math.MaxInt64
+	}
+
+	if policy.NoJitter {
+		return time.Duration(delay)
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
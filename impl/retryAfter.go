@@ -0,0 +1,70 @@
+package gorequest
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var defaultMaxRetryAfter = time.Minute
+
+/**
+ * Epoch values (seconds) are told apart from delays by size: no sane delay
+ * is anywhere near 2001-09-09.
+ */
+const epochThreshold = 1000000000
+
+/**
+ * Returns the delay requested by a 429 or 503 response through Retry-After
+ * (delay seconds or HTTP date), RateLimit-Reset or X-RateLimit-Reset (delay
+ * seconds or epoch seconds), in that order of preference.
+ */
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+
+	if resp == nil {
+		return 0, false
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	if value := strings.TrimSpace(resp.Header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return secondsDuration(float64(seconds)), true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return nonNegative(date.Sub(now)), true
+		}
+	}
+
+	for _, name := range []string{"RateLimit-Reset", "X-RateLimit-Reset"} {
+		value := strings.TrimSpace(resp.Header.Get(name))
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			continue
+		}
+		if seconds >= epochThreshold {
+			return nonNegative(time.Unix(0, int64(seconds*float64(time.Second))).Sub(now)), true
+		}
+		return secondsDuration(seconds), true
+	}
+
+	return 0, false
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	if seconds >= float64(math.MaxInt64/time.Second) {
+		return math.MaxInt64
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
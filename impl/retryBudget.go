@@ -0,0 +1,96 @@
+package gorequest
+
+import (
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var defaultRetryBudgetWindow time.Duration = 10 * time.Second
+
+/**
+ * Sliding-window implementation of model.RetryBudget, counting requests
+ * and retries.
+ */
+type retryBudget struct {
+	clock      model.Clock
+	minRetries int
+	mutex      sync.Mutex
+	ratio      float64
+	window     *rollingWindow
+}
+
+func newRetryBudget(budget model.RetryBudget) *retryBudget {
+
+	if budget.Ratio < 0 || budget.MinRetries < 0 || budget.Window < 0 {
+		panic(errors.New("Retry budget settings cannot be negative"))
+	}
+
+	window := budget.Window
+
+	if window == 0 {
+		window = defaultRetryBudgetWindow
+	}
+
+	return &retryBudget{
+		clock:      systemClock{},
+		minRetries: budget.MinRetries,
+		ratio:      budget.Ratio,
+		window:     newRollingWindow(window),
+	}
+}
+
+/**
+ * Records a new request.
+ */
+func (b *retryBudget) deposit() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.window.add(b.clock.Now(), 1, 0)
+}
+
+/**
+ * Records a retry if the budget allows it.
+ */
+func (b *retryBudget) withdraw() bool {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := b.clock.Now()
+	requests, retries := b.window.sums(now)
+
+	if retries >= b.minRetries && float64(retries+1) > b.ratio*float64(requests) {
+		return false
+	}
+
+	b.window.add(now, 0, 1)
+
+	return true
+}
+
+/****************************************************
+ * Retry counters
+ ****************************************************/
+
+type retryStats struct {
+	budgetExhausted    int64
+	deadlineExceeded   int64
+	maxElapsedExceeded int64
+	retries            int64
+}
+
+func (s *retryStats) add(counter *int64) {
+	atomic.AddInt64(counter, 1)
+}
+
+func (s *retryStats) snapshot() model.RetryStats {
+	return model.RetryStats{
+		BudgetExhausted:    atomic.LoadInt64(&s.budgetExhausted),
+		DeadlineExceeded:   atomic.LoadInt64(&s.deadlineExceeded),
+		MaxElapsedExceeded: atomic.LoadInt64(&s.maxElapsedExceeded),
+		Retries:            atomic.LoadInt64(&s.retries),
+	}
+}
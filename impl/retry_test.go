@@ -0,0 +1,491 @@
+package gorequest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := &model.Retry{
+		BaseDelay: 100 * time.Millisecond,
+		MaxDelay:  time.Second,
+		NoJitter:  true,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, backoff(policy, 1), "Should start at the base delay")
+	assert.Equal(t, 400*time.Millisecond, backoff(policy, 3), "Should double the delay")
+	assert.Equal(t, time.Second, backoff(policy, 10), "Should cap the delay")
+
+	policy.NoJitter = false
+
+	for i := 0; i < 100; i++ {
+		delay := backoff(policy, 3)
+		assert.True(t, delay >= 0 && delay <= 400*time.Millisecond, "Should jitter between zero and the delay")
+	}
+}
+
+func TestRetryUntilSuccess(t *testing.T) {
+	var calls int
+	var bodies []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if calls < 3 {
+			resp.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(resp, "OK")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithRetry(model.Retry{MaxAttempts: 5, BaseDelay: time.Millisecond}).Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithMethod("PUT").WithBody(newJsonBody(`{"id":1}`)).Build().Do()
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+	assert.Equal(t, 3, calls, "Should have made three attempts")
+	assert.Equal(t, []string{`{"id":1}`, `{"id":1}`, `{"id":1}`}, bodies, "Should resend the body on every attempt")
+}
+
+func TestDefaultRetryOfSpuriousErrors(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err, "Should listen")
+	defer listener.Close()
+
+	var flaky, down int32
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			req, err := http.ReadRequest(bufio.NewReader(conn))
+			drop := err != nil
+			// /flaky drops its first connection before the response, /down all
+			switch {
+			case drop:
+			case req.URL.Path == "/down":
+				atomic.AddInt32(&down, 1)
+				drop = true
+			case req.URL.Path == "/flaky":
+				drop = atomic.AddInt32(&flaky, 1) == 1
+			}
+			if drop {
+				conn.Close()
+				continue
+			}
+			conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nOK"))
+			conn.Close()
+		}
+	}()
+
+	url := "http://" + listener.Addr().String()
+
+	resp, err := NewRequest(WithUrl(url + "/flaky")).Send()
+
+	assert.Nil(t, err, "Should retry without a retry policy")
+	assert.Equal(t, "OK", string(resp.Body()), "Should return the response of the retry")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&flaky), "Should retry once")
+
+	_, err = NewRequest(WithUrl(url + "/down")).Send()
+
+	assert.NotNil(t, err, "Should fail when the retry fails too")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&down), "Should retry only once")
+
+	_, err = NewRequest(WithUrl(url+"/down"), WithMethod("POST"), WithBody(newJsonBody(`{"id":1}`))).Send()
+
+	assert.NotNil(t, err, "Should not retry non-idempotent requests")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&down), "Should send non-idempotent requests once")
+
+	_, err = NewRequest(WithUrl(url+"/down"), WithRetry(model.Retry{MaxAttempts: 1})).Send()
+
+	assert.NotNil(t, err, "Should not retry when retries are disabled")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&down), "Should send the request once")
+}
+
+func TestRetryExhausted(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithRetry(model.Retry{MaxAttempts: 5, BaseDelay: time.Millisecond}).Build()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithRetry(model.Retry{MaxAttempts: 2}).Build().Do()
+
+	assert.Equal(t, 503, response.Response().StatusCode, "Should return the last response")
+	assert.Equal(t, 2, calls, "Should use the per-request settings")
+}
+
+func TestDefaultRetryIf(t *testing.T) {
+	assert.True(t, DefaultRetryIf(&http.Response{StatusCode: 429}, nil), "Should retry 429")
+	assert.True(t, DefaultRetryIf(&http.Response{StatusCode: 503}, nil), "Should retry 503")
+	assert.False(t, DefaultRetryIf(&http.Response{StatusCode: 500}, nil), "Should not retry 500")
+	assert.False(t, DefaultRetryIf(&http.Response{StatusCode: 404}, nil), "Should not retry 404")
+	assert.True(t, DefaultRetryIf(nil, &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), "Should retry refused connections")
+	assert.True(t, DefaultRetryIf(nil, &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), "Should retry reset connections")
+	assert.True(t, DefaultRetryIf(nil, &url.Error{Op: "Get", Err: io.EOF}), "Should retry connections closed before the response")
+	assert.True(t, DefaultRetryIf(nil, io.ErrUnexpectedEOF), "Should retry truncated responses")
+	assert.True(t, DefaultRetryIf(nil, &net.DNSError{Err: "server misbehaving", IsTemporary: true}), "Should retry temporary DNS failures")
+	assert.False(t, DefaultRetryIf(nil, &net.DNSError{Err: "no such host", IsNotFound: true}), "Should not retry unknown hosts")
+	assert.False(t, DefaultRetryIf(nil, &model.RedirectError{}), "Should not retry policy errors")
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	retry := model.Retry{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	NewRequestBuilder().WithUrl(ts.URL).WithMethod("POST").WithRetry(retry).Build().Do()
+
+	assert.Equal(t, 1, calls, "Should not retry POST by default")
+
+	retry.RetryNonIdempotent = true
+	NewRequestBuilder().WithUrl(ts.URL).WithMethod("POST").WithRetry(retry).Build().Do()
+
+	assert.Equal(t, 4, calls, "Should retry POST when allowed")
+}
+
+func TestCustomRetryIf(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	defer ts.Close()
+
+	retry := model.Retry{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		RetryIf: func(resp *http.Response, err error) bool {
+			return err == nil && resp.StatusCode == http.StatusInternalServerError
+		},
+	}
+
+	NewRequestBuilder().WithUrl(ts.URL).WithRetry(retry).Build().Do()
+
+	assert.Equal(t, 3, calls, "Should retry according to the custom condition")
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := newRetryBudget(model.RetryBudget{Ratio: 0.2, MinRetries: 1})
+
+	assert.True(t, budget.withdraw(), "Should always allow the minimum retries")
+	assert.False(t, budget.withdraw(), "Should refuse retries without requests")
+
+	for i := 0; i < 10; i++ {
+		budget.deposit()
+	}
+
+	assert.True(t, budget.withdraw(), "Should allow 20% of ten requests")
+	assert.False(t, budget.withdraw(), "Should refuse the third retry")
+}
+
+func TestRetryBudgetExhaustedStats(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().
+		WithRetry(model.Retry{MaxAttempts: 5, BaseDelay: time.Millisecond}).
+		WithRetryBudget(model.RetryBudget{Ratio: 0.5}).
+		Build()
+
+	for i := 0; i < 4; i++ {
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+	}
+
+	stats := c.RetryStats()
+
+	assert.Equal(t, int64(2), stats.Retries, "Should retry half as often as requests")
+	assert.Equal(t, int64(4), stats.BudgetExhausted, "Should count every refused retry")
+	assert.Equal(t, 6, calls, "Should have sent four requests and two retries")
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().Build()
+	retry := model.Retry{MaxAttempts: 10, BaseDelay: 20 * time.Millisecond, NoJitter: true, MaxElapsed: 50 * time.Millisecond}
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithRetry(retry).Build().Do()
+
+	assert.Equal(t, 2, calls, "Should stop before the third attempt would end past MaxElapsed")
+	assert.Equal(t, int64(1), c.RetryStats().MaxElapsedExceeded, "Should count the skipped retry")
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	respond := func(status int, name, value string) *http.Response {
+		return &http.Response{StatusCode: status, Header: http.Header{name: []string{value}}}
+	}
+
+	delay, ok := retryAfter(respond(429, "Retry-After", "3"), now)
+	assert.True(t, ok, "Should honor delay seconds")
+	assert.Equal(t, 3*time.Second, delay, "Should equal the requested delay")
+
+	delay, _ = retryAfter(respond(503, "Retry-After", "Wed, 01 Jan 2020 00:00:10 GMT"), now)
+	assert.Equal(t, 10*time.Second, delay, "Should honor an HTTP date")
+
+	delay, _ = retryAfter(respond(429, "X-Ratelimit-Reset", fmt.Sprint(now.Unix()+5)), now)
+	assert.Equal(t, 5*time.Second, delay, "Should honor an epoch reset time")
+
+	delay, _ = retryAfter(respond(429, "Ratelimit-Reset", "2"), now)
+	assert.Equal(t, 2*time.Second, delay, "Should honor a reset delay")
+
+	_, ok = retryAfter(respond(502, "Retry-After", "3"), now)
+	assert.False(t, ok, "Should ignore Retry-After on other statuses")
+
+	_, ok = retryAfter(respond(429, "Retry-After", "soon"), now)
+	assert.False(t, ok, "Should ignore malformed values")
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			resp.Header().Set("Retry-After", "3600")
+			resp.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+
+	defer ts.Close()
+
+	policy := model.Retry{MaxAttempts: 2, BaseDelay: time.Hour, MaxRetryAfter: 50 * time.Millisecond}
+	c := NewClientBuilder().WithRetry(policy).Build()
+
+	start := time.Now()
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+	elapsed := time.Since(start)
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+	assert.True(t, elapsed >= 50*time.Millisecond && elapsed < time.Second, "Should wait for the capped server delay instead of the backoff")
+}
+
+func TestFallback(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	var received error
+
+	response := NewRequestBuilder().WithUrl(url).WithRetry(model.Retry{MaxAttempts: 2, BaseDelay: time.Millisecond}).WithFallback(func(err error) (model.Response, error) {
+		received = err
+		return NewStaticResponse(http.StatusOK, []byte("cached")), nil
+	}).Build().Do()
+
+	assert.True(t, errors.Is(received, syscall.ECONNREFUSED), "Should receive the last error")
+	assert.Equal(t, "cached", string(response.Body()), "Should return the fallback payload")
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+}
+
+func TestFallbackError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	defer func() {
+		err := recover()
+
+		assert.Equal(t, "Service unavailable", err.(error).Error(), "Should fail with the fallback error")
+	}()
+
+	NewRequestBuilder().WithUrl(url).WithFallback(func(err error) (model.Response, error) {
+		return nil, errors.New("Service unavailable")
+	}).Build().Do()
+}
+
+func TestRetryBodyNotReplayable(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	req, _ := http.NewRequest("POST", ts.URL, ioutil.NopCloser(strings.NewReader("payload")))
+	policy := &model.Retry{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryNonIdempotent: true}
+
+	defer func() {
+		err, ok := recover().(*model.BodyNotReplayableError)
+
+		assert.True(t, ok, "Should have panicked with a body not replayable error")
+		assert.Equal(t, "Cannot resend POST "+ts.URL+": request body cannot be replayed", err.Error(), "Should equal error message")
+		assert.Equal(t, 1, calls, "Should not resend the request")
+	}()
+
+	newRequest(req, getDefaultClient(), requestOptions{retry: policy}).Do()
+}
+
+func TestRetryBufferBody(t *testing.T) {
+	var bodies []string
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 2 {
+			resp.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+
+	defer ts.Close()
+
+	req, _ := http.NewRequest("POST", ts.URL, ioutil.NopCloser(strings.NewReader("payload")))
+	policy := &model.Retry{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryNonIdempotent: true, BufferBody: true}
+
+	response := newRequest(req, getDefaultClient(), requestOptions{retry: policy}).Do()
+
+	assert.Equal(t, 200, response.Response().StatusCode, "Should equal HTTP Status 200 (OK)")
+	assert.Equal(t, []string{"payload", "payload"}, bodies, "Should resend the buffered body")
+}
+
+func TestRetryDeadlineTruncation(t *testing.T) {
+	var calls int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		calls++
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	c := NewClientBuilder().WithRetry(model.Retry{MaxAttempts: 5, BaseDelay: 60 * time.Millisecond, NoJitter: true}).Build()
+
+	start := time.Now()
+
+	defer func() {
+		err, ok := recover().(*model.RetryDeadlineError)
+
+		assert.True(t, ok, "Should have panicked with a retry deadline error")
+		assert.Equal(t, "Deadline reached after 2 attempts: last status 503", err.Error(), "Should equal error message")
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), "Should match context.DeadlineExceeded")
+		assert.Equal(t, 2, calls, "Should skip the attempt that cannot finish in time")
+		assert.True(t, time.Since(start) < 100*time.Millisecond, "Should give up before the deadline")
+		assert.Equal(t, int64(1), c.RetryStats().DeadlineExceeded, "Should count the skipped retry")
+	}()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithContext(ctx).Build().Do()
+}
+
+func TestRetryDeadlineOnFakeClock(t *testing.T) {
+	var calls int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// a clock far behind the system clock must not hide the deadline
+	clock := requestmock.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)).AutoAdvance()
+	c := NewClientBuilder().WithClock(clock).WithRetry(model.Retry{MaxAttempts: 3, BaseDelay: 10 * time.Second, NoJitter: true}).Build()
+
+	_, err := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithContext(ctx).Build().Send()
+
+	assert.IsType(t, &model.RetryDeadlineError{}, err, "Should give up when the delay ends past the deadline")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "Should not retry past the deadline")
+}
+
+func TestRetryWithFakeClock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	defer ts.Close()
+
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := requestmock.NewClock(start).AutoAdvance()
+
+	c := NewClientBuilder().
+		WithClock(clock).
+		WithRetry(model.Retry{MaxAttempts: 4, BaseDelay: time.Minute, NoJitter: true}).
+		Build()
+
+	began := time.Now()
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, http.StatusServiceUnavailable, response.Response().StatusCode, "Should exhaust the attempts")
+	assert.Equal(t, 7*time.Minute, clock.Now().Sub(start), "Should wait 1, 2 and 4 minutes on the clock")
+	assert.True(t, time.Since(began) < 5*time.Second, "Should not sleep")
+	assert.Equal(t, int64(3), c.RetryStats().Retries, "Should count retries")
+}
+
+func TestCircuitBreakerWithFakeClock(t *testing.T) {
+	clock := requestmock.NewClock(time.Now())
+
+	c := NewClientBuilder().
+		WithClock(clock).
+		WithCircuitBreaker(model.CircuitBreaker{FailureRate: 0.5, MinRequests: 1, OpenTimeout: time.Minute}).
+		Build()
+
+	circuit := asClient(c).breakers.circuitFor("api.internal")
+
+	done, _ := circuit.allow()
+	done(exchangeFailed)
+
+	assert.Equal(t, model.CircuitOpen, c.CircuitStates()["api.internal"], "Should open the circuit")
+
+	clock.Advance(time.Minute)
+
+	assert.Equal(t, model.CircuitHalfOpen, c.CircuitStates()["api.internal"], "Should half-open once the timeout passed on the clock")
+}
@@ -0,0 +1,65 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Number of buckets a rolling window is divided into.
+ */
+const rollingWindowBuckets = 10
+
+type rollingWindowBucket struct {
+	counts [2]int
+	start  time.Time
+}
+
+/**
+ * Two counters summed over a sliding window, kept in buckets covering a
+ * tenth of the window each. Not safe for concurrent use.
+ */
+type rollingWindow struct {
+	buckets [rollingWindowBuckets]rollingWindowBucket
+	span    time.Duration
+}
+
+func newRollingWindow(window time.Duration) *rollingWindow {
+	span := window / rollingWindowBuckets
+	if span <= 0 {
+		span = 1
+	}
+	return &rollingWindow{
+		span: span,
+	}
+}
+
+func (w *rollingWindow) add(now time.Time, first, second int) {
+
+	start := now.Truncate(w.span)
+	bucket := &w.buckets[(start.UnixNano()/int64(w.span))%rollingWindowBuckets]
+
+	if !bucket.start.Equal(start) {
+		*bucket = rollingWindowBucket{start: start}
+	}
+
+	bucket.counts[0] += first
+	bucket.counts[1] += second
+}
+
+func (w *rollingWindow) sums(now time.Time) (int, int) {
+
+	first, second := 0, 0
+
+	for i := range w.buckets {
+		if now.Sub(w.buckets[i].start) < w.span*rollingWindowBuckets {
+			first += w.buckets[i].counts[0]
+			second += w.buckets[i].counts[1]
+		}
+	}
+
+	return first, second
+}
+
+func (w *rollingWindow) reset() {
+	w.buckets = [rollingWindowBuckets]rollingWindowBucket{}
+}
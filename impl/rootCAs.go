@@ -0,0 +1,77 @@
+package gorequest
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+/**
+ * File extensions considered when loading certificates from a directory.
+ */
+var rootCAExtensions = []string{".pem", ".crt", ".cer"}
+
+/**
+ * Returns the system trust store extended with the given PEM bundles. Each
+ * bundle must contain at least one certificate.
+ */
+func newRootCAPool(bundles map[string][]byte) *x509.CertPool {
+
+	pool, err := x509.SystemCertPool()
+
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	for source, pem := range bundles {
+		if !pool.AppendCertsFromPEM(pem) {
+			panic(fmt.Errorf("No PEM encoded certificates found in %s", source))
+		}
+	}
+
+	return pool
+}
+
+func readRootCAFile(path string) []byte {
+
+	pem, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return pem
+}
+
+func readRootCADirectory(dir string) map[string][]byte {
+
+	entries, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		panic(err)
+	}
+
+	bundles := make(map[string][]byte)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !hasRootCAExtension(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		bundles[path] = readRootCAFile(path)
+	}
+
+	return bundles
+}
+
+func hasRootCAExtension(name string) bool {
+	extension := strings.ToLower(filepath.Ext(name))
+	for _, candidate := range rootCAExtensions {
+		if extension == candidate {
+			return true
+		}
+	}
+	return false
+}
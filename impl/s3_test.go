@@ -0,0 +1,124 @@
+package gorequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	s3 "github.com/demianlessa/gorequest/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+ * An object store keeping the parts of a single multipart upload in memory.
+ * The first PUT of flakyPart fails with 503; completeError makes completion
+ * fail with status 200, as S3 can.
+ */
+type s3Server struct {
+	aborted       bool
+	completeError bool
+	flakyPart     int
+	mutex         sync.Mutex
+	object        []byte
+	parts         map[int][]byte
+	unsigned      int
+}
+
+func (s *s3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		s.unsigned++
+	}
+
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == "POST" && query.Has("uploads"):
+		s.parts = map[int][]byte{}
+		fmt.Fprint(w, `<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>db.tar</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`)
+	case r.Method == "PUT" && query.Get("uploadId") == "upload-1":
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		if number == s.flakyPart {
+			s.flakyPart = 0
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		s.parts[number], _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, number))
+	case r.Method == "POST" && query.Get("uploadId") == "upload-1":
+		var complete struct {
+			Parts []struct {
+				ETag       string
+				PartNumber int
+			} `xml:"Part"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		xml.Unmarshal(body, &complete)
+		if s.completeError {
+			fmt.Fprint(w, `<Error><Code>InternalError</Code><Message>We encountered an internal error.</Message></Error>`)
+			return
+		}
+		s.object = nil
+		for i, part := range complete.Parts {
+			if part.PartNumber != i+1 || part.ETag != fmt.Sprintf(`"etag-%d"`, i+1) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `<Error><Code>InvalidPartOrder</Code></Error>`)
+				return
+			}
+			s.object = append(s.object, s.parts[part.PartNumber]...)
+		}
+		fmt.Fprint(w, `<CompleteMultipartUploadResult><ETag>"object-etag"</ETag></CompleteMultipartUploadResult>`)
+	case r.Method == "DELETE" && query.Get("uploadId") == "upload-1":
+		s.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestS3MultipartUpload(t *testing.T) {
+	state := &s3Server{flakyPart: 2}
+
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	c := NewClient(WithHooks(SigV4Hooks(model.SigV4{AccessKeyID: "AKID", Region: "us-east-1", SecretAccessKey: "secret", Service: "s3"})))
+
+	uploader := s3.NewUploader(func() model.RequestBuilder { return NewRequestBuilder().WithClient(c) })
+	uploader.PartSize = 10
+	uploader.Retry = model.Retry{BaseDelay: time.Millisecond, MaxAttempts: 2}
+
+	content := []byte(strings.Repeat("0123456789", 9) + "012")
+
+	etag, err := uploader.Upload(context.Background(), server.URL+"/bucket/db.tar", bytes.NewReader(content), int64(len(content)), "application/x-tar")
+
+	assert.Nil(t, err, "Should upload the object")
+	assert.Equal(t, `"object-etag"`, etag, "Should return the ETag of the object")
+	assert.Equal(t, content, state.object, "Should upload every part, in order")
+	assert.Len(t, state.parts, 10, "Should upload parts of the size asked for")
+	assert.Equal(t, 0, state.unsigned, "Should send signed requests")
+	assert.False(t, state.aborted, "Should not abort successful uploads")
+
+	state.completeError = true
+
+	_, err = uploader.Upload(context.Background(), server.URL+"/bucket/db.tar", bytes.NewReader(content), int64(len(content)), "application/x-tar")
+
+	var failure *s3.Error
+
+	assert.True(t, errors.As(err, &failure), "Should fail with the error of the store, got %v", err)
+	assert.Equal(t, "InternalError", failure.Code, "Should read errors sent with status 200")
+	assert.True(t, state.aborted, "Should abort failed uploads")
+}
@@ -0,0 +1,233 @@
+package gorequest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+/**
+ * Headers left unsigned: set or changed by the transport or by proxies.
+ */
+var unsignedHeaders = map[string]bool{
+	"authorization":     true,
+	"connection":        true,
+	"expect":            true,
+	"transfer-encoding": true,
+	"user-agent":        true,
+	"x-amzn-trace-id":   true,
+}
+
+/**
+ * Returns hooks signing every attempt of a request with AWS Signature
+ * Version 4, for ClientBuilder.WithHooks. They sign the request as it is
+ * sent, base URL resolved, so they are best registered after hooks
+ * changing requests. Bodies are hashed into X-Amz-Content-Sha256, or sent
+ * as UNSIGNED-PAYLOAD when they cannot be reopened. Panics without
+ * credentials or scope.
+ */
+func SigV4Hooks(credentials model.SigV4) model.Hooks {
+
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" || credentials.Region == "" || credentials.Service == "" {
+		panic(errors.New("SigV4 signing requires credentials, a region and a service"))
+	}
+
+	if credentials.Clock == nil {
+		credentials.Clock = systemClock{}
+	}
+
+	return model.Hooks{
+		OnBeforeRequest: func(req *http.Request) error {
+			return signV4(req, credentials)
+		},
+	}
+}
+
+func signV4(req *http.Request, credentials model.SigV4) error {
+
+	now := credentials.Clock.Now().UTC()
+	date := now.Format("20060102")
+
+	payload, err := payloadHash(req)
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers, signed := canonicalHeaders(req)
+
+	canonical := strings.Join([]string{
+		req.Method,
+		canonicalPath(req, credentials.Service),
+		canonicalQuery(req),
+		headers,
+		signed,
+		payload,
+	}, "\n")
+
+	scope := date + "/" + credentials.Region + "/" + credentials.Service + "/aws4_request"
+	digest := sha256.Sum256([]byte(canonical))
+
+	toSign := "AWS4-HMAC-SHA256\n" + now.Format("20060102T150405Z") + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	for _, part := range []string{credentials.Region, credentials.Service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+credentials.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+hex.EncodeToString(hmacSHA256(key, toSign)))
+
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func payloadHash(req *http.Request) (string, error) {
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return emptySHA256, nil
+	}
+
+	if req.GetBody == nil {
+		return "UNSIGNED-PAYLOAD", nil
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return "", err
+	}
+
+	defer body.Close()
+
+	digest := sha256.New()
+
+	if _, err := io.Copy(digest, body); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+/**
+ * Returns the canonical headers block and the list of signed headers.
+ */
+func canonicalHeaders(req *http.Request) (string, string) {
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	values := map[string]string{"host": host}
+
+	for name, entries := range req.Header {
+		lower := strings.ToLower(name)
+		if unsignedHeaders[lower] {
+			continue
+		}
+		trimmed := make([]string, len(entries))
+		for i, entry := range entries {
+			trimmed[i] = strings.Join(strings.Fields(entry), " ")
+		}
+		values[lower] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var block strings.Builder
+	for _, name := range names {
+		block.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	return block.String(), strings.Join(names, ";")
+}
+
+/**
+ * Encodes each segment of the path once for S3, twice for other services.
+ */
+func canonicalPath(req *http.Request, service string) string {
+
+	path := req.URL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+		if service != "s3" {
+			segments[i] = awsEscape(segments[i])
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(req *http.Request) string {
+
+	var pairs [][2]string
+
+	for key, values := range req.URL.Query() {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{awsEscape(key), awsEscape(value)})
+		}
+	}
+
+	// sorted by key, then by value
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1]
+	})
+
+	joined := make([]string, len(pairs))
+	for i, pair := range pairs {
+		joined[i] = pair[0] + "=" + pair[1]
+	}
+
+	return strings.Join(joined, "&")
+}
+
+/**
+ * Percent-encodes everything but the unreserved characters of RFC 3986.
+ */
+func awsEscape(s string) string {
+
+	var escaped strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			escaped.WriteByte(c)
+			continue
+		}
+		escaped.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+
+	return escaped.String()
+}
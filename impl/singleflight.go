@@ -0,0 +1,93 @@
+package gorequest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/**
+ * Coalesces concurrent identical requests into a single upstream call whose
+ * outcome is shared by every caller.
+ */
+type flightGroup struct {
+	flights map[string]*flight
+	mutex   sync.Mutex
+}
+
+type flight struct {
+	done chan struct{}
+	err  error
+	resp *response
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[string]*flight)}
+}
+
+/**
+ * Runs fn unless a call with the same key is in flight, in which case its
+ * outcome is awaited instead. Callers sharing an outcome get their own copy
+ * of the body.
+ */
+func (g *flightGroup) do(key string, fn func() (*response, error)) (*response, error) {
+
+	g.mutex.Lock()
+
+	if f, ok := g.flights[key]; ok {
+		g.mutex.Unlock()
+		<-f.done
+		return f.resp.copy(), f.err
+	}
+
+	f := &flight{done: make(chan struct{})}
+	g.flights[key] = f
+
+	g.mutex.Unlock()
+
+	finish := func() {
+		g.mutex.Lock()
+		delete(g.flights, key)
+		g.mutex.Unlock()
+		close(f.done)
+	}
+
+	defer func() {
+		// waiters must not hang when fn panics
+		if p := recover(); p != nil {
+			f.err = panicError(p)
+			finish()
+			panic(p)
+		}
+	}()
+
+	f.resp, f.err = fn()
+	finish()
+
+	return f.resp.copy(), f.err
+}
+
+/**
+ * Identifies requests that would get the same response: the URL (normalized,
+ * with its query sorted if sortQuery is set), headers and TLS settings.
+ */
+func flightKey(req *http.Request, variant tlsVariant, sortQuery bool) string {
+
+	var key strings.Builder
+
+	fmt.Fprintf(&key, "%s %s %t %s\n", req.Method, normalizeURL(req.URL, sortQuery), variant.insecureSkipVerify, variant.serverName)
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(&key, "%s: %q\n", name, req.Header[name])
+	}
+
+	return key.String()
+}
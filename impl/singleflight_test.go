@@ -0,0 +1,193 @@
+package gorequest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicationCoalescesIdenticalGets(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		fmt.Fprint(resp, "shared")
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithDeduplication().Build()
+
+	var wg sync.WaitGroup
+	bodies := make([][]byte, 5)
+
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			bodies[i] = NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do().Body()
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "Should call upstream once")
+	for _, body := range bodies {
+		assert.Equal(t, "shared", string(body), "Should share the response")
+	}
+
+	bodies[0][0] = 'S'
+	assert.Equal(t, "shared", string(bodies[1]), "Should give every caller its own body")
+}
+
+func TestDeduplicationKeepsDistinctRequestsApart(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		fmt.Fprint(resp, req.Header.Get("Authorization"))
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithDeduplication().Build()
+
+	var wg sync.WaitGroup
+	bodies := make([]string, 2)
+
+	for i, token := range []string{"alice", "bob"} {
+		wg.Add(1)
+		go func(i int, token string) {
+			defer wg.Done()
+			bodies[i] = string(NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithBearerAuth(token).Build().Do().Body())
+		}(i, token)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "Should not coalesce requests with different credentials")
+	assert.Equal(t, []string{"Bearer alice", "Bearer bob"}, bodies, "Should keep responses apart")
+}
+
+func TestMemoization(t *testing.T) {
+	mock := requestmock.New()
+
+	config := mock.On("GET", "/config").Reply(http.StatusOK, "config").ReplyHeader("Vary", "Accept-Language")
+	other := mock.On("GET", "/other").Reply(http.StatusOK, "other")
+
+	clock := requestmock.NewClock(time.Now())
+	c := NewClientBuilder().WithTransport(mock).WithClock(clock).WithMemoizationLimit(2).Build()
+
+	get := func(path string, language string) string {
+		return string(NewRequestBuilder().
+			WithUrl("https://api.example.com" + path).
+			WithHeader("Accept-Language", language).
+			WithCacheTTL(time.Minute).
+			WithClient(c).
+			Build().
+			Do().
+			Body())
+	}
+
+	assert.Equal(t, "config", get("/config", "en"), "Should reply")
+	assert.Equal(t, "config", get("/config", "en"), "Should reply from memory")
+	assert.Equal(t, 1, config.Calls(), "Should not send identical requests again")
+
+	get("/config", "fr")
+
+	assert.Equal(t, 2, config.Calls(), "Should key on the headers the response varies on")
+
+	clock.Advance(time.Minute)
+	get("/config", "en")
+
+	assert.Equal(t, 3, config.Calls(), "Should expire entries after their TTL")
+
+	get("/other", "en")
+	get("/config", "fr")
+
+	assert.Equal(t, 1, other.Calls(), "Should memoize other URLs")
+	assert.Equal(t, 4, config.Calls(), "Should evict the least recently used entries")
+
+	NewRequestBuilder().WithUrl("https://api.example.com/other").WithClient(c).Build().Do()
+
+	assert.Equal(t, 2, other.Calls(), "Should not memoize requests without a TTL")
+}
+
+func TestMemoizationCredentials(t *testing.T) {
+	mock := requestmock.New()
+
+	alice := mock.On("GET", "/profile").MatchHeader("Authorization", "Bearer alice").Reply(http.StatusOK, "alice")
+	bob := mock.On("GET", "/profile").MatchHeader("Authorization", "Bearer bob").Reply(http.StatusOK, "bob")
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	get := func(token string) string {
+		return string(NewRequestBuilder().
+			WithUrl("https://api.example.com/profile").
+			WithBearerAuth(token).
+			WithCacheTTL(time.Minute).
+			WithClient(c).
+			Build().
+			Do().
+			Body())
+	}
+
+	assert.Equal(t, "alice", get("alice"), "Should reply")
+	assert.Equal(t, "alice", get("alice"), "Should reply from memory")
+	assert.Equal(t, "bob", get("bob"), "Should not serve the response of other credentials")
+	assert.Equal(t, "bob", get("bob"), "Should memoize every set of credentials")
+	assert.Equal(t, 1, alice.Calls(), "Should send a request per set of credentials")
+	assert.Equal(t, 1, bob.Calls(), "Should send a request per set of credentials")
+
+	url := "https://api.example.com/profile"
+
+	assert.NotEqual(t, memoKey(url, nil, http.Header{}, tlsVariant{}), memoKey(url, nil, http.Header{}, tlsVariant{insecureSkipVerify: true}), "Should key on the TLS settings")
+	assert.NotEqual(t, memoKey(url, nil, http.Header{}, tlsVariant{}), memoKey(url, nil, http.Header{}, tlsVariant{serverName: "origin.example.com"}), "Should key on the TLS settings")
+}
+
+func TestMemoizationCookies(t *testing.T) {
+	mock := requestmock.New()
+
+	alice := mock.On("GET", "/profile").MatchHeader("Cookie", "session=alice").Reply(http.StatusOK, "alice")
+	bob := mock.On("GET", "/profile").MatchHeader("Cookie", "session=bob").Reply(http.StatusOK, "bob")
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	get := func(session string) string {
+		return string(NewRequestBuilder().
+			WithUrl("https://api.example.com/profile").
+			WithHeader("Cookie", "session="+session).
+			WithCacheTTL(time.Minute).
+			WithClient(c).
+			Build().
+			Do().
+			Body())
+	}
+
+	assert.Equal(t, "alice", get("alice"), "Should reply")
+	assert.Equal(t, "bob", get("bob"), "Should not serve the response of another session")
+	assert.Equal(t, "alice", get("alice"), "Should reply from memory")
+	assert.Equal(t, 1, alice.Calls(), "Should send a request per session")
+	assert.Equal(t, 1, bob.Calls(), "Should send a request per session")
+
+	url := "https://api.example.com/profile"
+
+	for _, name := range []string{"Proxy-Authorization", "X-Api-Key"} {
+		assert.NotEqual(t, memoKey(url, nil, http.Header{}, tlsVariant{}), memoKey(url, nil, http.Header{name: {"secret"}}, tlsVariant{}), "Should key on "+name)
+	}
+}
@@ -0,0 +1,75 @@
+package gorequest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	soap "github.com/demianlessa/gorequest/soap"
+	"github.com/stretchr/testify/assert"
+)
+
+type getUser struct {
+	XMLName xml.Name `xml:"urn:users GetUser"`
+	ID      int      `xml:"id"`
+}
+
+type getUserResponse struct {
+	Name string `xml:"name"`
+}
+
+func TestSOAP(t *testing.T) {
+	mock := requestmock.New()
+
+	var received *http.Request
+	var envelope string
+
+	mock.On("POST", "/v11").Match(func(req *http.Request, body []byte) bool {
+		received, envelope = req, string(body)
+		return true
+	}).Reply(http.StatusOK, `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body><GetUserResponse xmlns="urn:users"><name>alice</name></GetUserResponse></soap:Body>
+</soap:Envelope>`)
+	mock.On("POST", "/v12").Match(func(req *http.Request, body []byte) bool {
+		received = req
+		return true
+	}).Reply(http.StatusInternalServerError, `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope">
+  <env:Body><env:Fault>
+    <env:Code><env:Value>env:Sender</env:Value></env:Code>
+    <env:Reason><env:Text xml:lang="en">Unknown user</env:Text></env:Reason>
+    <env:Detail><id>1</id></env:Detail>
+  </env:Fault></env:Body>
+</env:Envelope>`)
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	body, err := soap.NewBody(soap.SOAP11, "urn:GetUser", getUser{ID: 1})
+
+	assert.Nil(t, err, "Should build the envelope")
+
+	resp := soap.Prepare(NewRequestBuilder().WithUrl("https://api.example.com/v11").WithClient(c), body).Build().Do()
+
+	assert.Equal(t, `"urn:GetUser"`, received.Header.Get("SOAPAction"), "Should set the SOAP 1.1 action")
+	assert.Equal(t, "text/xml; charset=utf-8", received.Header.Get("Content-Type"), "Should set the SOAP 1.1 content type")
+	assert.True(t, strings.Contains(envelope, `<soap:Body><GetUser xmlns="urn:users"><id>1</id></GetUser></soap:Body>`), "Should wrap the payload")
+
+	var user getUserResponse
+
+	assert.Nil(t, soap.Decode(resp, &user), "Should decode the response")
+	assert.Equal(t, "alice", user.Name, "Should decode the response")
+
+	body, _ = soap.NewBody(soap.SOAP12, "urn:GetUser", getUser{ID: 1})
+	resp = soap.Prepare(NewRequestBuilder().WithUrl("https://api.example.com/v12").WithClient(c), body).Build().Do()
+
+	assert.Equal(t, "", received.Header.Get("SOAPAction"), "Should not set SOAPAction with SOAP 1.2")
+	assert.Equal(t, `application/soap+xml; charset=utf-8; action="urn:GetUser"`, received.Header.Get("Content-Type"), "Should carry the action in the content type")
+
+	fault, ok := soap.Decode(resp, &user).(*soap.Fault)
+
+	assert.True(t, ok, "Should return the fault")
+	assert.Equal(t, soap.Fault{Code: "env:Sender", Detail: "<id>1</id>", Reason: "Unknown user", Version: soap.SOAP12}, *fault, "Should parse the fault")
+	assert.Equal(t, "SOAP fault env:Sender: Unknown user", fault.Error(), "Should equal error message")
+}
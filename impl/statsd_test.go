@@ -0,0 +1,62 @@
+package gorequest
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	statsd "github.com/demianlessa/gorequest/statsd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsdCollector(t *testing.T) {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err, "Should listen")
+	defer server.Close()
+
+	collector, err := statsd.NewCollector(server.LocalAddr().String(), "myapp")
+	assert.Nil(t, err, "Should connect")
+	defer collector.Close()
+
+	receive := func() string {
+		buf := make([]byte, 1024)
+		server.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := server.ReadFrom(buf)
+		assert.Nil(t, err, "Should receive a datagram")
+		return string(buf[:n])
+	}
+
+	collector.AddCounter("requests", 1, map[string]string{"method": "GET", "host": "api.example.com", "status": "200"})
+
+	assert.Equal(t, "myapp.requests:1|c|#host:api.example.com,method:GET,status:200", receive(), "Should send counters with sorted tags")
+
+	collector.ObserveTimer("request_duration", 1500*time.Microsecond, map[string]string{"host": "api.example.com"})
+
+	assert.Equal(t, "myapp.request_duration:1.5|ms|#host:api.example.com", receive(), "Should send timers in milliseconds")
+
+	collector.SetGauge("in_flight", 3, nil)
+
+	assert.Equal(t, "myapp.in_flight:3|g", receive(), "Should send gauges without tags")
+
+	mock := requestmock.New()
+	mock.On("GET", "/users").Reply(http.StatusOK, "[]")
+
+	NewRequest(WithClient(NewClientBuilder().WithTransport(mock).WithMetrics(collector).Build()), WithUrl("https://api.example.com/users")).Send()
+
+	var lines []string
+	for line := receive(); ; line = receive() {
+		lines = append(lines, line)
+		if line == "" || strings.HasPrefix(line, "myapp.requests:") {
+			break
+		}
+	}
+
+	assert.Contains(t, lines, "myapp.requests:1|c|#host:api.example.com,method:GET,status:200", "Should receive the metrics of clients")
+
+	_, err = statsd.NewCollector("no port", "")
+
+	assert.NotNil(t, err, "Should return dial errors")
+}
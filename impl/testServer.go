@@ -0,0 +1,126 @@
+package gorequest
+
+import (
+	"io/ioutil"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+/****************************************************
+ * model.TestServer implementation
+ ****************************************************/
+
+type testServer struct {
+	client   model.Client
+	latency  time.Duration
+	mutex    sync.Mutex
+	requests []model.CapturedRequest
+	routes   map[string]model.TestResponse
+	server   *httptest.Server
+}
+
+/**
+ * Starts a TestServer answering routes (see TestServer.Handle). The server
+ * listens on a local port until closed.
+ */
+func NewTestServer(routes map[string]model.TestResponse) model.TestServer {
+
+	s := &testServer{
+		routes: make(map[string]model.TestResponse, len(routes)),
+	}
+
+	for route, response := range routes {
+		s.routes[route] = response
+	}
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	s.client = newClient(s.server.Client())
+
+	return s
+}
+
+func (s *testServer) Close() {
+	s.server.Close()
+}
+
+func (s *testServer) Handle(route string, response model.TestResponse) model.TestServer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.routes[route] = response
+	return s
+}
+
+func (s *testServer) Request(path string) model.RequestBuilder {
+	return NewRequestBuilder().WithUrl(s.server.URL + path).WithClient(s.client)
+}
+
+func (s *testServer) Requests() []model.CapturedRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]model.CapturedRequest(nil), s.requests...)
+}
+
+func (s *testServer) URL() string {
+	return s.server.URL
+}
+
+func (s *testServer) WithLatency(latency time.Duration) model.TestServer {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.latency = latency
+	return s
+}
+
+func (s *testServer) serve(resp http.ResponseWriter, req *http.Request) {
+
+	body, _ := ioutil.ReadAll(req.Body)
+
+	s.mutex.Lock()
+
+	s.requests = append(s.requests, model.CapturedRequest{
+		Body:       body,
+		Header:     req.Header.Clone(),
+		Method:     req.Method,
+		ReceivedAt: time.Now(),
+		URL:        req.URL.RequestURI(),
+	})
+
+	response, ok := s.routes[req.Method+" "+req.URL.Path]
+
+	if !ok {
+		response, ok = s.routes[req.URL.Path]
+	}
+
+	delay := s.latency + response.Delay
+
+	s.mutex.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	if !ok {
+		http.NotFound(resp, req)
+		return
+	}
+
+	for name, values := range response.Header {
+		resp.Header()[name] = values
+	}
+
+	status := response.Status
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	resp.WriteHeader(status)
+	resp.Write([]byte(response.Body))
+}
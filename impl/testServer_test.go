@@ -0,0 +1,53 @@
+package gorequest
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestServer(t *testing.T) {
+	server := NewTestServer(map[string]model.TestResponse{
+		"GET /users/1": {Body: `{"id":1}`, Header: http.Header{"Content-Type": {"application/json"}}},
+		"/health":      {Status: http.StatusNoContent},
+	})
+
+	defer server.Close()
+
+	response := server.Request("/users/1?fields=id").Build().Do()
+
+	assert.Equal(t, `{"id":1}`, string(response.Body()), "Should equal body")
+	assert.Equal(t, "application/json", response.Response().Header.Get("Content-Type"), "Should equal header")
+
+	response = server.Request("/health").WithMethod("HEAD").Build().Do()
+
+	assert.Equal(t, http.StatusNoContent, response.Response().StatusCode, "Should match any method")
+
+	response = server.Request("/users/1").WithMethod("POST").WithBody(newJsonBody(`{"name":"alice"}`)).Build().Do()
+
+	assert.Equal(t, http.StatusNotFound, response.Response().StatusCode, "Should not match other methods")
+
+	requests := server.Requests()
+
+	assert.Equal(t, 3, len(requests), "Should capture requests")
+	assert.Equal(t, "/users/1?fields=id", requests[0].URL, "Should capture the URL")
+	assert.Equal(t, "POST", requests[2].Method, "Should capture the method")
+	assert.Equal(t, `{"name":"alice"}`, string(requests[2].Body), "Should capture the body")
+}
+
+func TestTestServerLatency(t *testing.T) {
+	server := NewTestServer(nil).
+		Handle("/slow", model.TestResponse{Body: "OK", Delay: 20 * time.Millisecond}).
+		WithLatency(10 * time.Millisecond)
+
+	defer server.Close()
+
+	start := time.Now()
+
+	server.Request("/slow").Build().Do()
+
+	assert.True(t, time.Since(start) >= 30*time.Millisecond, "Should add latency and route delay")
+}
@@ -0,0 +1,306 @@
+package gorequest
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	bucket := newTokenBucket(systemClock{}, 100, 10)
+
+	assert.Equal(t, time.Duration(0), bucket.reserve(10), "Should serve the burst immediately")
+
+	delay := bucket.reserve(10)
+
+	assert.True(t, delay > 90*time.Millisecond && delay <= 100*time.Millisecond, "Should wait for the refill")
+}
+
+func TestDownloadRate(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 3000)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Write(payload)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithDownloadRate(10000, 1000).Build()
+
+	start := time.Now()
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, payload, response.Body(), "Should equal body")
+	assert.True(t, time.Since(start) >= 150*time.Millisecond, "Should take at least 200ms minus the burst")
+}
+
+func TestUploadRate(t *testing.T) {
+	var received []byte
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		received, _ = ioutil.ReadAll(req.Body)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithUploadRate(10000, 1000).Build()
+	customer := newTestCustomer(1, "Throttled", "Upload")
+
+	start := time.Now()
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithMethod("POST").WithBody(newJsonBody(customer)).Build().Do()
+
+	assert.Equal(t, `{"id":1,"firstName":"Throttled","lastName":"Upload"}`, string(received), "Should receive the whole body")
+	assert.True(t, time.Since(start) < time.Second, "Should not throttle a body smaller than the burst")
+
+	payload := strings.Repeat("x", 3000)
+	clock := requestmock.NewClock(time.Now()).AutoAdvance()
+	c = NewClientBuilder().WithClock(clock).WithUploadRate(1000, 1000).Build()
+
+	start = clock.Now()
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithMethod("POST").WithBody(newJsonBody(payload)).Build().Do()
+
+	assert.Equal(t, payload, string(received), "Should receive the whole throttled body")
+	assert.True(t, clock.Now().Sub(start) >= 2*time.Second, "Should take at least 3s minus the burst, took %s", clock.Now().Sub(start))
+}
+
+func TestRateLimitReject(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithRateLimit("127.0.0.1", 1, 2, model.OverflowReject).Build()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	defer func() {
+		err, ok := recover().(*model.RateLimitError)
+
+		assert.True(t, ok, "Should have panicked with a rate limit error")
+		assert.Equal(t, "Rate limit of 1 requests per second exceeded for 127.0.0.1", err.Error(), "Should equal error message")
+	}()
+
+	NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+}
+
+func TestRateLimitBlock(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithRateLimit("127.0.0.1", 20, 1, model.OverflowBlock).Build()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+	}
+
+	assert.True(t, time.Since(start) >= 140*time.Millisecond, "Should space requests 50ms apart after the burst")
+}
+
+func TestRateLimitBlockFollowsClock(t *testing.T) {
+	clock := requestmock.NewClock(time.Now())
+	limiter := newRateLimiter(clock)
+	limiter.limit("api.example.com", 1, 1, model.OverflowBlock)
+
+	assert.Nil(t, limiter.wait(context.Background(), "api.example.com"), "Should allow the burst")
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- limiter.wait(context.Background(), "api.example.com")
+	}()
+
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Second)
+
+	assert.Nil(t, <-waited, "Should allow the request once the clock has refilled the bucket")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waited <- limiter.wait(ctx, "api.example.com")
+	}()
+
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	assert.Equal(t, context.Canceled, <-waited, "Should give up when the context is done")
+}
+
+func TestMaxConcurrentRequestsQueueFull(t *testing.T) {
+	limiter := newConcurrencyLimiter(1, 1)
+
+	release, _ := limiter.acquire(context.Background(), model.PriorityNormal)
+
+	queued := make(chan struct{})
+	go func() {
+		done, _ := limiter.acquire(context.Background(), model.PriorityNormal)
+		done()
+		close(queued)
+	}()
+
+	for limiter.snapshot().Queued == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := limiter.acquire(context.Background(), model.PriorityNormal)
+
+	_, ok := err.(*model.QueueFullError)
+
+	assert.True(t, ok, "Should fail with a queue full error")
+	assert.Equal(t, "Request queue is full (1 in flight, 1 queued)", err.Error(), "Should equal error message")
+
+	release()
+	<-queued
+
+	_, err = limiter.acquire(context.Background(), model.PriorityNormal)
+
+	assert.Nil(t, err, "Should have a free slot again")
+}
+
+func TestMaxConcurrentRequestsBlock(t *testing.T) {
+	var inFlight, peak int32
+
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+
+	defer ts.Close()
+
+	c := NewClientBuilder().WithMaxConcurrentRequests(2, 10).Build()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak), "Should never exceed two requests in flight")
+}
+
+func TestBulkheadIsolatesUpstreams(t *testing.T) {
+	release := make(chan struct{})
+
+	slow := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	fast := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {}))
+
+	defer slow.Close()
+	defer fast.Close()
+
+	c := NewClientBuilder().WithBulkhead(model.Bulkhead{Name: "slow", Hosts: []string{"127.0.0.1"}, MaxConcurrent: 1}).Build()
+
+	done := make(chan struct{})
+	go func() {
+		NewRequestBuilder().WithUrl(slow.URL).WithClient(c).Build().Do()
+		close(done)
+	}()
+
+	for c.BulkheadStats()["slow"].InFlight == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	func() {
+		defer func() {
+			err, ok := recover().(*model.BulkheadFullError)
+
+			assert.True(t, ok, "Should have panicked with a bulkhead full error")
+			assert.Equal(t, "Bulkhead slow is full, rejected request to 127.0.0.1", err.Error(), "Should equal error message")
+		}()
+
+		NewRequestBuilder().WithUrl(slow.URL).WithClient(c).Build().Do()
+	}()
+
+	response := NewRequestBuilder().WithUrl(strings.Replace(fast.URL, "127.0.0.1", "localhost", 1)).WithClient(c).Build().Do()
+	assert.Equal(t, 200, response.Response().StatusCode, "Should not limit hosts outside the bulkhead")
+
+	stats := c.BulkheadStats()["slow"]
+	assert.Equal(t, model.BulkheadStats{InFlight: 1, Rejected: 1, MaxConcurrent: 1}, stats, "Should report saturation")
+
+	close(release)
+	<-done
+}
+
+func TestConcurrencyLimiterPriority(t *testing.T) {
+	limiter := newConcurrencyLimiter(1, 10)
+
+	release, _ := limiter.acquire(context.Background(), model.PriorityNormal)
+
+	var mutex sync.Mutex
+	var order []model.Priority
+	var wg sync.WaitGroup
+
+	for i, priority := range []model.Priority{model.PriorityLow, model.PriorityNormal, model.PriorityHigh} {
+		wg.Add(1)
+		go func(priority model.Priority) {
+			defer wg.Done()
+			done, _ := limiter.acquire(context.Background(), priority)
+			mutex.Lock()
+			order = append(order, priority)
+			mutex.Unlock()
+			done()
+		}(priority)
+		for limiter.snapshot().Queued != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	release()
+	wg.Wait()
+
+	assert.Equal(t, []model.Priority{model.PriorityHigh, model.PriorityNormal, model.PriorityLow}, order, "Should dispatch higher priorities first")
+}
+
+func TestConcurrencyLimiterCanceled(t *testing.T) {
+	limiter := newConcurrencyLimiter(1, 10)
+
+	release, _ := limiter.acquire(context.Background(), model.PriorityNormal)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := limiter.acquire(ctx, model.PriorityNormal)
+
+	assert.Equal(t, context.DeadlineExceeded, err, "Should stop waiting when the context is done")
+	assert.Equal(t, 0, limiter.snapshot().Queued, "Should leave the queue")
+
+	release()
+
+	next, err := limiter.acquire(context.Background(), model.PriorityNormal)
+
+	assert.Nil(t, err, "Should not hand the slot to a canceled request")
+	assert.Equal(t, 1, limiter.snapshot().InFlight, "Should count the slot once")
+
+	next()
+
+	assert.Equal(t, 0, limiter.snapshot().InFlight, "Should free the slot")
+}
@@ -0,0 +1,51 @@
+package gorequest
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+/**
+ * Limits the throughput of a body stream to the rate of a shared token
+ * bucket. Reads are capped at the bucket size so a single large read cannot
+ * monopolise the bandwidth of other streams sharing the bucket. Reads fail
+ * with the error of ctx once it is done.
+ */
+type throttledReader struct {
+	bucket *tokenBucket
+	ctx    context.Context
+	reader io.ReadCloser
+}
+
+func newThrottledReader(ctx context.Context, reader io.ReadCloser, bucket *tokenBucket) io.ReadCloser {
+	if bucket == nil || reader == nil || reader == http.NoBody {
+		return reader
+	}
+	return &throttledReader{
+		bucket: bucket,
+		ctx:    ctx,
+		reader: reader,
+	}
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+
+	if max := int(r.bucket.burst); len(p) > max {
+		p = p[:max]
+	}
+
+	n, err := r.reader.Read(p)
+
+	if n > 0 {
+		if waitErr := r.bucket.wait(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+
+	return n, err
+}
+
+func (r *throttledReader) Close() error {
+	return r.reader.Close()
+}
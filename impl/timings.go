@@ -0,0 +1,89 @@
+package gorequest
+
+import (
+	"crypto/tls"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+/**
+ * Collects the timings of an exchange through an httptrace.ClientTrace.
+ * Hooks may fire concurrently, e.g. when several addresses are dialed.
+ */
+type timingRecorder struct {
+	connectStart time.Time
+	dnsStart     time.Time
+	mutex        sync.Mutex
+	timings      model.Timings
+	tlsStart     time.Time
+	wroteRequest time.Time
+}
+
+func (t *timingRecorder) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		ConnectDone: func(network, addr string, err error) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			if !t.connectStart.IsZero() {
+				t.timings.Connect += time.Since(t.connectStart)
+				t.connectStart = time.Time{}
+			}
+		},
+		ConnectStart: func(network, addr string) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.timings.DNSLookup += time.Since(t.dnsStart)
+		},
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.dnsStart = time.Now()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.timings.ConnectionReused = info.Reused
+		},
+		GotFirstResponseByte: func() {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			if !t.wroteRequest.IsZero() {
+				t.timings.TimeToFirstByte += time.Since(t.wroteRequest)
+				t.wroteRequest = time.Time{}
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.timings.TLSHandshake += time.Since(t.tlsStart)
+		},
+		TLSHandshakeStart: func() {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.tlsStart = time.Now()
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			t.mutex.Lock()
+			defer t.mutex.Unlock()
+			t.wroteRequest = time.Now()
+		},
+	}
+}
+
+func (t *timingRecorder) snapshot(download time.Duration, total time.Duration) model.Timings {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	timings := t.timings
+	timings.Download = download
+	timings.Total = total
+	return timings
+}
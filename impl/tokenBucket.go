@@ -0,0 +1,115 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"sync"
+	"time"
+)
+
+/**
+ * A token bucket refilled continuously at rate tokens per second, holding at
+ * most burst tokens. Reservations may overdraw the bucket; the caller then
+ * waits until the debt has been refilled.
+ */
+type tokenBucket struct {
+	burst  float64
+	clock  model.Clock
+	last   time.Time
+	mutex  sync.Mutex
+	rate   float64
+	tokens float64
+}
+
+func newTokenBucket(clock model.Clock, rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		burst:  float64(burst),
+		clock:  clock,
+		last:   clock.Now(),
+		rate:   rate,
+		tokens: float64(burst),
+	}
+}
+
+/**
+ * Takes n tokens and returns how long the caller must wait before using them.
+ */
+func (b *tokenBucket) reserve(n int) time.Duration {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	b.tokens -= float64(n)
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+/**
+ * Takes n tokens only if they are available right away.
+ */
+func (b *tokenBucket) take(n int) bool {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+
+	if b.tokens < float64(n) {
+		return false
+	}
+
+	b.tokens -= float64(n)
+	return true
+}
+
+/**
+ * Gives back n tokens taken by a reservation the caller gave up on.
+ */
+func (b *tokenBucket) refund(n int) {
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.tokens += float64(n)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *tokenBucket) refill() {
+	now := b.clock.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+/**
+ * Takes n tokens, waiting until they are refilled if needed. Fails with the
+ * error of ctx, giving the tokens back, if it is done first.
+ */
+func (b *tokenBucket) wait(ctx context.Context, n int) error {
+
+	delay := b.reserve(n)
+
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-b.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		b.refund(n)
+		return ctx.Err()
+	}
+}
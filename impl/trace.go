@@ -0,0 +1,116 @@
+package gorequest
+
+import (
+	"context"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"strings"
+)
+
+type traceKey struct{}
+
+/**
+ * Returns a copy of ctx carrying trace. Requests built with it propagate the
+ * trace in their headers.
+ */
+func ContextWithTrace(ctx context.Context, trace model.TraceContext) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace)
+}
+
+/**
+ * Returns the trace context stored with ContextWithTrace, if any.
+ */
+func TraceFromContext(ctx context.Context) (model.TraceContext, bool) {
+	trace, ok := ctx.Value(traceKey{}).(model.TraceContext)
+	return trace, ok
+}
+
+/**
+ * Parses the traceparent and tracestate headers of an incoming request, e.g.
+ * to propagate the trace of a server handler to the requests it sends.
+ */
+func ParseTraceparent(traceparent string, tracestate string) (model.TraceContext, error) {
+
+	fields := strings.Split(strings.TrimSpace(traceparent), "-")
+
+	if len(fields) < 4 || !hexDigits(fields[0], 2) || fields[0] == "ff" || (fields[0] == "00" && len(fields) != 4) || !hexDigits(fields[3], 2) {
+		return model.TraceContext{}, fmt.Errorf("Invalid traceparent: %s", traceparent)
+	}
+
+	trace := model.TraceContext{
+		TraceID: fields[1],
+		SpanID:  fields[2],
+		Sampled: fields[3][1]&1 == 1,
+		State:   strings.TrimSpace(tracestate),
+	}
+
+	if !validTrace(trace) {
+		return model.TraceContext{}, fmt.Errorf("Invalid traceparent: %s", traceparent)
+	}
+
+	return trace, nil
+}
+
+/**
+ * Sets the trace headers of req from the trace context of its
+ * context.Context. Headers set explicitly on the request are kept.
+ */
+func injectTrace(req *http.Request, propagation *model.TracePropagation) {
+
+	extract := TraceFromContext
+
+	if propagation != nil && propagation.Extract != nil {
+		extract = propagation.Extract
+	}
+
+	trace, ok := extract(req.Context())
+
+	if !ok || !validTrace(trace) {
+		return
+	}
+
+	flags, sampled := "00", "0"
+
+	if trace.Sampled {
+		flags, sampled = "01", "1"
+	}
+
+	setDefaultHeader(req.Header, "traceparent", "00-"+trace.TraceID+"-"+trace.SpanID+"-"+flags)
+
+	if trace.State != "" {
+		setDefaultHeader(req.Header, "tracestate", trace.State)
+	}
+
+	if propagation != nil && propagation.B3 {
+		setDefaultHeader(req.Header, "X-B3-TraceId", trace.TraceID)
+		setDefaultHeader(req.Header, "X-B3-SpanId", trace.SpanID)
+		setDefaultHeader(req.Header, "X-B3-Sampled", sampled)
+	}
+}
+
+func setDefaultHeader(header http.Header, key string, value string) {
+	if header.Get(key) == "" {
+		header.Set(key, value)
+	}
+}
+
+func validTrace(trace model.TraceContext) bool {
+	return hexDigits(trace.TraceID, 32) && strings.Trim(trace.TraceID, "0") != "" &&
+		hexDigits(trace.SpanID, 16) && strings.Trim(trace.SpanID, "0") != ""
+}
+
+/**
+ * Reports whether s consists of n lowercase hex digits.
+ */
+func hexDigits(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package gorequest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTrace = model.TraceContext{
+	TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+	SpanID:  "00f067aa0ba902b7",
+	Sampled: true,
+	State:   "congo=t61rcWkgMzE",
+}
+
+func newTraceServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, req.Header.Get("traceparent"), "|", req.Header.Get("tracestate"), "|", req.Header.Get("X-B3-TraceId"))
+	}))
+}
+
+func TestTracePropagation(t *testing.T) {
+	ts := newTraceServer()
+
+	defer ts.Close()
+
+	ctx := ContextWithTrace(context.Background(), testTrace)
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithContext(ctx).Build().Do()
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01|congo=t61rcWkgMzE|", string(response.Body()), "Should send trace headers")
+
+	response = NewRequestBuilder().WithUrl(ts.URL).Build().Do()
+
+	assert.Equal(t, "||", string(response.Body()), "Should not send trace headers without a trace")
+}
+
+func TestTracePropagationB3(t *testing.T) {
+	ts := newTraceServer()
+
+	defer ts.Close()
+
+	type spanKey struct{}
+
+	c := NewClientBuilder().WithTracePropagation(model.TracePropagation{
+		B3: true,
+		Extract: func(ctx context.Context) (model.TraceContext, bool) {
+			trace, ok := ctx.Value(spanKey{}).(model.TraceContext)
+			return trace, ok
+		},
+	}).Build()
+
+	ctx := context.WithValue(context.Background(), spanKey{}, model.TraceContext{TraceID: testTrace.TraceID, SpanID: testTrace.SpanID})
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithContext(ctx).Build().Do()
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00||4bf92f3577b34da6a3ce929d0e0e4736", string(response.Body()), "Should send B3 headers")
+}
+
+func TestParseTraceparent(t *testing.T) {
+	trace, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "congo=t61rcWkgMzE")
+
+	assert.Nil(t, err, "Should parse traceparent")
+	assert.Equal(t, testTrace, trace, "Should equal trace context")
+
+	for _, traceparent := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+	} {
+		_, err := ParseTraceparent(traceparent, "")
+		assert.NotNil(t, err, "Should reject "+traceparent)
+	}
+}
+
+func TestContextHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(resp, req.Header.Get("X-Correlation-ID"), "|", req.Header.Get("X-Tenant-ID"))
+	}))
+
+	defer ts.Close()
+
+	type correlationKey struct{}
+	type tenantKey struct{}
+
+	c := NewClientBuilder().
+		WithContextHeader(correlationKey{}, "X-Correlation-ID").
+		WithContextHeader(tenantKey{}, "X-Tenant-ID").
+		Build()
+
+	ctx := context.WithValue(context.Background(), correlationKey{}, "abc-123")
+	ctx = context.WithValue(ctx, tenantKey{}, 42)
+
+	response := NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithContext(ctx).Build().Do()
+
+	assert.Equal(t, "abc-123|42", string(response.Body()), "Should copy context values into headers")
+
+	response = NewRequestBuilder().WithUrl(ts.URL).WithClient(c).WithContext(ctx).WithHeader("X-Correlation-ID", "explicit").Build().Do()
+
+	assert.Equal(t, "explicit|42", string(response.Body()), "Should keep headers set explicitly")
+
+	response = NewRequestBuilder().WithUrl(ts.URL).WithClient(c).Build().Do()
+
+	assert.Equal(t, "|", string(response.Body()), "Should skip missing values")
+}
@@ -0,0 +1,117 @@
+package gorequest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	tus "github.com/demianlessa/gorequest/tus"
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+ * A tus server keeping a single upload in memory. failAt makes the PATCH
+ * sent at that offset fail once; corruptAt flips its checksum once.
+ */
+type tusServer struct {
+	corruptAt int64
+	data      []byte
+	failAt    int64
+	length    int64
+	metadata  string
+	mutex     sync.Mutex
+	patches   int
+}
+
+func (s *tusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if r.Header.Get("Tus-Resumable") != "1.0.0" {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	w.Header().Set("Tus-Resumable", "1.0.0")
+
+	switch r.Method {
+	case "POST":
+		s.length, _ = strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+		s.metadata = r.Header.Get("Upload-Metadata")
+		w.Header().Set("Location", "/files/1")
+		w.WriteHeader(http.StatusCreated)
+	case "HEAD":
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+		w.Header().Set("Upload-Length", strconv.FormatInt(s.length, 10))
+	case "PATCH":
+		s.patches++
+		offset, _ := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+		chunk, _ := ioutil.ReadAll(r.Body)
+		sum := sha1.Sum(chunk)
+		switch {
+		case r.Header.Get("Content-Type") != "application/offset+octet-stream":
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		case offset != int64(len(s.data)):
+			w.WriteHeader(http.StatusConflict)
+		case offset == s.failAt:
+			s.failAt = -1
+			s.data = append(s.data, chunk[:1]...)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case offset == s.corruptAt || r.Header.Get("Upload-Checksum") != "sha1 "+base64.StdEncoding.EncodeToString(sum[:]):
+			s.corruptAt = -1
+			w.WriteHeader(tus.StatusChecksumMismatch)
+		default:
+			s.data = append(s.data, chunk...)
+			w.Header().Set("Upload-Offset", strconv.Itoa(len(s.data)))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+func TestTus(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 10))
+	state := &tusServer{corruptAt: 20, failAt: 50}
+
+	server := httptest.NewServer(state)
+	defer server.Close()
+
+	c := NewClientBuilder().Build()
+	uploader := tus.NewClient(func() model.RequestBuilder { return NewRequestBuilder().WithClient(c) })
+	uploader.ChunkSize = 10
+	uploader.Checksum = "sha1"
+
+	upload, err := uploader.Create(context.Background(), server.URL+"/files/", int64(len(content)), map[string]string{"filename": "digits.txt", "type": "text/plain"})
+
+	assert.Nil(t, err, "Should create the upload")
+	assert.Equal(t, server.URL+"/files/1", upload.URL, "Should resolve the location of the upload")
+	assert.Equal(t, "filename ZGlnaXRzLnR4dA==,type dGV4dC9wbGFpbg==", state.metadata, "Should send the metadata")
+
+	err = uploader.Upload(context.Background(), upload, bytes.NewReader(content))
+
+	var failure *tus.StatusError
+
+	assert.True(t, errors.As(err, &failure), "Should fail on server errors, got %v", err)
+	assert.Equal(t, http.StatusServiceUnavailable, failure.StatusCode, "Should report the status")
+	assert.Equal(t, int64(50), upload.Offset, "Should keep the offset acknowledged")
+
+	err = uploader.Resume(context.Background(), upload, bytes.NewReader(content))
+
+	assert.Nil(t, err, "Should resume the upload")
+	assert.Equal(t, content, state.data, "Should upload the whole content, resending the corrupted chunk")
+	assert.Equal(t, int64(100), upload.Offset, "Should reach the end of the upload")
+	assert.Equal(t, 12, state.patches, "Should send each chunk once, and again after a failure")
+
+	uploader.Checksum = "crc32"
+
+	assert.EqualError(t, uploader.Upload(context.Background(), upload, bytes.NewReader(content)), "Unsupported checksum algorithm: crc32", "Should reject unknown checksum algorithms")
+}
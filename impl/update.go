@@ -0,0 +1,75 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+/**
+ * Reads a resource, then writes the result of update.Merge with If-Match set
+ * to the ETag read, so the write fails with 412 if another writer got there
+ * first; the resource is then read and merged again. Returns the response
+ * to the successful write; responses with a status of 400 or above are
+ * returned along with their error, and model.ErrUpdateConflict once all
+ * attempts conflicted.
+ */
+func Update(ctx context.Context, update model.Update) (model.Response, error) {
+
+	if update.Read == nil || update.Merge == nil {
+		panic(errors.New("Update requires Read and Merge functions"))
+	}
+
+	if update.Attempts <= 0 {
+		update.Attempts = 3
+	}
+
+	var resp model.Response
+
+	for attempt := 0; attempt < update.Attempts; attempt++ {
+
+		current, err := update.Read().WithContext(ctx).Build().Send()
+
+		if err != nil {
+			return current, err
+		}
+
+		if current.Response().StatusCode >= http.StatusBadRequest {
+			return current, fmt.Errorf("Cannot read resource: status %d", current.Response().StatusCode)
+		}
+
+		etag := current.Response().Header.Get("ETag")
+
+		if etag == "" {
+			return current, errors.New("Resource has no ETag")
+		}
+
+		write, err := update.Merge(current)
+
+		if err != nil {
+			return current, err
+		}
+
+		resp, err = write.WithHeader("If-Match", etag).WithContext(ctx).Build().Send()
+
+		if err != nil {
+			return resp, err
+		}
+
+		status := resp.Response().StatusCode
+
+		if status == http.StatusPreconditionFailed {
+			continue
+		}
+
+		if status >= http.StatusBadRequest {
+			return resp, fmt.Errorf("Update failed with status %d", status)
+		}
+
+		return resp, nil
+	}
+
+	return resp, model.ErrUpdateConflict
+}
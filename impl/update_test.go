@@ -0,0 +1,70 @@
+package gorequest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	mock := requestmock.New()
+
+	mock.On("GET", "/users/1").Times(1).ReplyHeader("ETag", `"v1"`).ReplyJSON(http.StatusOK, map[string]interface{}{"name": "Ann", "tags": []string{}})
+	mock.On("GET", "/users/1").ReplyHeader("ETag", `"v2"`).ReplyJSON(http.StatusOK, map[string]interface{}{"name": "Ann", "tags": []string{"admin"}})
+	mock.On("PUT", "/users/1").MatchHeader("If-Match", `"v2"`).ReplyHeader("ETag", `"v3"`).Reply(http.StatusOK, "")
+	mock.On("PUT", "/users/1").Reply(http.StatusPreconditionFailed, "")
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+	url := "https://api.example.com/users/1"
+
+	merges := 0
+
+	update := model.Update{
+		Read: func() model.RequestBuilder {
+			return NewRequestBuilder().WithClient(c).WithUrl(url)
+		},
+		Merge: func(current model.Response) (model.RequestBuilder, error) {
+			merges++
+			var user struct {
+				Name string   `json:"name"`
+				Tags []string `json:"tags"`
+			}
+			if err := json.Unmarshal(current.Body(), &user); err != nil {
+				return nil, err
+			}
+			user.Tags = append(user.Tags, "editor")
+			return NewRequestBuilder().WithClient(c).WithMethod("PUT").WithUrl(url).WithBody(newJsonBody(user)), nil
+		},
+	}
+
+	resp, err := Update(context.Background(), update)
+
+	assert.Nil(t, err, "Should update the resource")
+	assert.Equal(t, `"v3"`, resp.Response().Header.Get("ETag"), "Should return the response to the write")
+	assert.Equal(t, 2, merges, "Should merge again after a conflict")
+	assert.True(t, mock.AssertCalled(t, "PUT", "/users/1", requestmock.WithHeader("If-Match", `"v2"`), requestmock.WithJSON(`{"name":"Ann","tags":["admin","editor"]}`)),
+		"Should write the merge of the latest state")
+
+	mock.Reset()
+	mock.On("GET", "/users/1").ReplyHeader("ETag", `"v1"`).ReplyJSON(http.StatusOK, map[string]interface{}{"name": "Ann"})
+	mock.On("PUT", "/users/1").Reply(http.StatusPreconditionFailed, "")
+
+	update.Attempts = 2
+	resp, err = Update(context.Background(), update)
+
+	assert.Equal(t, model.ErrUpdateConflict, err, "Should give up after the attempts")
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Response().StatusCode, "Should return the last conflict")
+	assert.Len(t, mock.Calls(), 4, "Should read and write once per attempt")
+
+	mock.Reset()
+	mock.On("GET", "/users/1").ReplyJSON(http.StatusOK, map[string]interface{}{"name": "Ann"})
+
+	_, err = Update(context.Background(), update)
+
+	assert.EqualError(t, err, "Resource has no ETag", "Should require an ETag")
+}
@@ -0,0 +1,75 @@
+package gorequest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+/**
+ * Resolves, connects and completes the TLS handshake with every host
+ * concurrently, so that the first requests find a connection in the pool.
+ * The transport offers no way to open a connection without using it: each
+ * connection is established with a HEAD request to the root of the host,
+ * whose status is ignored. Only failures to connect are reported, for all
+ * hosts at once.
+ */
+func (c *client) Warmup(ctx context.Context, hosts ...string) error {
+
+	var failures []string
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			if err := c.warmup(ctx, host); err != nil {
+				mutex.Lock()
+				failures = append(failures, fmt.Sprintf("%s: %v", host, err))
+				mutex.Unlock()
+			}
+		}(host)
+	}
+
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return fmt.Errorf("Warmup failed for %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+func (c *client) warmup(ctx context.Context, host string) error {
+
+	target := host
+
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+
+	if err != nil {
+		return err
+	}
+
+	req.URL.Path = "/"
+
+	resp, err := c.httpClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	// the connection only returns to the pool once the body is consumed
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	return nil
+}
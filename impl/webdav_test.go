@@ -0,0 +1,109 @@
+package gorequest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+	"time"
+
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	webdav "github.com/demianlessa/gorequest/webdav"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebDAV(t *testing.T) {
+	mock := requestmock.New()
+
+	var received *http.Request
+	var body string
+
+	record := func(req *http.Request, raw []byte) bool {
+		received, body = req, string(raw)
+		return true
+	}
+
+	mock.On("PROPFIND", "/files/").Match(record).Reply(http.StatusMultiStatus, `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:oc="http://owncloud.org/ns">
+  <d:response>
+    <d:href>/files/</d:href>
+    <d:propstat>
+      <d:prop><d:resourcetype><d:collection/></d:resourcetype><d:displayname>files</d:displayname></d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/files/report.pdf</d:href>
+    <d:propstat>
+      <d:prop>
+        <d:resourcetype/>
+        <d:getcontentlength>1024</d:getcontentlength>
+        <d:getcontenttype>application/pdf</d:getcontenttype>
+        <d:getetag>"abc"</d:getetag>
+        <d:getlastmodified>Tue, 13 Oct 2026 08:00:00 GMT</d:getlastmodified>
+        <oc:fileid>42</oc:fileid>
+      </d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+    <d:propstat>
+      <d:prop><d:displayname/></d:prop>
+      <d:status>HTTP/1.1 404 Not Found</d:status>
+    </d:propstat>
+  </d:response>
+</d:multistatus>`)
+	mock.On("MKCOL", "/files/new/").Match(record).Reply(http.StatusCreated, "")
+	mock.On("COPY", "/files/report.pdf").Match(record).Reply(http.StatusCreated, "")
+	mock.On("MOVE", "/files/report.pdf").Match(record).Reply(http.StatusCreated, "")
+	mock.On("LOCK", "/files/report.pdf").Match(record).ReplyHeader("Lock-Token", "<opaquelocktoken:1234>").Reply(http.StatusOK, "")
+	mock.On("UNLOCK", "/files/report.pdf").Match(record).Reply(http.StatusNoContent, "")
+
+	c := NewClientBuilder().WithTransport(mock).Build()
+
+	request := func(path string) model.RequestBuilder {
+		return NewRequestBuilder().WithUrl("https://cloud.example.com" + path).WithClient(c)
+	}
+
+	resp := webdav.Propfind(request("/files/"), webdav.DepthOne, xml.Name{Space: "DAV:", Local: "displayname"}).Build().Do()
+
+	assert.Equal(t, "1", received.Header.Get("Depth"), "Should set the depth")
+	assert.Contains(t, body, `<d:prop><displayname xmlns="DAV:"/></d:prop>`, "Should request the properties")
+
+	resources, err := webdav.ParseMultistatus(resp)
+
+	assert.Nil(t, err, "Should parse the response")
+	assert.Equal(t, 2, len(resources), "Should list every resource")
+	assert.True(t, resources[0].Collection, "Should detect collections")
+	assert.Equal(t, "files", resources[0].DisplayName, "Should decode the display name")
+
+	file := resources[1]
+
+	assert.False(t, file.Collection, "Should detect files")
+	assert.Equal(t, int64(1024), file.ContentLength, "Should decode the length")
+	assert.Equal(t, `"abc"`, file.ETag, "Should decode the ETag")
+	assert.Equal(t, time.Date(2026, 10, 13, 8, 0, 0, 0, time.UTC), file.LastModified, "Should decode the modification time")
+	assert.Equal(t, "42", file.Properties[xml.Name{Space: "http://owncloud.org/ns", Local: "fileid"}], "Should keep other properties")
+	assert.Equal(t, "", file.DisplayName, "Should skip properties that were not found")
+
+	webdav.Mkcol(request("/files/new/")).Build().Do()
+
+	assert.Equal(t, "MKCOL", received.Method, "Should create collections")
+
+	webdav.Copy(request("/files/report.pdf"), "https://cloud.example.com/files/copy.pdf", webdav.DepthInfinity, false).Build().Do()
+
+	assert.Equal(t, "https://cloud.example.com/files/copy.pdf", received.Header.Get("Destination"), "Should set the destination")
+	assert.Equal(t, "F", received.Header.Get("Overwrite"), "Should set the overwrite flag")
+
+	webdav.Move(request("/files/report.pdf"), "https://cloud.example.com/files/old.pdf", true).Build().Do()
+
+	assert.Equal(t, "T", received.Header.Get("Overwrite"), "Should set the overwrite flag")
+
+	token, err := webdav.LockToken(webdav.Lock(request("/files/report.pdf"), "alice <a&b>", webdav.DepthZero, time.Hour).Build().Do())
+
+	assert.Equal(t, "opaquelocktoken:1234", token, "Should return the lock token")
+	assert.Equal(t, "Second-3600", received.Header.Get("Timeout"), "Should set the timeout")
+	assert.Contains(t, body, "<d:owner>alice &lt;a&amp;b&gt;</d:owner>", "Should escape the owner")
+
+	webdav.Unlock(request("/files/report.pdf"), token).Build().Do()
+
+	assert.Equal(t, "<opaquelocktoken:1234>", received.Header.Get("Lock-Token"), "Should send the lock token")
+}
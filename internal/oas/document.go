@@ -0,0 +1,277 @@
+package gorequest
+
+/**
+ * The OpenAPI 3 document model shared by the openapi and gen packages:
+ * parsing, in YAML or JSON, and resolution of local $refs
+ * (#/components/...). Fields are limited to what those packages use.
+ */
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	yaml "gopkg.in/yaml.v3"
+	"strings"
+)
+
+type Document struct {
+	Components Components           `json:"components"`
+	Info       Info                 `json:"info"`
+	OpenAPI    string               `json:"openapi"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Servers    []Server             `json:"servers"`
+}
+
+type Info struct {
+	Title string `json:"title"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Components struct {
+	Parameters    map[string]*Parameter   `json:"parameters"`
+	RequestBodies map[string]*RequestBody `json:"requestBodies"`
+	Responses     map[string]*Response    `json:"responses"`
+	Schemas       map[string]*Schema      `json:"schemas"`
+}
+
+type PathItem struct {
+	Delete     *Operation   `json:"delete"`
+	Get        *Operation   `json:"get"`
+	Head       *Operation   `json:"head"`
+	Options    *Operation   `json:"options"`
+	Parameters []*Parameter `json:"parameters"`
+	Patch      *Operation   `json:"patch"`
+	Post       *Operation   `json:"post"`
+	Put        *Operation   `json:"put"`
+	Trace      *Operation   `json:"trace"`
+}
+
+type Operation struct {
+	Description string               `json:"description"`
+	OperationID string               `json:"operationId"`
+	Parameters  []*Parameter         `json:"parameters"`
+	RequestBody *RequestBody         `json:"requestBody"`
+	Responses   map[string]*Response `json:"responses"`
+	Summary     string               `json:"summary"`
+}
+
+type Parameter struct {
+	Description string  `json:"description"`
+	In          string  `json:"in"`
+	Name        string  `json:"name"`
+	Ref         string  `json:"$ref"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Content  map[string]*MediaType `json:"content"`
+	Ref      string                `json:"$ref"`
+	Required bool                  `json:"required"`
+}
+
+type Response struct {
+	Content map[string]*MediaType `json:"content"`
+	Ref     string                `json:"$ref"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+/**
+ * The subset of JSON Schema used by OpenAPI 3.0 documents.
+ */
+type Schema struct {
+	AdditionalProperties *AdditionalProperties `json:"additionalProperties"`
+	AllOf                []*Schema             `json:"allOf"`
+	AnyOf                []*Schema             `json:"anyOf"`
+	Description          string                `json:"description"`
+	Enum                 []interface{}         `json:"enum"`
+	ExclusiveMaximum     bool                  `json:"exclusiveMaximum"`
+	ExclusiveMinimum     bool                  `json:"exclusiveMinimum"`
+	Format               string                `json:"format"`
+	Items                *Schema               `json:"items"`
+	MaxItems             *int                  `json:"maxItems"`
+	MaxLength            *int                  `json:"maxLength"`
+	Maximum              *float64              `json:"maximum"`
+	MinItems             *int                  `json:"minItems"`
+	MinLength            *int                  `json:"minLength"`
+	Minimum              *float64              `json:"minimum"`
+	Nullable             bool                  `json:"nullable"`
+	OneOf                []*Schema             `json:"oneOf"`
+	Pattern              string                `json:"pattern"`
+	Properties           map[string]*Schema    `json:"properties"`
+	Ref                  string                `json:"$ref"`
+	Required             []string              `json:"required"`
+	Type                 string                `json:"type"`
+}
+
+/**
+ * Either a boolean or a schema: false forbids undeclared properties.
+ */
+type AdditionalProperties struct {
+	Forbidden bool
+	Schema    *Schema
+}
+
+func (a *AdditionalProperties) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "false":
+		a.Forbidden = true
+		return nil
+	case "true":
+		return nil
+	}
+	a.Schema = &Schema{}
+	return json.Unmarshal(data, a.Schema)
+}
+
+/**
+ * Parses a document in YAML or JSON.
+ */
+func Parse(data []byte) (*Document, error) {
+
+	var raw interface{}
+
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	// YAML maps may have non-string keys, e.g. unquoted status codes
+	normalized, err := json.Marshal(stringKeys(raw))
+
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+
+	if err := json.Unmarshal(normalized, doc); err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("Unsupported OpenAPI version %q", doc.OpenAPI)
+	}
+
+	return doc, nil
+}
+
+type MethodOperation struct {
+	Method    string
+	Operation *Operation
+}
+
+/**
+ * The operations defined by a path item, ordered by method.
+ */
+func (p *PathItem) Operations() []MethodOperation {
+
+	all := []MethodOperation{
+		{"DELETE", p.Delete},
+		{"GET", p.Get},
+		{"HEAD", p.Head},
+		{"OPTIONS", p.Options},
+		{"PATCH", p.Patch},
+		{"POST", p.Post},
+		{"PUT", p.Put},
+		{"TRACE", p.Trace},
+	}
+
+	defined := all[:0]
+
+	for _, entry := range all {
+		if entry.Operation != nil {
+			defined = append(defined, entry)
+		}
+	}
+
+	return defined
+}
+
+func (p *PathItem) Operation(method string) *Operation {
+	switch strings.ToUpper(method) {
+	case "DELETE":
+		return p.Delete
+	case "GET":
+		return p.Get
+	case "HEAD":
+		return p.Head
+	case "OPTIONS":
+		return p.Options
+	case "PATCH":
+		return p.Patch
+	case "POST":
+		return p.Post
+	case "PUT":
+		return p.Put
+	case "TRACE":
+		return p.Trace
+	}
+	return nil
+}
+
+/****************************************************
+ * $ref resolution, limited to local components
+ ****************************************************/
+
+func (d *Document) Parameter(p *Parameter) *Parameter {
+	for p != nil && p.Ref != "" {
+		p = d.Components.Parameters[RefName(p.Ref, "parameters")]
+	}
+	return p
+}
+
+func (d *Document) RequestBody(b *RequestBody) *RequestBody {
+	for b != nil && b.Ref != "" {
+		b = d.Components.RequestBodies[RefName(b.Ref, "requestBodies")]
+	}
+	return b
+}
+
+func (d *Document) Response(r *Response) *Response {
+	for r != nil && r.Ref != "" {
+		r = d.Components.Responses[RefName(r.Ref, "responses")]
+	}
+	return r
+}
+
+func (d *Document) Schema(sc *Schema) *Schema {
+	for sc != nil && sc.Ref != "" {
+		sc = d.Components.Schemas[RefName(sc.Ref, "schemas")]
+	}
+	return sc
+}
+
+/**
+ * The component name a local reference such as "#/components/schemas/Pet" points at.
+ */
+func RefName(ref string, kind string) string {
+	return strings.TrimPrefix(ref, "#/components/"+kind+"/")
+}
+
+func stringKeys(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			value[key] = stringKeys(item)
+		}
+		return value
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			converted[fmt.Sprint(key)] = stringKeys(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range value {
+			value[i] = stringKeys(item)
+		}
+		return value
+	}
+	return value
+}
@@ -0,0 +1,106 @@
+package gorequest
+
+import (
+	"bytes"
+	"encoding/json"
+	model "github.com/demianlessa/gorequest/model"
+	"reflect"
+)
+
+/**
+ * Returns patch, a JSON Merge Patch document, as an
+ * application/merge-patch+json body. Members set to null are removed from
+ * the target, objects are merged recursively, anything else replaces the
+ * target value.
+ */
+func MergePatch(patch json.RawMessage) model.RequestBody {
+	return &body{contentType: "application/merge-patch+json", data: patch}
+}
+
+/**
+ * Computes the merge patch turning before into after, comparing their JSON
+ * encodings: members missing from after (omitempty fields included) are
+ * set to null, changed arrays are replaced whole. As null means removal,
+ * members of after that are null are not reproduced.
+ */
+func Diff(before interface{}, after interface{}) (json.RawMessage, error) {
+
+	from, err := decode(before)
+
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := decode(after)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(diff(from, to))
+}
+
+func diff(from interface{}, to interface{}) interface{} {
+
+	fromObject, ok := from.(map[string]interface{})
+	toObject, isObject := to.(map[string]interface{})
+
+	if !ok || !isObject {
+		return to
+	}
+
+	patch := map[string]interface{}{}
+
+	for key := range fromObject {
+		if _, kept := toObject[key]; !kept {
+			patch[key] = nil
+		}
+	}
+
+	for key, value := range toObject {
+
+		previous, existed := fromObject[key]
+
+		if !existed {
+			patch[key] = value
+			continue
+		}
+
+		if reflect.DeepEqual(previous, value) {
+			continue
+		}
+
+		change := diff(previous, value)
+
+		if object, isObject := change.(map[string]interface{}); isObject && len(object) == 0 {
+			continue
+		}
+
+		patch[key] = change
+	}
+
+	return patch
+}
+
+/**
+ * Decodes the JSON encoding of value, keeping numbers exact.
+ */
+func decode(value interface{}) (interface{}, error) {
+
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded interface{}
+
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
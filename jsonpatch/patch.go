@@ -0,0 +1,158 @@
+package gorequest
+
+/**
+ * Bodies of PATCH requests: JSON Patch (RFC 6902) operation lists and JSON
+ * Merge Patch (RFC 7386) documents, given as is or computed from the
+ * before and after states of a value:
+ *
+ *   body, err := jsonpatch.New().Replace("/name", "Ann").Remove("/nickname").Body()
+ *   resp := gorequest.NewDoer(client).Patch(url, body)
+ *
+ *   patch, err := jsonpatch.Diff(before, after)
+ *   resp := gorequest.NewDoer(client).Patch(url, jsonpatch.MergePatch(patch))
+ */
+
+import (
+	"bytes"
+	"encoding/json"
+	model "github.com/demianlessa/gorequest/model"
+)
+
+/**
+ * One operation of a JSON Patch. Path and From are JSON Pointers, e.g.
+ * "/tags/0"; see Pointer to build them from keys.
+ */
+type Operation struct {
+	From  string
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+/**
+ * Value is written for the operations taking one even when it is nil, as
+ * null is a value like any other there.
+ */
+func (o Operation) MarshalJSON() ([]byte, error) {
+
+	operation := map[string]interface{}{"op": o.Op, "path": o.Path}
+
+	switch o.Op {
+	case "add", "replace", "test":
+		operation["value"] = o.Value
+	case "copy", "move":
+		operation["from"] = o.From
+	}
+
+	return json.Marshal(operation)
+}
+
+/**
+ * A JSON Patch: operations applied in order, all or none of them.
+ */
+type Patch []Operation
+
+func New() Patch {
+	return Patch{}
+}
+
+/**
+ * Adds value at path: sets a member, or inserts into an array ("-" appends).
+ */
+func (p Patch) Add(path string, value interface{}) Patch {
+	return append(p, Operation{Op: "add", Path: path, Value: value})
+}
+
+/**
+ * Copies the value at from to path.
+ */
+func (p Patch) Copy(from string, path string) Patch {
+	return append(p, Operation{From: from, Op: "copy", Path: path})
+}
+
+/**
+ * Moves the value at from to path.
+ */
+func (p Patch) Move(from string, path string) Patch {
+	return append(p, Operation{From: from, Op: "move", Path: path})
+}
+
+/**
+ * Removes the value at path, which must exist.
+ */
+func (p Patch) Remove(path string) Patch {
+	return append(p, Operation{Op: "remove", Path: path})
+}
+
+/**
+ * Replaces the value at path, which must exist.
+ */
+func (p Patch) Replace(path string, value interface{}) Patch {
+	return append(p, Operation{Op: "replace", Path: path, Value: value})
+}
+
+/**
+ * Fails the whole patch unless the value at path equals value, e.g. to
+ * guard the other operations against concurrent changes.
+ */
+func (p Patch) Test(path string, value interface{}) Patch {
+	return append(p, Operation{Op: "test", Path: path, Value: value})
+}
+
+/**
+ * Returns the patch as an application/json-patch+json body.
+ */
+func (p Patch) Body() (model.RequestBody, error) {
+
+	if p == nil {
+		p = Patch{}
+	}
+
+	data, err := json.Marshal([]Operation(p))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &body{contentType: "application/json-patch+json", data: data}, nil
+}
+
+/**
+ * Builds a JSON Pointer from keys and array indexes, escaping "~" and "/".
+ */
+func Pointer(tokens ...string) string {
+
+	var pointer bytes.Buffer
+
+	for _, token := range tokens {
+		pointer.WriteByte('/')
+		for _, r := range token {
+			switch r {
+			case '~':
+				pointer.WriteString("~0")
+			case '/':
+				pointer.WriteString("~1")
+			default:
+				pointer.WriteRune(r)
+			}
+		}
+	}
+
+	return pointer.String()
+}
+
+/**
+ * model.RequestBody of patches.
+ */
+type body struct {
+	contentType string
+	data        []byte
+}
+
+func (b *body) ContentType() string {
+	return b.contentType
+}
+
+func (b *body) RawData() *bytes.Buffer {
+	return bytes.NewBuffer(b.data)
+}
@@ -0,0 +1,49 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Compliance record of one exchange sent by a Client: who sent what, when,
+ * and with which outcome. Records never carry credentials, query strings or
+ * raw bodies; bodies are identified by their SHA-256 hash.
+ */
+type AuditRecord struct {
+	Time time.Time
+	// Caller on whose behalf the request was sent, as stored in its context
+	// with ContextWithPrincipal; empty when unknown.
+	Principal string
+	Method    string
+	// Request URL without user info and query string.
+	URL        string
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+	// Attempt number of the request, starting at 1.
+	Attempt int
+	// Hex-encoded SHA-256 of the bodies; empty when there is no body or it
+	// could not be read again.
+	RequestBodySHA256  string
+	ResponseBodySHA256 string
+}
+
+/**
+ * Append-only destination of audit records, e.g. a file, syslog or an
+ * external service. Sinks are called synchronously for every exchange and
+ * must be safe for concurrent use. Sinks that also implement io.Closer are
+ * closed with the Client.
+ */
+type AuditSink interface {
+	Write(record AuditRecord) error
+}
+
+/**
+ * Audit settings of a Client.
+ */
+type Audit struct {
+	Sink AuditSink
+	// Receives the errors of the sink; nil ignores them. Requests never
+	// fail because of the audit.
+	OnError func(err error)
+}
@@ -0,0 +1,64 @@
+package gorequest
+
+import (
+	"context"
+	"time"
+)
+
+/**
+ * How a Client distributes requests with relative URLs across endpoints.
+ */
+type BalancingStrategy int
+
+const (
+	// Cycle through the healthy endpoints in order.
+	BalanceRoundRobin BalancingStrategy = iota
+	// Pick the healthy endpoint with the fewest requests in flight.
+	BalanceLeastOutstanding
+	// Cycle through the healthy endpoints proportionally to their weight.
+	BalanceWeighted
+	// Pick the healthy endpoint with the lowest median latency, as tracked
+	// by the latency statistics of the Client. Endpoints without samples
+	// are tried first.
+	BalanceLowestLatency
+)
+
+/**
+ * A base URL taking part in load balancing. Weight is only used by the
+ * weighted strategy; values below 1 count as 1.
+ */
+type Endpoint struct {
+	URL    string
+	Weight int
+}
+
+/**
+ * Supplies the current endpoints of a service, e.g. from DNS SRV records or
+ * from a registry such as Consul or etcd. Clients poll it periodically.
+ */
+type Discovery interface {
+	Endpoints(ctx context.Context) ([]Endpoint, error)
+}
+
+/**
+ * Active health checking of a Client's endpoints. Each endpoint is probed
+ * periodically at Path (relative to its base URL); endpoints failing their
+ * checks are avoided until they pass again, so traffic shifts away from them
+ * before user requests fail. A check passes on any status below 400.
+ */
+type HealthCheck struct {
+	// Path probed on every endpoint, e.g. "/healthz".
+	Path string
+	// Method of the probe; empty means GET.
+	Method string
+	// Time between two rounds of checks; zero means 10 seconds.
+	Interval time.Duration
+	// Upper bound of a single probe; zero means 5 seconds.
+	Timeout time.Duration
+	// Consecutive failed checks after which an endpoint is avoided; zero
+	// means 1.
+	UnhealthyThreshold int
+	// Consecutive passed checks after which an avoided endpoint is used
+	// again; zero means 1.
+	HealthyThreshold int
+}
@@ -0,0 +1,50 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Per-host circuit breaker settings. A closed circuit opens when, over the
+ * rolling Window, at least MinRequests requests were made and the share of
+ * failures (connection errors and 5xx responses) reaches FailureRate. After
+ * OpenTimeout the circuit becomes half-open and lets HalfOpenProbes requests
+ * through: if all succeed it closes, otherwise it opens again. Requests
+ * abandoned by the caller (context cancelled or past its deadline, hedged
+ * copies that lost) are not counted.
+ */
+type CircuitBreaker struct {
+	// Failure share (0-1] that opens the circuit.
+	FailureRate float64
+	// Requests needed in the window before the failure rate is evaluated;
+	// zero means 10.
+	MinRequests int
+	// Length of the rolling window; zero means 10 seconds.
+	Window time.Duration
+	// Time spent open before probing; zero means 30 seconds.
+	OpenTimeout time.Duration
+	// Probe requests allowed while half-open; zero means 1.
+	HalfOpenProbes int
+}
+
+/**
+ * State of the circuit of a host.
+ */
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
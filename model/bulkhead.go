@@ -0,0 +1,34 @@
+package gorequest
+
+/**
+ * An isolated pool of request slots shared by a group of upstream hosts, so
+ * a slow upstream can only exhaust its own pool and never the capacity used
+ * for other upstreams.
+ */
+type Bulkhead struct {
+	// Identifies the bulkhead in errors and statistics.
+	Name string
+	// Host names (without port) whose requests run in this bulkhead.
+	Hosts []string
+	// Requests allowed in flight at once.
+	MaxConcurrent int
+	// Requests allowed to wait for a free slot; further requests fail with
+	// a *BulkheadFullError.
+	MaxQueue int
+}
+
+/**
+ * Saturation of a bulkhead.
+ */
+type BulkheadStats struct {
+	// Requests currently in flight.
+	InFlight int
+	// Requests currently waiting for a slot.
+	Queued int
+	// Requests rejected because the queue was full, since the client was
+	// created.
+	Rejected int64
+	// Capacity of the bulkhead, for computing utilization.
+	MaxConcurrent int
+	MaxQueue      int
+}
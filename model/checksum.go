@@ -0,0 +1,22 @@
+package gorequest
+
+/**
+ * Digest algorithms of Checksum.
+ */
+const (
+	ChecksumMD5    = "md5"
+	ChecksumSHA256 = "sha-256"
+)
+
+/**
+ * Expected digest of a response body; see RequestBuilder.WithChecksum.
+ */
+type Checksum struct {
+	// ChecksumMD5 or ChecksumSHA256.
+	Algorithm string
+	// Hex-encoded digest, as published next to artifacts. When empty, the
+	// digest announced by the response is used: its Content-Digest or
+	// Digest header for Algorithm, or else its ETag if that is a digest of
+	// the right size (as object stores give the MD5 of simple uploads).
+	Expected string
+}
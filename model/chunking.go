@@ -0,0 +1,17 @@
+package gorequest
+
+/**
+ * How request bodies are framed over HTTP/1.1; HTTP/2 has no chunked
+ * encoding and ignores it.
+ */
+type Chunking int
+
+const (
+	// Content-Length when the size of the body is known, chunked otherwise.
+	ChunkingAuto Chunking = iota
+	// Always chunked, for streaming endpoints requiring it.
+	ChunkingAlways
+	// Never chunked, for servers rejecting chunked bodies: bodies of
+	// unknown size are read into memory to send their Content-Length.
+	ChunkingNever
+)
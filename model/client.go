@@ -0,0 +1,141 @@
+package gorequest
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+/**
+ * A Client owns the transport (connection pool, TLS settings, timeouts)
+ * shared by every request built against it. Requests that are not given a
+ * Client explicitly run on a package-level default instance.
+ */
+type Client interface {
+	BulkheadStats() map[string]BulkheadStats
+	CircuitStates() map[string]CircuitState
+	// Returns a client sharing the connection pool and the settings of this
+	// one, except for the overrides.
+	Clone(overrides ClientOverrides) Client
+	Close(ctx context.Context) error
+	ConnectionStats() ConnectionStats
+	HttpClient() *http.Client
+	LatencyStats() map[string]LatencyStats
+	RetryStats() RetryStats
+	// Opens a connection to each host (a base URL, or a host name for
+	// HTTPS) ahead of the first request and leaves it in the pool.
+	Warmup(ctx context.Context, hosts ...string) error
+}
+
+/**
+ * Builds a Client instance. Settings not configured explicitly keep the
+ * defaults of the net/http package. Clients built from the same builder
+ * share no state: each has its own limiters, circuits and endpoints.
+ */
+type ClientBuilder interface {
+	Build() Client
+	WithAudit(audit Audit) ClientBuilder
+	WithBalancer(strategy BalancingStrategy, endpoints ...Endpoint) ClientBuilder
+	WithBulkhead(bulkhead Bulkhead) ClientBuilder
+	WithCanonicalQuery() ClientBuilder
+	WithCipherSuites(suites ...uint16) ClientBuilder
+	WithCircuitBreaker(breaker CircuitBreaker) ClientBuilder
+	WithClock(clock Clock) ClientBuilder
+	WithContextHeader(key interface{}, header string) ClientBuilder
+	WithCurvePreferences(curves ...tls.CurveID) ClientBuilder
+	WithDeduplication() ClientBuilder
+	WithDiscovery(discovery Discovery, strategy BalancingStrategy, refresh time.Duration) ClientBuilder
+	WithDownloadRate(bytesPerSecond int, burst int) ClientBuilder
+	WithEndpoints(baseURLs ...string) ClientBuilder
+	WithFaultInjection(faults ...Fault) ClientBuilder
+	WithExpvar(name string) ClientBuilder
+	WithHealthCheck(check HealthCheck) ClientBuilder
+	WithHedging(hedge Hedge) ClientBuilder
+	WithHooks(hooks Hooks) ClientBuilder
+	WithHttpClient(httpClient *http.Client) ClientBuilder
+	WithInterface(name string) ClientBuilder
+	WithKeepAlive(interval time.Duration) ClientBuilder
+	WithLatencyStats(window time.Duration) ClientBuilder
+	WithLocalAddr(addr string) ClientBuilder
+	WithLogging(logging Logging) ClientBuilder
+	WithMaxConcurrentRequests(limit int, queueSize int) ClientBuilder
+	WithMaxRequestsPerHost(limit int, policy OverflowPolicy) ClientBuilder
+	WithMaxTLSVersion(version uint16) ClientBuilder
+	WithMemoizationLimit(maxEntries int) ClientBuilder
+	WithMetrics(collector MetricsCollector) ClientBuilder
+	WithMinTLSVersion(version uint16) ClientBuilder
+	WithNoDelay(noDelay bool) ClientBuilder
+	WithObserver(observer Observer) ClientBuilder
+	WithOffline(replay http.RoundTripper) ClientBuilder
+	WithPinnedKeys(host string, pins ...string) ClientBuilder
+	WithPinningReportOnly(reporter PinningReporter) ClientBuilder
+	WithProfilerLabels() ClientBuilder
+	WithRateLimit(host string, requestsPerSecond float64, burst int, policy OverflowPolicy) ClientBuilder
+	WithRateLimitPacing(reserve int, maxWait time.Duration) ClientBuilder
+	WithRedirectHeaders(rules RedirectHeaders) ClientBuilder
+	WithRedirectPolicy(policy RedirectPolicy) ClientBuilder
+	WithRetry(retry Retry) ClientBuilder
+	WithRetryBudget(budget RetryBudget) ClientBuilder
+	WithRootCAs(pem []byte) ClientBuilder
+	WithRootCADirectory(dir string) ClientBuilder
+	WithRootCAFile(path string) ClientBuilder
+	WithStrictIDNA() ClientBuilder
+	WithTimeout(timeout time.Duration) ClientBuilder
+	WithTracePropagation(propagation TracePropagation) ClientBuilder
+	WithTransport(transport http.RoundTripper) ClientBuilder
+	WithUploadRate(bytesPerSecond int, burst int) ClientBuilder
+}
+
+/**
+ * Settings a client returned by Client.Clone changes from the original, e.g.
+ * to serve several tenants of one API with a single connection pool. Zero
+ * values keep the settings of the original.
+ */
+type ClientOverrides struct {
+	// Authorization of requests not sent with an Authorization header.
+	Auth AuthorizationMethod
+	// Base URL of requests with relative URLs, replacing any endpoints of
+	// the original.
+	BaseURL string
+	// Headers added to every request that does not set them, on top of
+	// those of the original.
+	Headers map[string]string
+	// Timeout of each request.
+	Timeout time.Duration
+}
+
+/**
+ * Receives pinning failures when pinning runs in report-only mode, instead
+ * of the handshake being aborted.
+ */
+type PinningReporter func(err *PinningError)
+
+/**
+ * Defines a constructor type that returns a default ClientBuilder instance.
+ */
+type ClientBuilderConstructor func() ClientBuilder
+
+/**
+ * Connection counters of a single host (host:port).
+ */
+type HostConnectionStats struct {
+	// Connections dialed since the client was created.
+	Dialed int64
+	// Requests that were served over an already open connection.
+	Reused int64
+	// Connections currently open, idle or in use.
+	Open int64
+	// Open connections parked in the idle pool (HTTP/1.x only).
+	Idle int64
+}
+
+/**
+ * Snapshot of the connection pool of a Client: totals plus a breakdown per
+ * host. Only connections dialed by transports created by this package are
+ * tracked; clients wrapping a foreign *http.Client report zeros.
+ */
+type ConnectionStats struct {
+	HostConnectionStats
+	Hosts map[string]HostConnectionStats
+}
@@ -0,0 +1,17 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Source of time of a Client: retry delays and elapsed-time limits,
+ * Retry-After dates, circuit breaker cooldowns and retry budget windows.
+ * Tests can substitute a fake clock to advance time instantly instead of
+ * sleeping; the timeouts of the transport keep using real time.
+ */
+type Clock interface {
+	Now() time.Time
+	// Returns a channel receiving the current time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
@@ -0,0 +1,16 @@
+package gorequest
+
+/**
+ * How the body of a response was encoded on the wire.
+ */
+type ContentEncoding struct {
+	// Whether Body (or the stream) holds the body decompressed, by the
+	// client or by the transport.
+	Decompressed bool
+	// The Content-Encoding of the response, "" for identity.
+	Encoding string
+	// Size of the body as received, before decompression; -1 if unknown,
+	// as for bodies decompressed by the transport. For streamed bodies,
+	// the size received so far.
+	EncodedSize int64
+}
@@ -0,0 +1,26 @@
+package gorequest
+
+import (
+	"context"
+)
+
+/**
+ * The one-call surface of a Client, for application code to depend on
+ * instead of the builders: tests can then swap in a fake Doer, or one built
+ * over a Client using a requestmock transport. Requests are sent as with
+ * RequestBuilder.Build().Do(), which panics on failures; Send returns them
+ * instead.
+ */
+type Doer interface {
+	Delete(url string) Response
+	Do(method string, url string, body RequestBody) Response
+	Get(url string) Response
+	Head(url string) Response
+	Patch(url string, body RequestBody) Response
+	Post(url string, body RequestBody) Response
+	Put(url string, body RequestBody) Response
+	// Sends the request and returns its error if it fails; see Request.Send.
+	Send(method string, url string, body RequestBody) (Response, error)
+	// Returns a Doer sending its requests with ctx.
+	WithContext(ctx context.Context) Doer
+}
@@ -0,0 +1,256 @@
+package gorequest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+/**
+ * Returned by requests run on a Client that has been closed.
+ */
+var ErrClientClosed = errors.New("Client is closed")
+
+/**
+ * Returned by requests run on an offline Client without a replay transport.
+ */
+var ErrOffline = errors.New("Client is offline")
+
+/**
+ * Returned when enqueueing a request on a Queue that has been closed.
+ */
+var ErrQueueClosed = errors.New("Queue is closed")
+
+/**
+ * Returned by URLSigner.Verify for presigned URLs past their expiry.
+ */
+var ErrURLExpired = errors.New("Presigned URL has expired")
+
+/**
+ * Returned by URLSigner.Verify for URLs without a valid signature.
+ */
+var ErrURLSignature = errors.New("Invalid URL signature")
+
+/**
+ * Returned by FetchAll when a collection has more pages than MaxPages; the
+ * items of the pages fetched are kept.
+ */
+var ErrPageLimit = errors.New("Page limit reached before the last page")
+
+/**
+ * Returned by Update when the resource was changed by another writer
+ * between every read and write it attempted.
+ */
+var ErrUpdateConflict = errors.New("Resource kept changing during update")
+
+/**
+ * Delivered to the handler of a queued request dropped to make room for a
+ * newer one; see OverflowDropOldest.
+ */
+var ErrRequestDropped = errors.New("Request dropped from a full queue")
+
+/**
+ * Wraps any error raised by a request that was sent with TLS certificate
+ * verification disabled, so such failures are never mistaken for ordinary
+ * ones.
+ */
+type InsecureRequestError struct {
+	Err error
+}
+
+func (e *InsecureRequestError) Error() string {
+	return "TLS verification disabled for this request: " + e.Err.Error()
+}
+
+func (e *InsecureRequestError) Unwrap() error {
+	return e.Err
+}
+
+/**
+ * Raised, with ClientBuilder.WithStrictIDNA, for hosts with a label mixing
+ * scripts.
+ */
+type MixedScriptError struct {
+	Host    string
+	Scripts []string
+}
+
+func (e *MixedScriptError) Error() string {
+	return fmt.Sprintf("Host %s mixes scripts (%s)", e.Host, strings.Join(e.Scripts, ", "))
+}
+
+/**
+ * Raised when none of the public keys in the verified chains of a host
+ * matches one of the SPKI pins configured for it. Presented lists the keys
+ * of the chain as the host sent it.
+ */
+type PinningError struct {
+	Host      string
+	Presented []string
+}
+
+func (e *PinningError) Error() string {
+	return fmt.Sprintf("No pinned public key presented by %s (presented: %s)", e.Host, strings.Join(e.Presented, ", "))
+}
+
+/**
+ * Raised by a response body read with RequestBuilder.WithChecksum when its
+ * digest is not the one expected. Digests are hex-encoded.
+ */
+type ChecksumError struct {
+	Actual    string
+	Algorithm string
+	Expected  string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("Checksum mismatch: expected %s %s, got %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+/**
+ * Raised when a redirect policy refuses to follow a redirect.
+ */
+type RedirectError struct {
+	Location *url.URL
+	Reason   string
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("Redirect to %s not followed: %s", e.Location, e.Reason)
+}
+
+/**
+ * Raised when a request is rejected because the client already has the
+ * maximum number of requests in flight to its host.
+ */
+type HostLimitError struct {
+	Host  string
+	Limit int
+}
+
+func (e *HostLimitError) Error() string {
+	return fmt.Sprintf("Too many requests in flight to %s (limit %d)", e.Host, e.Limit)
+}
+
+/**
+ * Returned when a request has to be resent (retried, hedged or failed over)
+ * but its body cannot be replayed. Err is the error raised while reopening
+ * the body, if any.
+ */
+type BodyNotReplayableError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *BodyNotReplayableError) Error() string {
+	message := fmt.Sprintf("Cannot resend %s %s: request body cannot be replayed", e.Method, e.URL)
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	return message
+}
+
+func (e *BodyNotReplayableError) Unwrap() error {
+	return e.Err
+}
+
+/**
+ * Returned when the quota a host announced is paced (see
+ * ClientBuilder.WithRateLimitPacing) and the turn of a request is further
+ * away than the maximum wait.
+ */
+type QuotaExhaustedError struct {
+	Host string
+	Wait time.Duration
+}
+
+func (e *QuotaExhaustedError) Error() string {
+	return fmt.Sprintf("Quota of %s exhausted for the next %s", e.Host, e.Wait)
+}
+
+/**
+ * Returned when retries stop because the next attempt (its delay plus the
+ * duration of the previous attempt) would end past the deadline of the
+ * request context, or because the deadline passed while waiting. Err is the
+ * error of the last attempt, if any; StatusCode the status it received
+ * otherwise. Matches context.DeadlineExceeded with errors.Is.
+ */
+type RetryDeadlineError struct {
+	Attempts   int
+	Err        error
+	StatusCode int
+}
+
+func (e *RetryDeadlineError) Error() string {
+	message := fmt.Sprintf("Deadline reached after %d attempts", e.Attempts)
+	if e.Err != nil {
+		return message + ": " + e.Err.Error()
+	}
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: last status %d", message, e.StatusCode)
+	}
+	return message
+}
+
+func (e *RetryDeadlineError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+func (e *RetryDeadlineError) Unwrap() error {
+	return e.Err
+}
+
+/**
+ * Returned when every slot of a bulkhead is taken and its queue is full.
+ */
+type BulkheadFullError struct {
+	Name string
+	Host string
+}
+
+func (e *BulkheadFullError) Error() string {
+	return fmt.Sprintf("Bulkhead %s is full, rejected request to %s", e.Name, e.Host)
+}
+
+/**
+ * Returned when every request slot of the client is taken and the wait queue
+ * is full, or when a Queue rejects a request because it is full.
+ */
+type QueueFullError struct {
+	Limit     int
+	QueueSize int
+}
+
+func (e *QueueFullError) Error() string {
+	return fmt.Sprintf("Request queue is full (%d in flight, %d queued)", e.Limit, e.QueueSize)
+}
+
+/**
+ * Returned when a request exceeds the rate limit of its host and the limit
+ * rejects instead of waiting.
+ */
+type RateLimitError struct {
+	Host string
+	Rate float64
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("Rate limit of %g requests per second exceeded for %s", e.Rate, e.Host)
+}
+
+/**
+ * Returned without contacting the host when its circuit is open (or
+ * half-open with all probes in flight).
+ */
+type CircuitOpenError struct {
+	Host  string
+	State CircuitState
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("Circuit for %s is %s", e.Host, e.State)
+}
@@ -0,0 +1,25 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * A fault injected into a share of the requests of a Client, for testing
+ * how callers cope with slow or failing upstreams. Injected faults go
+ * through retries, circuit breakers and fallbacks like real ones.
+ */
+type Fault struct {
+	// Host name (without port) of the affected requests; empty matches any.
+	Host string
+	// Path prefix of the affected requests; empty matches any.
+	PathPrefix string
+	// Share of the matching requests affected, from 0 to 100.
+	Percentage float64
+	// Delay added before the request is sent.
+	Latency time.Duration
+	// Fails the request with a refused connection instead of sending it.
+	ConnectionError bool
+	// Answers the request with this status instead of sending it.
+	StatusCode int
+}
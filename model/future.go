@@ -0,0 +1,11 @@
+package gorequest
+
+/**
+ * Handle on a request running in the background; see Async.
+ */
+type Future interface {
+	// Closed once the result is available.
+	Done() <-chan struct{}
+	// Waits for the outcome of the request.
+	Result() Result
+}
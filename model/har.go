@@ -0,0 +1,27 @@
+package gorequest
+
+import (
+	"io"
+)
+
+/**
+ * Records exchanges into an HTTP Archive (HAR 1.2) for post-incident
+ * analysis. A recorder is a Logger: attach it with ClientBuilder.WithLogging,
+ * whose settings control redaction, body logging and body size limits.
+ * Recording can be started and stopped at runtime.
+ */
+type HARRecorder interface {
+	Logger
+	// Number of entries recorded.
+	Len() int
+	// Reports whether exchanges are being recorded.
+	Recording() bool
+	// Discards the entries recorded so far.
+	Reset()
+	// Starts recording exchanges.
+	Start()
+	// Stops recording exchanges; recorded entries are kept.
+	Stop()
+	// Writes the recorded entries as a HAR document.
+	WriteTo(w io.Writer) (int64, error)
+}
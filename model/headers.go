@@ -0,0 +1,84 @@
+package gorequest
+
+import (
+	"net/http"
+	"strings"
+)
+
+/**
+ * Names of common headers, in canonical form.
+ */
+const (
+	HeaderAccept        = "Accept"
+	HeaderAuthorization = "Authorization"
+	HeaderCacheControl  = "Cache-Control"
+	HeaderContentType   = "Content-Type"
+	HeaderCookie        = "Cookie"
+	HeaderIfNoneMatch   = "If-None-Match"
+	HeaderUserAgent     = "User-Agent"
+)
+
+/**
+ * Request headers, one value per name, as RequestBuilder.WithHeaders takes
+ * them. Names are canonicalized (content-type becomes Content-Type), so Get
+ * and Del find a header whatever the case it was set with. Being a plain
+ * map, Headers can be converted to and from map[string]string. Setters
+ * return the Headers to be chained:
+ *
+ *   headers := Headers{}.JSON().BearerToken(token).Set("X-Tenant", tenant)
+ */
+type Headers map[string]string
+
+/**
+ * Appends value to the header, comma-separated, as HTTP allows for
+ * repeated headers.
+ */
+func (h Headers) Add(name string, value string) Headers {
+	name = http.CanonicalHeaderKey(name)
+	if current, ok := h[name]; ok && current != "" {
+		value = current + ", " + value
+	}
+	h[name] = value
+	return h
+}
+
+func (h Headers) Del(name string) Headers {
+	for key := range h {
+		if strings.EqualFold(key, name) {
+			delete(h, key)
+		}
+	}
+	return h
+}
+
+func (h Headers) Get(name string) string {
+	if value, ok := h[http.CanonicalHeaderKey(name)]; ok {
+		return value
+	}
+	for key, value := range h {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+func (h Headers) Set(name string, value string) Headers {
+	h.Del(name)
+	h[http.CanonicalHeaderKey(name)] = value
+	return h
+}
+
+/**
+ * Sets the Authorization header to a bearer token.
+ */
+func (h Headers) BearerToken(token string) Headers {
+	return h.Set(HeaderAuthorization, "Bearer "+token)
+}
+
+/**
+ * Declares a JSON request body and asks for a JSON response.
+ */
+func (h Headers) JSON() Headers {
+	return h.Set(HeaderAccept, "application/json").Set(HeaderContentType, "application/json")
+}
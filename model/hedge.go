@@ -0,0 +1,18 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Hedged request settings. When an attempt has not completed after Delay, a
+ * copy of the request is sent (to the next endpoint when the client balances
+ * across several) and the first successful response wins; the others are
+ * cancelled. Only idempotent requests are hedged.
+ */
+type Hedge struct {
+	// Time to wait for a response before sending the next copy.
+	Delay time.Duration
+	// Copies sent in addition to the original request; zero means 1.
+	MaxHedges int
+}
@@ -0,0 +1,46 @@
+package gorequest
+
+import (
+	"net/http"
+)
+
+/**
+ * Cross-cutting behavior attached to every request of a Client, e.g. header
+ * stamping, logging or metrics. Any hook may be nil. Hooks are called
+ * synchronously, in the order they were registered, and must be safe for
+ * concurrent use.
+ */
+type Hooks struct {
+	OnBeforeRequest BeforeRequestHook
+	OnAfterResponse AfterResponseHook
+	OnError         ErrorHook
+	OnRetry         RetryHook
+}
+
+/**
+ * Called before every attempt of a request with the request about to be
+ * sent, which it may modify. Hedged copies and attempts on another endpoint
+ * count as attempts, and relative URLs are already resolved against the
+ * endpoint. Returning an error fails the attempt with that error.
+ */
+type BeforeRequestHook func(req *http.Request) error
+
+/**
+ * Called once a request succeeded, with the response of its last attempt.
+ * Returns the response handed to the caller, either resp itself or a
+ * replacement (see NewStaticResponse); nil keeps resp. Returning an error
+ * fails the request with that error.
+ */
+type AfterResponseHook func(req *http.Request, resp Response) (Response, error)
+
+/**
+ * Called once a request failed for good, with the error it fails with,
+ * before any fallback runs.
+ */
+type ErrorHook func(req *http.Request, err error)
+
+/**
+ * Called before a retry with the outcome of the attempt being retried:
+ * either resp or err is set. attempt starts at 1.
+ */
+type RetryHook func(req *http.Request, attempt int, resp *http.Response, err error)
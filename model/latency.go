@@ -0,0 +1,20 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Rolling latency statistics of a single host (host:port), computed over
+ * the exchanges completed within the tracking window.
+ */
+type LatencyStats struct {
+	// Exchanges completed within the window.
+	Count int
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	// Share of the exchanges, between 0 and 1, that failed with a
+	// connection error or a 5xx response.
+	ErrorRate float64
+}
@@ -0,0 +1,17 @@
+package gorequest
+
+/**
+ * What a limiter does with a request that exceeds its limit.
+ */
+type OverflowPolicy int
+
+const (
+	// Wait until capacity becomes available.
+	OverflowBlock OverflowPolicy = iota
+	// Fail immediately with a typed error.
+	OverflowReject
+	// Make room by dropping the oldest waiting request, which fails with
+	// ErrRequestDropped. Only supported by Queues; other limiters wait as
+	// with OverflowBlock.
+	OverflowDropOldest
+)
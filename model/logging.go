@@ -0,0 +1,60 @@
+package gorequest
+
+import (
+	"net/http"
+	"time"
+)
+
+/**
+ * Receives an entry for every exchange sent by a Client.
+ */
+type Logger interface {
+	Log(entry LogEntry)
+}
+
+/**
+ * One exchange: a single attempt of a request, after redaction. Redirects
+ * followed within the attempt are not logged separately.
+ */
+type LogEntry struct {
+	Method     string
+	URL        string
+	Proto      string
+	StatusCode int
+	Err        error
+	StartedAt  time.Time
+	Duration   time.Duration
+	// Attempt number of the request, starting at 1.
+	Attempt int
+	// Size of the request and response bodies; -1 when unknown.
+	BytesSent     int64
+	BytesReceived int64
+	// Headers, with redacted values replaced.
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+	// Bodies, truncated and redacted; only set when LogBodies is enabled.
+	RequestBody  []byte
+	ResponseBody []byte
+}
+
+/**
+ * Logging settings of a Client.
+ */
+type Logging struct {
+	Logger Logger
+	// Headers whose values are replaced by "[REDACTED]", in addition to
+	// Authorization, Proxy-Authorization, Cookie and Set-Cookie.
+	RedactHeaders []string
+	// Includes request and response bodies in the entries.
+	LogBodies bool
+	// Bodies are truncated to this size; zero means 4 KiB.
+	MaxBodyBytes int
+	// Rewrites bodies before they are logged, e.g. to mask secrets.
+	RedactBody func(body []byte) []byte
+	// Logs the values of the query of URLs as sent; otherwise they are
+	// replaced by "[REDACTED]", as they may carry tokens.
+	LogQuery bool
+	// Share of successful exchanges logged, from 0 to 1; zero logs all of
+	// them. Failed exchanges are always logged.
+	SampleRate float64
+}
@@ -0,0 +1,28 @@
+package gorequest
+
+import "time"
+
+/**
+ * Settings of a long poll: a request sent over and over, that the server
+ * holds until it has data or its own timeout elapses. See LongPoll.
+ */
+type LongPoll struct {
+	// Delays between reconnections after failures; only BaseDelay,
+	// MaxDelay, Multiplier and NoJitter are used. Defaults to 1s doubling up
+	// to 30s, with jitter.
+	Backoff Retry
+	// Source of time of the delays; defaults to the system clock.
+	Clock Clock
+	// Reports whether resp timed out without data. Defaults to 204 and 304
+	// responses and responses without a body.
+	Empty func(resp Response) bool
+	// Shortest time between the starts of a poll that came back empty and
+	// of the next one, so servers answering right away are not polled in
+	// a tight loop. Defaults to one second.
+	MinInterval time.Duration
+	// Returns the request of the next poll, given the last response with
+	// data (nil before the first one), e.g. to pass its cursor on. The
+	// request asks for the server-side timeout (e.g. ?wait=30) and its
+	// client must allow for it.
+	Next func(last Response) RequestBuilder
+}
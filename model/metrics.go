@@ -0,0 +1,26 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Vendor-neutral sink for client metrics, implemented by adapters for
+ * Prometheus, statsd or any other system. Tags are low-cardinality labels
+ * (method, host, status); adapters must not keep the map. Methods are
+ * called synchronously and must be safe for concurrent use.
+ *
+ * A Client reports:
+ *   - counter "requests" (method, host, status): exchanges sent, status
+ *     being "error" when no response was received
+ *   - counter "errors" (method, host): exchanges without a response
+ *   - counter "retries" (method, host): attempts after the first one
+ *   - counter "bytes_sent", "bytes_received" (host): body sizes
+ *   - timer "request_duration" (method, host, status)
+ *   - gauge "in_flight": requests in progress
+ */
+type MetricsCollector interface {
+	AddCounter(name string, delta float64, tags map[string]string)
+	ObserveTimer(name string, duration time.Duration, tags map[string]string)
+	SetGauge(name string, value float64, tags map[string]string)
+}
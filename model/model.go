@@ -5,15 +5,30 @@ package gorequest
  */
 
 import (
+	"context"
 	"bytes"
+	"io"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"time"
 )
 
 /**
- *  TODO: describe this interface.
+ * A request ready to be sent, built by a RequestBuilder. A Request is meant
+ * to be sent once and is not safe for concurrent use: its body is consumed
+ * and its headers are completed while it is sent. Build one Request per
+ * call instead; the Client they share, including the package-level default
+ * one, is safe for concurrent use. Builders are not safe for concurrent use
+ * either.
  */
 type Request interface {
+	// Sends the request and panics if it fails; see Send.
 	Do() Response
+	// Sends the request and returns its error if it fails, including the
+	// errors found while building it (a missing URL, a body that cannot be
+	// serialized). Errors raised by this package wrap their cause.
+	Send() (Response, error)
 }
 
 /**
@@ -21,7 +36,12 @@ type Request interface {
  */
 type Response interface {
 	Body() []byte
+	ContentEncoding() ContentEncoding
+	InsecureSkipVerify() bool
+	RateLimit() (RateLimit, bool)
+	RedirectChain() []*url.URL
 	Response() *http.Response
+	Timings() Timings
 }
 
 /**
@@ -44,13 +64,36 @@ type RequestBody interface {
  */
 type RequestBuilder interface {
 	Build() Request
+	BuildHttpRequest(ctx context.Context) (*http.Request, error)
+	WithAcceptEncoding(encodings ...string) RequestBuilder
 	WithBasicAuth(user string, password string) RequestBuilder
 	WithBearerAuth(token string) RequestBuilder
 	WithBody(body RequestBody) RequestBuilder
+	WithCacheTTL(ttl time.Duration) RequestBuilder
+	WithChecksum(checksum Checksum) RequestBuilder
+	WithChunking(chunking Chunking) RequestBuilder
+	WithClient(client Client) RequestBuilder
+	WithClientTrace(trace *httptrace.ClientTrace) RequestBuilder
+	WithContentDigest(algorithm string) RequestBuilder
+	WithContext(ctx context.Context) RequestBuilder
 	WithCustomAuth(auth AuthorizationMethod) RequestBuilder
+	WithDebug(output io.Writer, bodies bool) RequestBuilder
+	WithFallback(fallback Fallback) RequestBuilder
 	WithHeader(name, value string) RequestBuilder
+	WithHeaders(headers Headers) RequestBuilder
+	WithHedging(hedge Hedge) RequestBuilder
+	WithInsecureSkipVerify() RequestBuilder
 	WithMethod(method string) RequestBuilder
+	WithPriority(priority Priority) RequestBuilder
+	WithProfilerLabels(labels ...string) RequestBuilder
+	WithRedirectHeaders(rules RedirectHeaders) RequestBuilder
+	WithRedirectPolicy(policy RedirectPolicy) RequestBuilder
+	WithResponseSink(sink io.Writer) RequestBuilder
+	WithResponseStream() RequestBuilder
+	WithRetry(retry Retry) RequestBuilder
+	WithServerName(name string) RequestBuilder
 	WithUrl(url string) RequestBuilder
+	WithoutDecompression() RequestBuilder
 }
 
 /**
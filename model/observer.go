@@ -0,0 +1,31 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Receives a summary of every exchange sent by a Client, e.g. to feed a
+ * metrics system. Observers are called synchronously and must be safe for
+ * concurrent use.
+ */
+type Observer interface {
+	ObserveExchange(exchange Exchange)
+}
+
+/**
+ * One exchange: a single attempt of a request. Err is set when no response
+ * was received.
+ */
+type Exchange struct {
+	Method     string
+	Host       string
+	StatusCode int
+	Err        error
+	Duration   time.Duration
+	// Attempt number of the request, starting at 1.
+	Attempt int
+	// Size of the request and response bodies; -1 when unknown.
+	BytesSent     int64
+	BytesReceived int64
+}
@@ -0,0 +1,11 @@
+package gorequest
+
+/**
+ * A setting for the functional constructors NewClient and NewRequest, as an
+ * alternative to the builders. Client configures a ClientBuilder and Request
+ * a RequestBuilder; an Option leaves nil the one it does not apply to.
+ */
+type Option struct {
+	Client  func(builder ClientBuilder)
+	Request func(builder RequestBuilder)
+}
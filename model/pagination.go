@@ -0,0 +1,30 @@
+package gorequest
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/**
+ * How FetchAll walks the pages of a collection and collects their items.
+ * Items are taken from JSON bodies and handed to Each, appended to Into,
+ * or both.
+ */
+type Pagination struct {
+	// Called with every item, in order; returning an error stops the walk.
+	Each func(item json.RawMessage) error
+	// Pointer to a slice the items are decoded and appended to.
+	Into interface{}
+	// Dot-separated path of the member of the body holding the items of a
+	// page, e.g. "data" or "result.items"; empty when the body is the array.
+	Items string
+	// Upper bound of the pages fetched; zero means no bound. The walk fails
+	// with ErrPageLimit if there are more.
+	MaxPages int
+	// Upper bound of the time spent on the walk; zero means no bound.
+	MaxDuration time.Duration
+	// Returns the URL of the page following page, relative to its URL, or
+	// "" after the last one. Defaults to the rel="next" target of the Link
+	// header; see NextCursor for cursor pagination.
+	Next func(page Response) (string, error)
+}
@@ -0,0 +1,15 @@
+package gorequest
+
+/**
+ * The requests of a Postman collection (format v2.1), as Option templates.
+ * Requests are named after their folders and their own name, joined with
+ * "/", e.g. "Users/Get user".
+ */
+type PostmanCollection interface {
+	// Names of the requests, in the order of the collection.
+	Names() []string
+	// Options reproducing the named request, with its {{variables}}
+	// replaced; variables take precedence over the environment, which takes
+	// precedence over the variables of the collection.
+	Options(name string, variables map[string]string) ([]Option, error)
+}
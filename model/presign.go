@@ -0,0 +1,34 @@
+package gorequest
+
+import (
+	"net/url"
+	"time"
+)
+
+/**
+ * Settings of a URLSigner.
+ */
+type URLSigning struct {
+	// Defaults to the system clock.
+	Clock Clock
+	// HMAC-SHA256 key signing URLs.
+	Key []byte
+	// Keys still accepted by Verify after a rotation, until the URLs signed
+	// with them expire.
+	PreviousKeys [][]byte
+}
+
+/**
+ * Generates presigned URLs, to hand to browsers or third parties, and
+ * verifies them when they come back. A URL is signed for one method and
+ * until an expiry, both covered by an HMAC along with its path and query;
+ * the scheme and host are not, so URLs survive proxies and load balancers.
+ */
+type URLSigner interface {
+	// Returns rawURL with the X-Expires and X-Signature query parameters
+	// added, valid for method during ttl.
+	Sign(method string, rawURL string, ttl time.Duration) (string, error)
+	// Checks a URL received with method, e.g. req.Method and req.URL of a
+	// server request. Returns ErrURLSignature or ErrURLExpired.
+	Verify(method string, u *url.URL) error
+}
@@ -0,0 +1,17 @@
+package gorequest
+
+/**
+ * Order in which waiting requests are dispatched when a concurrency limit,
+ * a bulkhead or a Queue is saturated: higher priorities first, then in
+ * arrival order. Any value may be used; the constants are conventions.
+ */
+type Priority int
+
+const (
+	// Background bulk traffic.
+	PriorityLow Priority = -10
+	// The priority of requests that do not set one.
+	PriorityNormal Priority = 0
+	// Latency-sensitive calls.
+	PriorityHigh Priority = 10
+)
@@ -0,0 +1,65 @@
+package gorequest
+
+import (
+	"context"
+	"time"
+)
+
+/**
+ * Outcome of a request run by a Queue: the response, or the error the
+ * request failed with.
+ */
+type Result struct {
+	Response Response
+	Err      error
+}
+
+/**
+ * Receives the outcome of a queued request, on the worker that ran it.
+ */
+type ResultHandler func(result Result)
+
+/**
+ * Runs requests asynchronously on a pool of workers. Requests keep the
+ * settings (retries, rate limits, ...) of the Client they were built against.
+ */
+type Queue interface {
+	// Queues req and returns a handle on its outcome. Fails as Enqueue.
+	Async(req Request) (Future, error)
+	// Closes the queue to new requests and waits until the queued ones have
+	// run or ctx is done, whichever comes first. Scheduled requests not yet
+	// queued are dropped; their handlers receive ErrQueueClosed.
+	Close(ctx context.Context) error
+	// Queues req; handler receives its outcome. Fails with ErrQueueClosed
+	// or, when the queue rejects overflow (or has nothing to drop), a
+	// *QueueFullError.
+	Enqueue(req Request, handler ResultHandler) error
+	// Queues req after d has elapsed. Errors raised when it is queued (full
+	// or closed queue) are delivered to handler.
+	EnqueueAfter(d time.Duration, req Request, handler ResultHandler) (CancelFunc, error)
+	// Queues req at t, as EnqueueAfter.
+	EnqueueAt(t time.Time, req Request, handler ResultHandler) (CancelFunc, error)
+	// Queues req and returns a channel receiving its outcome.
+	Submit(req Request) (<-chan Result, error)
+}
+
+/**
+ * Cancels a scheduled request. Reports false when the request was already
+ * queued or cancelled.
+ */
+type CancelFunc func() bool
+
+/**
+ * Builds a Queue instance.
+ */
+type QueueBuilder interface {
+	Build() Queue
+	WithCapacity(capacity int) QueueBuilder
+	WithOverflowPolicy(policy OverflowPolicy) QueueBuilder
+	WithWorkers(workers int) QueueBuilder
+}
+
+/**
+ * Defines a constructor type that returns a default QueueBuilder instance.
+ */
+type QueueBuilderConstructor func() QueueBuilder
@@ -0,0 +1,19 @@
+package gorequest
+
+/**
+ * The part of a resource sent in a 206 Partial Content response, from its
+ * Content-Range header: bytes First to Last, both included, of Size bytes
+ * (-1 when the server does not know the size).
+ */
+type ContentRange struct {
+	First int64
+	Last  int64
+	Size  int64
+}
+
+/**
+ * Number of bytes of the part.
+ */
+func (r ContentRange) Length() int64 {
+	return r.Last - r.First + 1
+}
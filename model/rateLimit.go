@@ -0,0 +1,19 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Request quota announced by a response through the IETF RateLimit fields
+ * or the X-RateLimit-* headers; see Response.RateLimit.
+ */
+type RateLimit struct {
+	// Requests allowed per window; -1 when not announced.
+	Limit int64
+	// Requests left in the current window; -1 when not announced.
+	Remaining int64
+	// Time until the window resets, counted from the response; -1 when not
+	// announced.
+	Reset time.Duration
+}
@@ -0,0 +1,32 @@
+package gorequest
+
+import (
+	"net/http"
+)
+
+/**
+ * Decides whether a redirect is followed. It has the same contract as
+ * http.Client.CheckRedirect: req is the upcoming request and via holds the
+ * requests made so far, oldest first. Returning http.ErrUseLastResponse stops
+ * following and returns the redirect response itself.
+ */
+type RedirectPolicy interface {
+	CheckRedirect(req *http.Request, via []*http.Request) error
+}
+
+/**
+ * Controls which headers of the original request are carried over when a
+ * redirect is followed. Header names are case insensitive.
+ *
+ * Authorization, Proxy-Authorization, Cookie, Cookie2 and WWW-Authenticate
+ * are always stripped when a hop leaves the origin (scheme, host and port)
+ * of the original request, unless they are listed in Preserve.
+ */
+type RedirectHeaders struct {
+	// Forwarded on every hop, including cross-origin ones.
+	Preserve []string
+	// Removed on hops that leave the origin of the original request.
+	StripCrossOrigin []string
+	// Never forwarded, not even to the same origin.
+	Drop []string
+}
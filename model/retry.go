@@ -0,0 +1,94 @@
+package gorequest
+
+import (
+	"net/http"
+	"time"
+)
+
+/**
+ * Automatic retry settings. Attempt n (starting at 1) is followed by a delay
+ * of BaseDelay * Multiplier^(n-1), capped at MaxDelay; with full jitter (the
+ * default) the actual delay is uniformly random between zero and that value.
+ *
+ * Which outcomes are retried is decided by RetryIf. Requests with methods
+ * that are not idempotent (POST, PATCH) are never retried unless
+ * RetryNonIdempotent is set.
+ *
+ * Requests without retry settings are still retried once, if idempotent and
+ * replayable, after a reset connection, a connection closed mid-response or
+ * a temporary DNS failure. Any Retry replaces that default; MaxAttempts 1
+ * disables it.
+ */
+type Retry struct {
+	// Total number of attempts, including the first one. Values below 2
+	// disable retries.
+	MaxAttempts int
+	// Delay after the first attempt.
+	BaseDelay time.Duration
+	// Upper bound of any single delay; zero means no cap.
+	MaxDelay time.Duration
+	// Upper bound of the time spent on all attempts: no retry is started
+	// if its delay would end past it. Zero means no bound.
+	MaxElapsed time.Duration
+	// Growth factor between delays; values below 1 mean 2.
+	Multiplier float64
+	// Disables full jitter, making delays deterministic.
+	NoJitter bool
+	// Decides whether an attempt is retried; nil retries 429, 502, 503 and
+	// 504 responses as well as transient network errors (timeouts, refused
+	// or reset connections, unexpected EOF, temporary DNS failures).
+	RetryIf RetryCondition
+	// Allows retrying POST and PATCH requests.
+	RetryNonIdempotent bool
+	// Buffers request bodies without a GetBody function in memory before
+	// the first attempt, so they can be resent. Otherwise such requests
+	// fail with a *BodyNotReplayableError when a retry is due.
+	BufferBody bool
+	// Upper bound of the delay a 429 or 503 response may request through
+	// Retry-After or rate-limit reset headers, which replaces the computed
+	// backoff. Zero means one minute; a negative value ignores the headers.
+	MaxRetryAfter time.Duration
+}
+
+/**
+ * Receives the outcome of an attempt: either a response (whose body has
+ * already been read) or an error.
+ */
+type RetryCondition func(resp *http.Response, err error) bool
+
+/**
+ * Produces a substitute outcome for a request that failed after all of its
+ * attempts, e.g. a cached or default payload. Returning an error fails the
+ * request with that error instead.
+ */
+type Fallback func(err error) (Response, error)
+
+/**
+ * Caps the extra load a Client generates through retries, so retries cannot
+ * amplify an outage. Over a sliding window, retries may not exceed Ratio
+ * times the number of requests, except for MinRetries which are always
+ * allowed.
+ */
+type RetryBudget struct {
+	// Allowed retries per request, e.g. 0.2 for at most 20% extra load.
+	Ratio float64
+	// Retries allowed per window regardless of the ratio.
+	MinRetries int
+	// Length of the sliding window; zero means 10 seconds.
+	Window time.Duration
+}
+
+/**
+ * Retry counters of a Client since it was created.
+ */
+type RetryStats struct {
+	// Retries performed, i.e. attempts after the first one.
+	Retries int64
+	// Retries skipped because the retry budget was exhausted.
+	BudgetExhausted int64
+	// Retries skipped because they would have exceeded MaxElapsed.
+	MaxElapsedExceeded int64
+	// Retries skipped because they would have ended past the deadline of
+	// the request context.
+	DeadlineExceeded int64
+}
@@ -0,0 +1,18 @@
+package gorequest
+
+/**
+ * Credentials and scope of AWS Signature Version 4, for S3 and other AWS
+ * (or AWS-compatible) services; see SigV4Hooks.
+ */
+type SigV4 struct {
+	AccessKeyID string
+	// Defaults to the system clock.
+	Clock Clock
+	// e.g. "us-east-1".
+	Region          string
+	SecretAccessKey string
+	// e.g. "s3".
+	Service string
+	// Sent as X-Amz-Security-Token with temporary credentials.
+	SessionToken string
+}
@@ -0,0 +1,49 @@
+package gorequest
+
+import (
+	"net/http"
+	"time"
+)
+
+/**
+ * An httptest.Server answering from a route table and capturing the requests
+ * it receives, for tests of code built on this package.
+ */
+type TestServer interface {
+	// Shuts the server down.
+	Close()
+	// Sets the response of route: "METHOD /path", or "/path" for any
+	// method. Unknown routes get a 404.
+	Handle(route string, response TestResponse) TestServer
+	// Returns a RequestBuilder aimed at path on the server, using a Client
+	// that trusts it.
+	Request(path string) RequestBuilder
+	// Returns the requests received so far, in order.
+	Requests() []CapturedRequest
+	// Returns the base URL of the server.
+	URL() string
+	// Delays every response by latency, on top of the delay of its route.
+	WithLatency(latency time.Duration) TestServer
+}
+
+/**
+ * Canned response of a TestServer route. Zero Status means 200.
+ */
+type TestResponse struct {
+	Status int
+	Body   string
+	Header http.Header
+	Delay  time.Duration
+}
+
+/**
+ * A request received by a TestServer.
+ */
+type CapturedRequest struct {
+	Method string
+	// Path and query string.
+	URL        string
+	Header     http.Header
+	Body       []byte
+	ReceivedAt time.Time
+}
@@ -0,0 +1,26 @@
+package gorequest
+
+import (
+	"time"
+)
+
+/**
+ * Where the time of an exchange went. When redirects were followed, each
+ * phase adds up the time spent in it across hops. Phases that did not take
+ * place, e.g. DNS and connect on a reused connection, are zero.
+ */
+type Timings struct {
+	DNSLookup time.Duration
+	// Establishing the TCP connection.
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	// From the request being written to the first byte of the response.
+	TimeToFirstByte time.Duration
+	// Reading the response body.
+	Download time.Duration
+	// From sending the request to the end of the body, including waits for
+	// a connection from the pool.
+	Total time.Duration
+	// Whether the (last) connection was taken from the idle pool.
+	ConnectionReused bool
+}
@@ -0,0 +1,38 @@
+package gorequest
+
+import (
+	"context"
+)
+
+/**
+ * W3C Trace Context (https://www.w3.org/TR/trace-context/) of the operation
+ * a request is part of. Requests whose context carries one are sent with
+ * traceparent and tracestate headers, so the server can join the trace.
+ */
+type TraceContext struct {
+	// 32 lowercase hex digits, not all zero.
+	TraceID string
+	// 16 lowercase hex digits, not all zero: the span of the caller, sent
+	// as the parent-id of the request.
+	SpanID  string
+	Sampled bool
+	// Vendor-specific tracestate value, forwarded as is.
+	State string
+}
+
+/**
+ * Reads the trace context of a request from its context.Context.
+ */
+type TraceExtractor func(ctx context.Context) (TraceContext, bool)
+
+/**
+ * Trace propagation settings of a Client.
+ */
+type TracePropagation struct {
+	// Also sends B3 headers (X-B3-TraceId, X-B3-SpanId, X-B3-Sampled) for
+	// Zipkin-based systems.
+	B3 bool
+	// Reads the trace context, e.g. from the span of a tracing library; nil
+	// reads the value stored with ContextWithTrace.
+	Extract TraceExtractor
+}
@@ -0,0 +1,18 @@
+package gorequest
+
+/**
+ * A read-modify-write of a resource, guarded by its ETag so that concurrent
+ * writers do not overwrite each other's changes. See Update.
+ */
+type Update struct {
+	// Times the resource is read and written before giving up on
+	// conflicting writers; defaults to 3.
+	Attempts int
+	// Returns the request writing the resource given its current state, the
+	// response to Read, e.g. a PUT of its body with the changes merged in.
+	// Called again with the new state after every conflict. If-Match is set
+	// by Update.
+	Merge func(current Response) (RequestBuilder, error)
+	// Returns the request reading the resource, e.g. a GET of its URL.
+	Read func() RequestBuilder
+}
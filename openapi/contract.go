@@ -0,0 +1,433 @@
+package gorequest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	oas "github.com/demianlessa/gorequest/internal/oas"
+	model "github.com/demianlessa/gorequest/model"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**
+ * What a Contract does on a violation.
+ */
+type Mode int
+
+const (
+	// Fails the request with the *Violation, e.g. in tests and staging.
+	ModeEnforce Mode = iota
+	// Only reports the violation to OnViolation.
+	ModeReport
+)
+
+/**
+ * The problems found with a request or with its response.
+ */
+type Violation struct {
+	Method   string
+	Problems []string
+	// Whether the problems are with the response rather than the request.
+	Response bool
+	// The response status, when Response is set.
+	Status int
+	URL    string
+}
+
+func (v *Violation) Error() string {
+
+	subject := "request"
+
+	if v.Response {
+		subject = fmt.Sprintf("%d response to", v.Status)
+	}
+
+	return fmt.Sprintf("OpenAPI contract violated by the %s %s %s: %s", subject, v.Method, v.URL, strings.Join(v.Problems, "; "))
+}
+
+/**
+ * Validates the traffic of a Client against Spec; register its Hooks with
+ * ClientBuilder.WithHooks. OnViolation, if set, is called with every
+ * violation whatever the mode.
+ */
+type Contract struct {
+	Mode        Mode
+	OnViolation func(v *Violation)
+	Spec        *Spec
+}
+
+/**
+ * Returns hooks validating every request before it is sent and every
+ * response once received.
+ */
+func (c *Contract) Hooks() model.Hooks {
+	return model.Hooks{
+		OnBeforeRequest: func(req *http.Request) error {
+
+			body, err := readBody(req)
+
+			if err != nil {
+				return err
+			}
+
+			return c.check(req, c.Spec.ValidateRequest(req, body), nil)
+		},
+		OnAfterResponse: func(req *http.Request, resp model.Response) (model.Response, error) {
+
+			if resp.Response() == nil {
+				return nil, nil
+			}
+
+			if err := c.check(req, c.Spec.ValidateResponse(req, resp.Response(), resp.Body()), resp.Response()); err != nil {
+				return nil, err
+			}
+
+			return nil, nil
+		},
+	}
+}
+
+func (c *Contract) check(req *http.Request, problems []string, resp *http.Response) error {
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	violation := &Violation{
+		Method:   req.Method,
+		Problems: problems,
+		URL:      req.URL.String(),
+	}
+
+	if resp != nil {
+		violation.Response = true
+		violation.Status = resp.StatusCode
+	}
+
+	if c.OnViolation != nil {
+		c.OnViolation(violation)
+	}
+
+	if c.Mode == ModeEnforce {
+		return violation
+	}
+
+	return nil
+}
+
+/**
+ * Validates a request against its operation: the path and method must be
+ * documented, and the parameters and the body (which may be nil) must match.
+ * Returns the problems found, if any.
+ */
+func (s *Spec) ValidateRequest(req *http.Request, body []byte) []string {
+
+	item, op, pathParams := s.operation(req.Method, req.URL.Path)
+
+	if op == nil {
+		return []string{fmt.Sprintf("no operation documented for %s %s", req.Method, req.URL.Path)}
+	}
+
+	var problems []string
+
+	for _, param := range s.parameters(item, op) {
+		problems = append(problems, s.validateParameter(req, param, pathParams)...)
+	}
+
+	documented := s.document.RequestBody(op.RequestBody)
+
+	if documented == nil {
+		return problems
+	}
+
+	if len(body) == 0 {
+		if documented.Required {
+			problems = append(problems, "missing required request body")
+		}
+		return problems
+	}
+
+	return append(problems, s.validateContent(documented.Content, req.Header.Get("Content-Type"), body, "request body")...)
+}
+
+/**
+ * Validates a response against the operation of req: its status must be
+ * documented and its body must match. Returns the problems found, if any.
+ */
+func (s *Spec) ValidateResponse(req *http.Request, resp *http.Response, body []byte) []string {
+
+	_, op, _ := s.operation(req.Method, req.URL.Path)
+
+	if op == nil {
+		return []string{fmt.Sprintf("no operation documented for %s %s", req.Method, req.URL.Path)}
+	}
+
+	documented := s.document.Response(statusResponse(op.Responses, resp.StatusCode))
+
+	if documented == nil {
+		return []string{fmt.Sprintf("undocumented status %d", resp.StatusCode)}
+	}
+
+	if len(body) == 0 || len(documented.Content) == 0 {
+		return nil
+	}
+
+	return s.validateContent(documented.Content, resp.Header.Get("Content-Type"), body, "response body")
+}
+
+/**
+ * Finds the operation serving method and path, preferring the path
+ * templates with the most literal segments, and returns the values of its
+ * path parameters.
+ */
+func (s *Spec) operation(method string, path string) (*oas.PathItem, *oas.Operation, map[string]string) {
+
+	if s.basePath != "" && strings.HasPrefix(path, s.basePath) {
+		path = strings.TrimPrefix(path, s.basePath)
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	templates := make([]string, 0, len(s.document.Paths))
+	for template := range s.document.Paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	var best *oas.PathItem
+	var bestParams map[string]string
+	bestLiterals := -1
+
+	for _, template := range templates {
+		params, literals, ok := matchTemplate(strings.Split(strings.Trim(template, "/"), "/"), segments)
+		if !ok || literals <= bestLiterals {
+			continue
+		}
+		item := s.document.Paths[template]
+		if item.Operation(method) == nil {
+			continue
+		}
+		best, bestParams, bestLiterals = item, params, literals
+	}
+
+	if best == nil {
+		return nil, nil, nil
+	}
+
+	return best, best.Operation(method), bestParams
+}
+
+func matchTemplate(template []string, segments []string) (map[string]string, int, bool) {
+
+	if len(template) != len(segments) {
+		return nil, 0, false
+	}
+
+	params := make(map[string]string)
+	literals := 0
+
+	for i, part := range template {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if segments[i] == "" {
+				return nil, 0, false
+			}
+			params[part[1:len(part)-1]] = segments[i]
+			continue
+		}
+		if part != segments[i] {
+			return nil, 0, false
+		}
+		literals++
+	}
+
+	return params, literals, true
+}
+
+/**
+ * Returns the parameters of op, including those of its path item it does
+ * not override.
+ */
+func (s *Spec) parameters(item *oas.PathItem, op *oas.Operation) []*oas.Parameter {
+
+	var params []*oas.Parameter
+	seen := make(map[string]bool)
+
+	for _, list := range [][]*oas.Parameter{op.Parameters, item.Parameters} {
+		for _, param := range list {
+			param = s.document.Parameter(param)
+			if param == nil || seen[param.In+" "+param.Name] {
+				continue
+			}
+			seen[param.In+" "+param.Name] = true
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+func (s *Spec) validateParameter(req *http.Request, param *oas.Parameter, pathParams map[string]string) []string {
+
+	var values []string
+
+	switch param.In {
+	case "path":
+		if value, ok := pathParams[param.Name]; ok {
+			values = []string{value}
+		}
+	case "query":
+		values = req.URL.Query()[param.Name]
+	case "header":
+		values = req.Header.Values(param.Name)
+	case "cookie":
+		if cookie, err := req.Cookie(param.Name); err == nil {
+			values = []string{cookie.Value}
+		}
+	}
+
+	location := fmt.Sprintf("%s parameter %q", param.In, param.Name)
+
+	if len(values) == 0 {
+		if param.Required || param.In == "path" {
+			return []string{"missing required " + location}
+		}
+		return nil
+	}
+
+	sc := s.document.Schema(param.Schema)
+
+	if sc == nil {
+		return nil
+	}
+
+	if sc.Type == "array" {
+		items := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			items = append(items, coerce(value, s.document.Schema(sc.Items)))
+		}
+		return s.validate(items, sc, location)
+	}
+
+	return s.validate(coerce(values[0], sc), sc, location)
+}
+
+/**
+ * Converts a parameter value to the JSON type its schema expects, leaving
+ * it a string when it does not parse.
+ */
+func coerce(value string, sc *oas.Schema) interface{} {
+
+	if sc == nil {
+		return value
+	}
+
+	switch sc.Type {
+	case "integer", "number":
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return number
+		}
+	case "boolean":
+		if boolean, err := strconv.ParseBool(value); err == nil {
+			return boolean
+		}
+	}
+
+	return value
+}
+
+func (s *Spec) validateContent(content map[string]*oas.MediaType, contentType string, body []byte, location string) []string {
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	if err != nil {
+		mediaType = "application/octet-stream"
+	}
+
+	documented := lookupMediaType(content, mediaType)
+
+	if documented == nil {
+		return []string{fmt.Sprintf("undocumented %s content type %q", location, mediaType)}
+	}
+
+	if documented.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	var value interface{}
+
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("invalid JSON in %s: %v", location, err)}
+	}
+
+	return s.validate(value, documented.Schema, "$")
+}
+
+func lookupMediaType(content map[string]*oas.MediaType, mediaType string) *oas.MediaType {
+
+	if documented, ok := content[mediaType]; ok {
+		return documented
+	}
+
+	if i := strings.Index(mediaType, "/"); i >= 0 {
+		if documented, ok := content[mediaType[:i]+"/*"]; ok {
+			return documented
+		}
+	}
+
+	return content["*/*"]
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+/**
+ * Returns the response documented for status: by exact code, by range
+ * (2XX) or the default one.
+ */
+func statusResponse(responses map[string]*oas.Response, status int) *oas.Response {
+
+	code := strconv.Itoa(status)
+
+	for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		if documented, ok := responses[key]; ok {
+			return documented
+		}
+	}
+
+	return nil
+}
+
+/**
+ * Returns the body of req without consuming it.
+ */
+func readBody(req *http.Request) ([]byte, error) {
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
+
+	data, err := ioutil.ReadAll(req.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
@@ -0,0 +1,72 @@
+package gorequest
+
+/**
+ * Contract checks of requests and responses against an OpenAPI 3 document.
+ * The Hooks of a Contract validate every outgoing request and every response
+ * received: the operation must be documented, its parameters and bodies
+ * must match their schemas and the status must be one of its responses.
+ *
+ *   spec, err := openapi.Load("testdata/api.yaml")
+ *   ...
+ *   contract := &openapi.Contract{Spec: spec, Mode: openapi.ModeEnforce}
+ *   client := gorequest.NewClientBuilder().WithHooks(contract.Hooks()).Build()
+ *
+ * Spec.Validate and Spec.RequestHooks check outgoing requests only.
+ *
+ * JSON bodies are validated against a subset of JSON Schema: type, enum,
+ * nullable, allOf/anyOf/oneOf, required, properties, additionalProperties,
+ * items, the length, item count and numeric bounds, pattern and the
+ * date, date-time and uuid formats. Other media types are only checked for
+ * being documented.
+ */
+
+import (
+	oas "github.com/demianlessa/gorequest/internal/oas"
+	"io/ioutil"
+	"net/url"
+	"strings"
+)
+
+/**
+ * A parsed OpenAPI 3 document. Safe for concurrent use.
+ */
+type Spec struct {
+	basePath string
+	document *oas.Document
+}
+
+/**
+ * Reads a document, in YAML or JSON, from the file at path.
+ */
+func Load(path string) (*Spec, error) {
+
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data)
+}
+
+/**
+ * Parses a document in YAML or JSON.
+ */
+func Parse(data []byte) (*Spec, error) {
+
+	document, err := oas.Parse(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{document: document}
+
+	if len(spec.document.Servers) > 0 {
+		if server, err := url.Parse(spec.document.Servers[0].URL); err == nil {
+			spec.basePath = strings.TrimSuffix(server.Path, "/")
+		}
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,220 @@
+package gorequest
+
+import (
+	"fmt"
+	oas "github.com/demianlessa/gorequest/internal/oas"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/****************************************************
+ * JSON Schema subset
+ ****************************************************/
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+/**
+ * Validates a decoded JSON value, returning one problem per mismatch, each
+ * prefixed with the location of the value (path).
+ */
+func (s *Spec) validate(value interface{}, sc *oas.Schema, path string) []string {
+
+	sc = s.document.Schema(sc)
+
+	if sc == nil {
+		return nil
+	}
+
+	if value == nil && sc.Nullable {
+		return nil
+	}
+
+	var problems []string
+
+	for _, sub := range sc.AllOf {
+		problems = append(problems, s.validate(value, sub, path)...)
+	}
+
+	if len(sc.AnyOf) > 0 && s.matching(value, sc.AnyOf, path) == 0 {
+		problems = append(problems, fmt.Sprintf("%s: matches none of anyOf", path))
+	}
+
+	if len(sc.OneOf) > 0 {
+		if matching := s.matching(value, sc.OneOf, path); matching != 1 {
+			problems = append(problems, fmt.Sprintf("%s: matches %d of oneOf instead of 1", path, matching))
+		}
+	}
+
+	if len(sc.Enum) > 0 && !inEnum(value, sc.Enum) {
+		problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", path, value, sc.Enum))
+	}
+
+	if sc.Type != "" && !hasType(value, sc.Type) {
+		return append(problems, fmt.Sprintf("%s: expected %s, got %s", path, sc.Type, typeOf(value)))
+	}
+
+	switch value := value.(type) {
+	case string:
+		problems = append(problems, validateString(value, sc, path)...)
+	case float64:
+		problems = append(problems, validateNumber(value, sc, path)...)
+	case []interface{}:
+		if sc.MinItems != nil && len(value) < *sc.MinItems {
+			problems = append(problems, fmt.Sprintf("%s: expected at least %d items, got %d", path, *sc.MinItems, len(value)))
+		}
+		if sc.MaxItems != nil && len(value) > *sc.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s: expected at most %d items, got %d", path, *sc.MaxItems, len(value)))
+		}
+		for i, item := range value {
+			problems = append(problems, s.validate(item, sc.Items, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case map[string]interface{}:
+		problems = append(problems, s.validateObject(value, sc, path)...)
+	}
+
+	return problems
+}
+
+func (s *Spec) validateObject(value map[string]interface{}, sc *oas.Schema, path string) []string {
+
+	var problems []string
+
+	for _, name := range sc.Required {
+		if _, ok := value[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s: missing required property %q", path, name))
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := sc.Properties[name]; ok {
+			problems = append(problems, s.validate(value[name], property, path+"."+name)...)
+			continue
+		}
+		if sc.AdditionalProperties == nil {
+			continue
+		}
+		if sc.AdditionalProperties.Forbidden {
+			problems = append(problems, fmt.Sprintf("%s: unexpected property %q", path, name))
+			continue
+		}
+		problems = append(problems, s.validate(value[name], sc.AdditionalProperties.Schema, path+"."+name)...)
+	}
+
+	return problems
+}
+
+func (s *Spec) matching(value interface{}, schemas []*oas.Schema, path string) int {
+	matching := 0
+	for _, sub := range schemas {
+		if len(s.validate(value, sub, path)) == 0 {
+			matching++
+		}
+	}
+	return matching
+}
+
+func validateString(value string, sc *oas.Schema, path string) []string {
+
+	var problems []string
+
+	length := utf8.RuneCountInString(value)
+
+	if sc.MinLength != nil && length < *sc.MinLength {
+		problems = append(problems, fmt.Sprintf("%s: expected at least %d characters, got %d", path, *sc.MinLength, length))
+	}
+
+	if sc.MaxLength != nil && length > *sc.MaxLength {
+		problems = append(problems, fmt.Sprintf("%s: expected at most %d characters, got %d", path, *sc.MaxLength, length))
+	}
+
+	if sc.Pattern != "" {
+		if pattern, err := regexp.Compile(sc.Pattern); err == nil && !pattern.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%s: %q does not match %s", path, value, sc.Pattern))
+		}
+	}
+
+	if !hasFormat(value, sc.Format) {
+		problems = append(problems, fmt.Sprintf("%s: %q is not a valid %s", path, value, sc.Format))
+	}
+
+	return problems
+}
+
+func validateNumber(value float64, sc *oas.Schema, path string) []string {
+
+	var problems []string
+
+	if sc.Minimum != nil && (value < *sc.Minimum || sc.ExclusiveMinimum && value == *sc.Minimum) {
+		problems = append(problems, fmt.Sprintf("%s: %v is below the minimum of %v", path, value, *sc.Minimum))
+	}
+
+	if sc.Maximum != nil && (value > *sc.Maximum || sc.ExclusiveMaximum && value == *sc.Maximum) {
+		problems = append(problems, fmt.Sprintf("%s: %v is above the maximum of %v", path, value, *sc.Maximum))
+	}
+
+	return problems
+}
+
+func hasType(value interface{}, expected string) bool {
+	switch expected {
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	}
+	return typeOf(value) == expected
+}
+
+func hasFormat(value string, format string) bool {
+	switch format {
+	case "date":
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	}
+	return true
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(value, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", value), "*")
+}
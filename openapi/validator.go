@@ -0,0 +1,57 @@
+package gorequest
+
+import (
+	"context"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+)
+
+/**
+ * Checks the request builder would send against its operation, without
+ * sending it. The problems found are returned as a *Violation, e.g.
+ * `missing required query parameter "since"`, rather than left to the
+ * server to answer with a 400.
+ */
+func (s *Spec) Validate(builder model.RequestBuilder) error {
+
+	req, err := builder.BuildHttpRequest(context.Background())
+
+	if err != nil {
+		return err
+	}
+
+	return s.validateRequest(req)
+}
+
+/**
+ * Returns hooks failing the requests that do not match the document with a
+ * *Violation, before they are sent. Unlike those of a Contract, they leave
+ * responses alone, so they fit clients of services whose responses drift
+ * from their documentation.
+ */
+func (s *Spec) RequestHooks() model.Hooks {
+	return model.Hooks{
+		OnBeforeRequest: s.validateRequest,
+	}
+}
+
+func (s *Spec) validateRequest(req *http.Request) error {
+
+	body, err := readBody(req)
+
+	if err != nil {
+		return err
+	}
+
+	problems := s.ValidateRequest(req, body)
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return &Violation{
+		Method:   req.Method,
+		Problems: problems,
+		URL:      req.URL.String(),
+	}
+}
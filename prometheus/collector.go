@@ -0,0 +1,140 @@
+package gorequest
+
+/**
+ * Prometheus metrics for gorequest clients. Kept in its own module so the
+ * core does not depend on the Prometheus client library.
+ *
+ *   collector := prometheus.NewCollector("myapp")
+ *   client := gorequest.NewClientBuilder().WithObserver(collector).Build()
+ *   collector.Watch("billing", client)
+ *   registry.MustRegister(collector)
+ */
+
+import (
+	model "github.com/demianlessa/gorequest/model"
+	prometheus "github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"sync"
+)
+
+/**
+ * Exchange metrics observed through model.Observer, plus the retry and
+ * circuit breaker state of the watched clients, read at scrape time.
+ */
+type Collector struct {
+	breakerState *prometheus.Desc
+	clients      map[string]model.Client
+	duration     *prometheus.HistogramVec
+	errors       *prometheus.CounterVec
+	mutex        sync.Mutex
+	requests     *prometheus.CounterVec
+	retries      *prometheus.Desc
+	skipped      *prometheus.Desc
+}
+
+/**
+ * Returns a Collector whose metric names start with namespace, if not empty.
+ */
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		breakerState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gorequest", "circuit_state"),
+			"Circuit breaker state per host: 0 closed, 1 open, 2 half-open.",
+			[]string{"client", "host"}, nil,
+		),
+		clients: make(map[string]model.Client),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "gorequest",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of exchanges, including reading the response body.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "host", "status"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "gorequest",
+			Name:      "errors_total",
+			Help:      "Exchanges that failed without a response.",
+		}, []string{"method", "host"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "gorequest",
+			Name:      "requests_total",
+			Help:      "Exchanges sent, including retries.",
+		}, []string{"method", "host", "status"}),
+		retries: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gorequest", "retries_total"),
+			"Retries performed.",
+			[]string{"client"}, nil,
+		),
+		skipped: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gorequest", "retries_skipped_total"),
+			"Retries skipped, by reason.",
+			[]string{"client", "reason"}, nil,
+		),
+	}
+}
+
+/**
+ * Adds the retry and circuit breaker state of client to the metrics,
+ * labelled with name.
+ */
+func (c *Collector) Watch(name string, client model.Client) *Collector {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.clients[name] = client
+	return c
+}
+
+/**
+ * model.Observer implementation.
+ */
+func (c *Collector) ObserveExchange(exchange model.Exchange) {
+
+	status := "error"
+
+	if exchange.Err != nil {
+		c.errors.WithLabelValues(exchange.Method, exchange.Host).Inc()
+	} else {
+		status = strconv.Itoa(exchange.StatusCode)
+	}
+
+	c.requests.WithLabelValues(exchange.Method, exchange.Host, status).Inc()
+	c.duration.WithLabelValues(exchange.Method, exchange.Host, status).Observe(exchange.Duration.Seconds())
+}
+
+/**
+ * prometheus.Collector implementation.
+ */
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+	c.duration.Describe(ch)
+	ch <- c.retries
+	ch <- c.skipped
+	ch <- c.breakerState
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+	c.duration.Collect(ch)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for name, client := range c.clients {
+
+		stats := client.RetryStats()
+
+		ch <- prometheus.MustNewConstMetric(c.retries, prometheus.CounterValue, float64(stats.Retries), name)
+		ch <- prometheus.MustNewConstMetric(c.skipped, prometheus.CounterValue, float64(stats.BudgetExhausted), name, "budget")
+		ch <- prometheus.MustNewConstMetric(c.skipped, prometheus.CounterValue, float64(stats.MaxElapsedExceeded), name, "max_elapsed")
+		ch <- prometheus.MustNewConstMetric(c.skipped, prometheus.CounterValue, float64(stats.DeadlineExceeded), name, "deadline")
+
+		for host, state := range client.CircuitStates() {
+			ch <- prometheus.MustNewConstMetric(c.breakerState, prometheus.GaugeValue, float64(state), name, host)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package gorequest
+
+import (
+	prometheus "github.com/prometheus/client_golang/prometheus"
+	"sort"
+	"sync"
+	"time"
+)
+
+/**
+ * Prometheus adapter of model.MetricsCollector, for ClientBuilder.WithMetrics.
+ * Metrics are created on first use, with the tags of that first call as
+ * labels: counters as <name>_total, timers as <name>_seconds histograms.
+ * Unlike Collector it needs no knowledge of the client internals, at the
+ * price of generic help texts.
+ */
+type MetricsCollector struct {
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+	labels     map[string][]string
+	mutex      sync.Mutex
+	namespace  string
+}
+
+/**
+ * Returns a MetricsCollector whose metric names start with namespace, if
+ * not empty.
+ */
+func NewMetricsCollector(namespace string) *MetricsCollector {
+	return &MetricsCollector{
+		counters:   make(map[string]*prometheus.CounterVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+		labels:     make(map[string][]string),
+		namespace:  namespace,
+	}
+}
+
+/**
+ * model.MetricsCollector implementation.
+ */
+func (c *MetricsCollector) AddCounter(name string, delta float64, tags map[string]string) {
+
+	c.mutex.Lock()
+	counter, ok := c.counters[name]
+	if !ok {
+		counter = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.namespace,
+			Subsystem: "gorequest",
+			Name:      name + "_total",
+			Help:      "Client counter " + name + ".",
+		}, c.labelNames(name, tags))
+		c.counters[name] = counter
+	}
+	values := c.labelValues(name, tags)
+	c.mutex.Unlock()
+
+	counter.WithLabelValues(values...).Add(delta)
+}
+
+func (c *MetricsCollector) ObserveTimer(name string, duration time.Duration, tags map[string]string) {
+
+	c.mutex.Lock()
+	histogram, ok := c.histograms[name]
+	if !ok {
+		histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.namespace,
+			Subsystem: "gorequest",
+			Name:      name + "_seconds",
+			Help:      "Client timer " + name + ".",
+			Buckets:   prometheus.DefBuckets,
+		}, c.labelNames(name, tags))
+		c.histograms[name] = histogram
+	}
+	values := c.labelValues(name, tags)
+	c.mutex.Unlock()
+
+	histogram.WithLabelValues(values...).Observe(duration.Seconds())
+}
+
+func (c *MetricsCollector) SetGauge(name string, value float64, tags map[string]string) {
+
+	c.mutex.Lock()
+	gauge, ok := c.gauges[name]
+	if !ok {
+		gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: c.namespace,
+			Subsystem: "gorequest",
+			Name:      name,
+			Help:      "Client gauge " + name + ".",
+		}, c.labelNames(name, tags))
+		c.gauges[name] = gauge
+	}
+	values := c.labelValues(name, tags)
+	c.mutex.Unlock()
+
+	gauge.WithLabelValues(values...).Set(value)
+}
+
+/**
+ * prometheus.Collector implementation. Metrics are only known once used, so
+ * none are described: the collector is registered unchecked.
+ */
+func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, counter := range c.counters {
+		counter.Collect(ch)
+	}
+	for _, gauge := range c.gauges {
+		gauge.Collect(ch)
+	}
+	for _, histogram := range c.histograms {
+		histogram.Collect(ch)
+	}
+}
+
+/**
+ * Returns the label names of metric name, fixing them on first use.
+ */
+func (c *MetricsCollector) labelNames(name string, tags map[string]string) []string {
+	if names, ok := c.labels[name]; ok {
+		return names
+	}
+	names := make([]string, 0, len(tags))
+	for key := range tags {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	c.labels[name] = names
+	return names
+}
+
+/**
+ * Returns the values of the labels of metric name; tags missing from the
+ * first call are dropped, labels missing from tags are empty.
+ */
+func (c *MetricsCollector) labelValues(name string, tags map[string]string) []string {
+	names := c.labelNames(name, tags)
+	values := make([]string, len(names))
+	for i, label := range names {
+		values[i] = tags[label]
+	}
+	return values
+}
@@ -0,0 +1,113 @@
+package gorequest
+
+import (
+	"net/http"
+	"testing"
+
+	gorequest "github.com/demianlessa/gorequest"
+	model "github.com/demianlessa/gorequest/model"
+	requestmock "github.com/demianlessa/gorequest/requestmock"
+	prometheus "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+/**
+ * Returns the gathered metrics of family name, by their labels.
+ */
+func gatherFamily(t *testing.T, registry *prometheus.Registry, name string) map[string]*dto.Metric {
+	families, err := registry.Gather()
+	assert.Nil(t, err, "Should gather")
+
+	metrics := map[string]*dto.Metric{}
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.Metric {
+			labels := ""
+			for _, label := range metric.Label {
+				labels += label.GetName() + "=" + label.GetValue() + ","
+			}
+			metrics[labels] = metric
+		}
+	}
+
+	return metrics
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("GET", "/users").Reply(http.StatusOK, "[]")
+	mock.On("GET", "/broken").Reply(http.StatusInternalServerError, "")
+
+	collector := NewCollector("myapp")
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	client := gorequest.NewClientBuilder().
+		WithTransport(mock).
+		WithObserver(collector).
+		WithCircuitBreaker(model.CircuitBreaker{FailureRate: 0.5}).
+		Build()
+	collector.Watch("billing", client)
+
+	gorequest.NewRequest(gorequest.WithClient(client), gorequest.WithUrl("https://api.example.com/users")).Send()
+	gorequest.NewRequest(gorequest.WithClient(client), gorequest.WithUrl("https://api.example.com/users")).Send()
+	gorequest.NewRequest(gorequest.WithClient(client), gorequest.WithUrl("https://api.example.com/broken")).Send()
+
+	requests := gatherFamily(t, registry, "myapp_gorequest_requests_total")
+
+	assert.Len(t, requests, 2, "Should count exchanges by method, host and status")
+	assert.Equal(t, 2.0, requests["host=api.example.com,method=GET,status=200,"].GetCounter().GetValue(), "Should count successful exchanges")
+	assert.Equal(t, 1.0, requests["host=api.example.com,method=GET,status=500,"].GetCounter().GetValue(), "Should count failed exchanges")
+
+	durations := gatherFamily(t, registry, "myapp_gorequest_request_duration_seconds")
+
+	assert.Equal(t, uint64(2), durations["host=api.example.com,method=GET,status=200,"].GetHistogram().GetSampleCount(), "Should time exchanges")
+
+	retries := gatherFamily(t, registry, "myapp_gorequest_retries_total")
+
+	assert.Contains(t, retries, "client=billing,", "Should report the retries of watched clients")
+
+	skipped := gatherFamily(t, registry, "myapp_gorequest_retries_skipped_total")
+
+	assert.Contains(t, skipped, "client=billing,reason=budget,", "Should report skipped retries by reason")
+
+	states := gatherFamily(t, registry, "myapp_gorequest_circuit_state")
+
+	assert.Contains(t, states, "client=billing,host=api.example.com,", "Should report the circuit of every host")
+}
+
+func TestPrometheusMetricsCollector(t *testing.T) {
+	mock := requestmock.New()
+	mock.On("GET", "/users").Reply(http.StatusOK, "[]")
+
+	collector := NewMetricsCollector("myapp")
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+
+	client := gorequest.NewClientBuilder().WithTransport(mock).WithMetrics(collector).Build()
+
+	gorequest.NewRequest(gorequest.WithClient(client), gorequest.WithUrl("https://api.example.com/users")).Send()
+	gorequest.NewRequest(gorequest.WithClient(client), gorequest.WithUrl("https://api.example.com/users")).Send()
+
+	requests := gatherFamily(t, registry, "myapp_gorequest_requests_total")
+
+	assert.Equal(t, 2.0, requests["host=api.example.com,method=GET,status=200,"].GetCounter().GetValue(), "Should label counters with the tags of the client")
+
+	durations := gatherFamily(t, registry, "myapp_gorequest_request_duration_seconds")
+
+	assert.Equal(t, uint64(2), durations["host=api.example.com,method=GET,status=200,"].GetHistogram().GetSampleCount(), "Should record timers as histograms")
+
+	inFlight := gatherFamily(t, registry, "myapp_gorequest_in_flight")
+
+	assert.Equal(t, 0.0, inFlight[""].GetGauge().GetValue(), "Should record gauges")
+
+	collector.AddCounter("requests", 1, map[string]string{"method": "POST", "host": "api.example.com", "status": "201", "extra": "dropped"})
+
+	requests = gatherFamily(t, registry, "myapp_gorequest_requests_total")
+
+	assert.Equal(t, 1.0, requests["host=api.example.com,method=POST,status=201,"].GetCounter().GetValue(), "Should drop tags missing from the first use")
+}
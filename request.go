@@ -5,8 +5,12 @@ package gorequest
  */
 
 import (
+  "context"
   impl "github.com/demianlessa/gorequest/impl"
   model "github.com/demianlessa/gorequest/model"
+  "io"
+  "net/http"
+  "time"
 )
 
 /**
@@ -15,3 +19,227 @@ import (
  * constructor.
  */
 var NewRequestBuilder model.RequestBuilderConstructor = impl.NewRequestBuilder;
+
+/**
+ * Creates a ClientBuilder. Clients carry the transport settings (TLS, timeouts)
+ * shared by the requests built against them; see RequestBuilder.WithClient.
+ */
+var NewClientBuilder model.ClientBuilderConstructor = impl.NewClientBuilder;
+
+/**
+ * Adopts an already configured *http.Client (proxy, instrumentation...) as a
+ * Client; see ClientBuilder.WithHttpClient to add further settings.
+ */
+var WrapHttpClient func(httpClient *http.Client) model.Client = impl.WrapHttpClient;
+
+/**
+ * Creates a QueueBuilder. Queues run requests asynchronously on a pool of
+ * workers and deliver their outcome to a handler or a channel.
+ */
+var NewQueueBuilder model.QueueBuilderConstructor = impl.NewQueueBuilder;
+
+/**
+ * Redirect policies for ClientBuilder.WithRedirectPolicy and
+ * RequestBuilder.WithRedirectPolicy. Unless configured otherwise, up to 10
+ * redirects are followed.
+ */
+var NewNoRedirectPolicy func() model.RedirectPolicy = impl.NewNoRedirectPolicy;
+var NewMaxRedirectPolicy func(hops int) model.RedirectPolicy = impl.NewMaxRedirectPolicy;
+var NewSameHostRedirectPolicy func(hops int) model.RedirectPolicy = impl.NewSameHostRedirectPolicy;
+
+/**
+ * Discovers endpoints from DNS SRV records; see ClientBuilder.WithDiscovery.
+ */
+var NewSRVDiscovery func(scheme, service, proto, name string) model.Discovery = impl.NewSRVDiscovery;
+
+/**
+ * The retry condition used when model.Retry.RetryIf is not set. Custom
+ * conditions can delegate to it.
+ */
+var DefaultRetryIf model.RetryCondition = impl.DefaultRetryIf;
+
+/**
+ * Builds a Response that was not received over the network, e.g. the default
+ * payload returned by a model.Fallback.
+ */
+var NewStaticResponse func(status int, body []byte) model.Response = impl.NewStaticResponse;
+
+/**
+ * Creates a recorder capturing exchanges into a HAR document; attach it with
+ * ClientBuilder.WithLogging.
+ */
+var NewHARRecorder func(maxEntries int) model.HARRecorder = impl.NewHARRecorder;
+
+/**
+ * W3C Trace Context propagation: a trace stored in the context of a request
+ * is sent in its traceparent and tracestate headers. ParseTraceparent reads
+ * the headers of an incoming request, to continue its trace.
+ */
+var ContextWithTrace func(ctx context.Context, trace model.TraceContext) context.Context = impl.ContextWithTrace;
+var TraceFromContext func(ctx context.Context) (model.TraceContext, bool) = impl.TraceFromContext;
+var ParseTraceparent func(traceparent string, tracestate string) (model.TraceContext, error) = impl.ParseTraceparent;
+
+/**
+ * Audit sinks for ClientBuilder.WithAudit, writing JSON lines to a file or
+ * to any writer (e.g. a *syslog.Writer). ContextWithPrincipal records on
+ * whose behalf a request is sent.
+ */
+var NewFileAuditSink func(path string) model.AuditSink = impl.NewFileAuditSink;
+var NewWriterAuditSink func(writer io.Writer) model.AuditSink = impl.NewWriterAuditSink;
+var ContextWithPrincipal func(ctx context.Context, principal string) context.Context = impl.ContextWithPrincipal;
+
+/**
+ * Starts a TestServer answering from a route table, for tests of code built
+ * on this package; see model.TestServer.
+ */
+var NewTestServer func(routes map[string]model.TestResponse) model.TestServer = impl.NewTestServer;
+
+/**
+ * Wraps a Client (nil for the default one) into a model.Doer, the interface
+ * application code can depend on and tests can replace.
+ */
+var NewDoer func(client model.Client) model.Doer = impl.NewDoer;
+
+/**
+ * Runs requests concurrently with bounded parallelism and returns their
+ * outcomes in order; see impl.All.
+ */
+var All func(ctx context.Context, requests []model.Request, concurrency int) []model.Result = impl.All;
+
+/**
+ * Futures: Async sends a request in the background, First and AllSettled
+ * combine the handles it returns.
+ */
+var Async func(req model.Request) model.Future = impl.Async;
+var First func(futures ...model.Future) model.Future = impl.First;
+var AllSettled func(futures ...model.Future) []model.Result = impl.AllSettled;
+
+/**
+ * Long-polls until ctx is done, delivering responses with data and failures
+ * on the returned channel; see model.LongPoll.
+ */
+var LongPoll func(ctx context.Context, poll model.LongPoll) <-chan model.Result = impl.LongPoll;
+
+/**
+ * Sends a request every interval until a condition on its response holds,
+ * e.g. an asynchronous job reporting "complete"; see impl.Poll.
+ */
+var Poll func(ctx context.Context, options []model.Option, interval time.Duration, until func(resp model.Response) (bool, error)) (model.Response, error) = impl.Poll;
+
+/**
+ * Read-modify-write of a resource with If-Match, merging again after
+ * conflicting writes; see model.Update.
+ */
+var Update func(ctx context.Context, update model.Update) (model.Response, error) = impl.Update;
+
+/**
+ * Fetches every page of a collection (Link header or cursor pagination) and
+ * collects their items; see model.Pagination.
+ */
+var FetchAll func(ctx context.Context, options []model.Option, pagination model.Pagination) error = impl.FetchAll;
+
+/**
+ * Pagination.Next for collections paged by a cursor in their bodies; see
+ * impl.NextCursor.
+ */
+var NextCursor func(param string, field string) func(page model.Response) (string, error) = impl.NextCursor;
+
+/**
+ * Parses a curl command line (e.g. copied from browser developer tools) into
+ * a RequestBuilder; see impl.FromCurl for the options understood.
+ */
+var FromCurl func(cmd string) (model.RequestBuilder, error) = impl.FromCurl;
+
+/**
+ * Converts internationalized hostnames to punycode, as requests send them,
+ * and back to Unicode for display.
+ */
+var HostToASCII func(host string) (string, error) = impl.HostToASCII;
+var HostToUnicode func(host string) string = impl.HostToUnicode;
+
+/**
+ * Normalizes a URL the way memoization and deduplication keys are: lowercase
+ * scheme and host, no default port, resolved dot segments, canonical
+ * percent-encodings and, if sortQuery is set, sorted query parameters.
+ */
+var NormalizeURL func(rawURL string, sortQuery bool) (string, error) = impl.NormalizeURL;
+
+/**
+ * Creates a URLSigner generating and verifying time-limited presigned URLs.
+ */
+var NewURLSigner func(signing model.URLSigning) model.URLSigner = impl.NewURLSigner;
+
+/**
+ * Hooks signing requests with AWS Signature Version 4, e.g. for S3 and
+ * S3-compatible object stores; see impl.SigV4Hooks.
+ */
+var SigV4Hooks func(credentials model.SigV4) model.Hooks = impl.SigV4Hooks;
+
+/**
+ * Loads a Postman collection (v2.1) and, optionally, an environment, whose
+ * requests can then be sent as Options; see impl.LoadPostmanCollection for
+ * what is supported.
+ */
+var LoadPostmanCollection func(collection []byte, environment []byte) (model.PostmanCollection, error) = impl.LoadPostmanCollection;
+
+/**
+ * Sends a *http.Request built elsewhere through the pipeline of a Client (nil
+ * for the default one); see also RequestBuilder.BuildHttpRequest.
+ */
+var NewRequestFromHttp func(client model.Client, req *http.Request) model.Request = impl.NewRequestFromHttp;
+
+/**
+ * Functional constructors, an alternative to the builders:
+ *
+ *   client := NewClient(WithTimeout(10*time.Second), WithBaseURL(u), WithRetry(r))
+ *   resp := NewRequest(WithClient(client), WithUrl("/users")).Do()
+ *
+ * Options that apply to both (WithHedging, WithRedirectPolicy, WithRetry)
+ * can be passed to either; the others panic when passed to the wrong one.
+ */
+var NewClient func(options ...model.Option) model.Client = impl.NewClient;
+var NewRequest func(options ...model.Option) model.Request = impl.NewRequest;
+
+var WithHedging func(hedge model.Hedge) model.Option = impl.WithHedging;
+var WithRedirectPolicy func(policy model.RedirectPolicy) model.Option = impl.WithRedirectPolicy;
+var WithRetry func(retry model.Retry) model.Option = impl.WithRetry;
+
+var WithBaseURL func(baseURLs ...string) model.Option = impl.WithBaseURL;
+var WithHooks func(hooks model.Hooks) model.Option = impl.WithHooks;
+var WithTimeout func(timeout time.Duration) model.Option = impl.WithTimeout;
+var WithTransport func(transport http.RoundTripper) model.Option = impl.WithTransport;
+
+var WithAcceptEncoding func(encodings ...string) model.Option = impl.WithAcceptEncoding;
+var WithBasicAuth func(user string, password string) model.Option = impl.WithBasicAuth;
+var WithBearerAuth func(token string) model.Option = impl.WithBearerAuth;
+var WithBody func(body model.RequestBody) model.Option = impl.WithBody;
+var WithChecksum func(checksum model.Checksum) model.Option = impl.WithChecksum;
+var WithChunking func(chunking model.Chunking) model.Option = impl.WithChunking;
+var WithClient func(client model.Client) model.Option = impl.WithClient;
+var WithContentDigest func(algorithm string) model.Option = impl.WithContentDigest;
+var WithContext func(ctx context.Context) model.Option = impl.WithContext;
+var WithHeader func(name, value string) model.Option = impl.WithHeader;
+var WithHeaders func(headers model.Headers) model.Option = impl.WithHeaders;
+var WithMethod func(method string) model.Option = impl.WithMethod;
+var WithRange func(offset int64, length int64) model.Option = impl.WithRange;
+var WithRangeHeader func(value string) model.Option = impl.WithRangeHeader;
+var WithUrl func(url string) model.Option = impl.WithUrl;
+var WithoutDecompression func() model.Option = impl.WithoutDecompression;
+
+/**
+ * Checks that a response to a WithRange request is the part asked for:
+ * status 206 and a Content-Range matching the body.
+ */
+var ContentRange func(resp model.Response) (model.ContentRange, error) = impl.ContentRange;
+
+/**
+ * Reports whether the resource of options exists, probing it with HEAD (or
+ * GET where HEAD is not supported); see impl.Exists.
+ */
+var Exists func(options ...model.Option) (bool, *http.Response, error) = impl.Exists;
+
+/**
+ * Request body of JSON metadata followed by media, for multipart/related
+ * uploads; see impl.NewMultipartRelated.
+ */
+var NewMultipartRelated func(metadata interface{}, mediaType string, media io.Reader) model.RequestBody = impl.NewMultipartRelated;
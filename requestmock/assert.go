@@ -0,0 +1,208 @@
+package gorequest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/**
+ * The subset of *testing.T used by the assertions.
+ */
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+	Helper()
+}
+
+/**
+ * A request received by a Mock.
+ */
+type Call struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+}
+
+/**
+ * Narrows the calls counted by AssertCalled, or sets their expected number.
+ */
+type Expectation func(e *expectation)
+
+type expectation struct {
+	conditions []string
+	matchers   []func(call Call) bool
+	times      int
+}
+
+func (e *expectation) add(condition string, matcher func(call Call) bool) {
+	e.conditions = append(e.conditions, condition)
+	e.matchers = append(e.matchers, matcher)
+}
+
+/**
+ * Expects exactly n matching calls instead of at least one.
+ */
+func Times(n int) Expectation {
+	return func(e *expectation) {
+		e.times = n
+	}
+}
+
+/**
+ * Only counts calls with the given header value.
+ */
+func WithHeader(name string, value string) Expectation {
+	return func(e *expectation) {
+		e.add(fmt.Sprintf("header %s: %s", name, value), func(call Call) bool {
+			return call.Header.Get(name) == value
+		})
+	}
+}
+
+/**
+ * Only counts calls with the given query parameter value.
+ */
+func WithQuery(name string, value string) Expectation {
+	return func(e *expectation) {
+		e.add(fmt.Sprintf("query %s=%s", name, value), func(call Call) bool {
+			return call.URL.Query().Get(name) == value
+		})
+	}
+}
+
+/**
+ * Only counts calls whose body equals body.
+ */
+func WithBody(body string) Expectation {
+	return func(e *expectation) {
+		e.add("body "+body, func(call Call) bool {
+			return string(call.Body) == body
+		})
+	}
+}
+
+/**
+ * Only counts calls whose body is JSON equivalent to body.
+ */
+func WithJSON(body string) Expectation {
+
+	var expected interface{}
+
+	if err := json.Unmarshal([]byte(body), &expected); err != nil {
+		panic(err)
+	}
+
+	return func(e *expectation) {
+		e.add("JSON body "+body, func(call Call) bool {
+			return jsonEqual(expected, call.Body)
+		})
+	}
+}
+
+/**
+ * Only counts calls satisfying a custom condition.
+ */
+func Where(description string, matcher func(call Call) bool) Expectation {
+	return func(e *expectation) {
+		e.add(description, matcher)
+	}
+}
+
+/**
+ * Asserts that requests with method ("" or "*" for any) and URL pattern (as
+ * in Mock.On) were sent: at least once, or exactly as often as set with
+ * Times. Reports the calls received on failure.
+ */
+func (m *Mock) AssertCalled(t TestingT, method string, pattern string, expectations ...Expectation) bool {
+
+	t.Helper()
+
+	e := &expectation{times: -1}
+
+	for _, expectation := range expectations {
+		expectation(e)
+	}
+
+	count := m.count(method, pattern, e)
+
+	if e.times < 0 && count > 0 || count == e.times {
+		return true
+	}
+
+	expected := "at least once"
+
+	if e.times >= 0 {
+		expected = fmt.Sprintf("%d times", e.times)
+	}
+
+	description := strings.TrimSpace(strings.ToUpper(method) + " " + pattern)
+
+	if len(e.conditions) > 0 {
+		description += " with " + strings.Join(e.conditions, ", ")
+	}
+
+	t.Errorf("Expected %s to be called %s, got %d calls\nReceived:\n%s", description, expected, count, m.describeCalls())
+
+	return false
+}
+
+/**
+ * Asserts that no request with method and URL pattern was sent.
+ */
+func (m *Mock) AssertNotCalled(t TestingT, method string, pattern string, expectations ...Expectation) bool {
+	t.Helper()
+	return m.AssertCalled(t, method, pattern, append(expectations, Times(0))...)
+}
+
+func (m *Mock) count(method string, pattern string, e *expectation) int {
+
+	compiled := compilePattern(pattern)
+	method = strings.ToUpper(method)
+	count := 0
+
+	for _, call := range m.Calls() {
+
+		if method != "" && method != "*" && method != call.Method {
+			continue
+		}
+
+		if !matchURL(compiled, pattern, call.URL) {
+			continue
+		}
+
+		matched := true
+
+		for _, matcher := range e.matchers {
+			if !matcher(call) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (m *Mock) describeCalls() string {
+
+	calls := m.Calls()
+
+	if len(calls) == 0 {
+		return "  (none)"
+	}
+
+	lines := make([]string, 0, len(calls))
+
+	for _, call := range calls {
+		lines = append(lines, "  "+call.Method+" "+call.URL.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,376 @@
+package gorequest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+	"unicode/utf8"
+)
+
+/**
+ * How a Recorder treats the network.
+ */
+type Mode int
+
+const (
+	// Replay the cassette if its file exists, record it otherwise.
+	ModeAuto Mode = iota
+	// Always send requests and overwrite the cassette.
+	ModeRecord
+	// Never send requests; requests missing from the cassette fail.
+	ModeReplay
+)
+
+/**
+ * Environment variable forcing every Recorder into ModeReplay when set, so
+ * that CI and air-gapped runs never reach the network, and missing
+ * cassettes fail instead of being recorded.
+ */
+const OfflineEnv = "REQUESTMOCK_OFFLINE"
+
+/**
+ * Value replacing redacted headers and query parameters.
+ */
+const Redacted = "[REDACTED]"
+
+/**
+ * Record-and-replay settings. Recorded exchanges are scrubbed before they
+ * are stored: Authorization, Proxy-Authorization, Cookie and Set-Cookie
+ * headers are always redacted.
+ */
+type Cassette struct {
+	// JSON file holding the recorded interactions.
+	Path string
+	Mode Mode
+	// Transport used while recording; nil means http.DefaultTransport.
+	Transport http.RoundTripper
+	// Additional headers redacted in requests and responses.
+	RedactHeaders []string
+	// Query parameters redacted in request URLs, e.g. "api_key".
+	RedactQuery []string
+	// Rewrites an interaction before it is stored, e.g. to mask secrets in
+	// bodies. Replayed requests are matched against the rewritten request.
+	Scrub func(interaction *Interaction)
+	// By default requests are matched on method and URL (after redaction).
+	// MatchBody also compares bodies, MatchHeaders the given headers.
+	MatchBody    bool
+	MatchHeaders []string
+}
+
+/**
+ * One recorded exchange.
+ */
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string       `json:"method"`
+	URL    string       `json:"url"`
+	Header http.Header  `json:"header,omitempty"`
+	Body   RecordedBody `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int          `json:"status"`
+	Header     http.Header  `json:"header,omitempty"`
+	Body       RecordedBody `json:"body,omitempty"`
+}
+
+/**
+ * A body stored as text when it is valid UTF-8, as base64 otherwise.
+ */
+type RecordedBody []byte
+
+func (b RecordedBody) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *RecordedBody) UnmarshalJSON(data []byte) error {
+
+	var text string
+
+	if json.Unmarshal(data, &text) == nil {
+		*b = RecordedBody(text)
+		return nil
+	}
+
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	*b = decoded
+
+	return err
+}
+
+/**
+ * An http.RoundTripper recording exchanges into a cassette or replaying
+ * them from it, so integration tests run against real responses without
+ * the network. Call Stop once done to save a recording.
+ */
+type Recorder struct {
+	interactions []*Interaction
+	mutex        sync.Mutex
+	recording    bool
+	redact       map[string]bool
+	settings     Cassette
+	used         []bool
+}
+
+/**
+ * Returns a Recorder for cassette; in replay mode the cassette is loaded
+ * right away.
+ */
+func NewRecorder(cassette Cassette) *Recorder {
+
+	if cassette.Path == "" {
+		panic(errors.New("Cassette path is required"))
+	}
+
+	if cassette.Transport == nil {
+		cassette.Transport = http.DefaultTransport
+	}
+
+	if os.Getenv(OfflineEnv) != "" {
+		cassette.Mode = ModeReplay
+	}
+
+	r := &Recorder{
+		redact:   make(map[string]bool),
+		settings: cassette,
+	}
+
+	for _, name := range append([]string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}, cassette.RedactHeaders...) {
+		r.redact[http.CanonicalHeaderKey(name)] = true
+	}
+
+	switch cassette.Mode {
+	case ModeRecord:
+		r.recording = true
+	case ModeAuto:
+		_, err := os.Stat(cassette.Path)
+		r.recording = os.IsNotExist(err)
+	}
+
+	if !r.recording {
+		r.load()
+	}
+
+	return r
+}
+
+/**
+ * Reports whether the recorder sends requests and records them.
+ */
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+/**
+ * http.RoundTripper implementation.
+ */
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	var body []byte
+
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	recorded := r.recordRequest(req, body)
+
+	if r.recording {
+		return r.record(req, recorded)
+	}
+
+	return r.replay(req, recorded)
+}
+
+/**
+ * Saves the cassette when recording.
+ */
+func (r *Recorder) Stop() error {
+
+	if !r.recording {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.settings.Path, append(data, '\n'), 0644)
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+
+	resp, err := r.settings.Transport.RoundTrip(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	interaction := &Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Body:       body,
+			Header:     r.redactHeader(resp.Header),
+			StatusCode: resp.StatusCode,
+		},
+	}
+
+	if r.settings.Scrub != nil {
+		r.settings.Scrub(interaction)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.interactions = append(r.interactions, interaction)
+
+	return resp, nil
+}
+
+/**
+ * Answers with the first unused interaction matching the request, so
+ * repeated requests get the responses in the order they were recorded.
+ */
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, interaction := range r.interactions {
+
+		if r.used[i] || !r.matches(interaction.Request, recorded) {
+			continue
+		}
+
+		r.used[i] = true
+
+		response := interaction.Response
+
+		return &http.Response{
+			Body:          ioutil.NopCloser(bytes.NewReader(response.Body)),
+			ContentLength: int64(len(response.Body)),
+			Header:        response.Header.Clone(),
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Request:       req,
+			Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+			StatusCode:    response.StatusCode,
+		}, nil
+	}
+
+	return nil, &UnmatchedError{Method: recorded.Method, URL: recorded.URL}
+}
+
+func (r *Recorder) matches(recorded RecordedRequest, actual RecordedRequest) bool {
+
+	if recorded.Method != actual.Method || recorded.URL != actual.URL {
+		return false
+	}
+
+	if r.settings.MatchBody && !bytes.Equal(recorded.Body, actual.Body) {
+		return false
+	}
+
+	for _, name := range r.settings.MatchHeaders {
+		if !reflect.DeepEqual(recorded.Header.Values(name), actual.Header.Values(name)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/**
+ * Returns the request as it is stored: redacted, but not scrubbed.
+ */
+func (r *Recorder) recordRequest(req *http.Request, body []byte) RecordedRequest {
+
+	u := *req.URL
+
+	if len(r.settings.RedactQuery) > 0 {
+		query := u.Query()
+		for _, name := range r.settings.RedactQuery {
+			if _, ok := query[name]; ok {
+				query.Set(name, Redacted)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	return RecordedRequest{
+		Body:   body,
+		Header: r.redactHeader(req.Header),
+		Method: req.Method,
+		URL:    u.String(),
+	}
+}
+
+func (r *Recorder) redactHeader(header http.Header) http.Header {
+
+	clone := header.Clone()
+
+	for name := range clone {
+		if r.redact[name] {
+			clone[name] = []string{Redacted}
+		}
+	}
+
+	return clone
+}
+
+func (r *Recorder) load() {
+
+	data, err := ioutil.ReadFile(r.settings.Path)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := json.Unmarshal(data, &r.interactions); err != nil {
+		panic(fmt.Errorf("Invalid cassette %s: %v", r.settings.Path, err))
+	}
+
+	r.used = make([]bool, len(r.interactions))
+}
@@ -0,0 +1,104 @@
+package gorequest
+
+import (
+	"sync"
+	"time"
+)
+
+type clockWaiter struct {
+	at      time.Time
+	channel chan time.Time
+}
+
+/**
+ * A fake model.Clock for ClientBuilder.WithClock. Time only moves when
+ * Advance is called or, in auto-advance mode, whenever the code under test
+ * waits: retries then complete instantly while their delays still add up.
+ */
+type Clock struct {
+	auto    bool
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []clockWaiter
+}
+
+/**
+ * Returns a Clock set to start.
+ */
+func NewClock(start time.Time) *Clock {
+	return &Clock{
+		now: start,
+	}
+}
+
+/**
+ * Makes every wait advance the clock by its duration right away.
+ */
+func (c *Clock) AutoAdvance() *Clock {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.auto = true
+	return c
+}
+
+func (c *Clock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	waiter := clockWaiter{at: c.now.Add(d), channel: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, waiter)
+
+	if c.auto && d > 0 {
+		c.advance(d)
+	} else {
+		c.fire()
+	}
+
+	return waiter.channel
+}
+
+/**
+ * Moves the clock forward, releasing the waits that are due.
+ */
+func (c *Clock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.advance(d)
+}
+
+/**
+ * Returns the number of pending waits, e.g. to let the code under test
+ * reach its next wait before calling Advance.
+ */
+func (c *Clock) Waiters() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.waiters)
+}
+
+func (c *Clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+func (c *Clock) fire() {
+
+	pending := c.waiters[:0]
+
+	for _, waiter := range c.waiters {
+		if waiter.at.After(c.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+		waiter.channel <- c.now
+	}
+
+	c.waiters = pending
+}
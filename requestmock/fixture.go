@@ -0,0 +1,138 @@
+package gorequest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+/****************************************************
+ * Fixture files
+ ****************************************************/
+
+/**
+ * A response read from a fixture file: a status line and headers rendered
+ * as a template for each request, followed by the body kept verbatim.
+ */
+type fixture struct {
+	body []byte
+	data interface{}
+	head *template.Template
+}
+
+/**
+ * What the head of a fixture is rendered with.
+ */
+type FixtureData struct {
+	Data    interface{}
+	Request *http.Request
+}
+
+/**
+ * Sets the status of the response and streams its body from the file at
+ * path, read anew for each request. Unless a Content-Type header is set,
+ * it is derived from the file extension.
+ */
+func (s *Stub) ReplyFile(status int, path string) *Stub {
+
+	if _, err := os.Stat(path); err != nil {
+		panic(err)
+	}
+
+	s.status = status
+	s.file = path
+
+	if s.header.Get("Content-Type") == "" {
+		if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+			s.header.Set("Content-Type", contentType)
+		}
+	}
+
+	return s
+}
+
+/**
+ * Replies with the response stored in the fixture file at path, in the
+ * format of a raw HTTP response:
+ *
+ *   HTTP/1.1 {{or .Data.Status 200}} OK
+ *   Content-Type: application/json
+ *   X-Request-Id: {{.Request.Header.Get "X-Request-Id"}}
+ *
+ *   {"id":1}
+ *
+ * The status line and headers are a text/template rendered with a
+ * FixtureData holding data and the request; the body, which may be binary,
+ * is left untouched. Headers added with ReplyHeader are added to the ones of
+ * the fixture.
+ */
+func (s *Stub) ReplyFixture(path string, data interface{}) *Stub {
+
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		panic(err)
+	}
+
+	head, body := splitFixture(content)
+
+	tmpl, err := template.New(filepath.Base(path)).Parse(head)
+
+	if err != nil {
+		panic(err)
+	}
+
+	s.fixture = &fixture{
+		body: body,
+		data: data,
+		head: tmpl,
+	}
+
+	return s
+}
+
+func (f *fixture) response(req *http.Request) (*http.Response, error) {
+
+	var raw bytes.Buffer
+
+	if err := f.head.Execute(&raw, FixtureData{Data: f.data, Request: req}); err != nil {
+		return nil, err
+	}
+
+	raw.WriteString("\r\n\r\n")
+	raw.Write(f.body)
+
+	resp, err := http.ReadResponse(bufio.NewReader(&raw), req)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid fixture %s: %v", f.head.Name(), err)
+	}
+
+	if resp.ContentLength < 0 && len(resp.TransferEncoding) == 0 {
+		resp.ContentLength = int64(len(f.body))
+	}
+
+	return resp, nil
+}
+
+/**
+ * Splits a fixture at the first empty line, accepting both CRLF and LF line
+ * endings.
+ */
+func splitFixture(content []byte) (string, []byte) {
+
+	for _, separator := range []string{"\r\n\r\n", "\n\n"} {
+		if i := bytes.Index(content, []byte(separator)); i >= 0 {
+			return strings.TrimRight(string(content[:i]), "\r\n"), content[i+len(separator):]
+		}
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
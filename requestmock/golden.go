@@ -0,0 +1,228 @@
+package gorequest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/**
+ * Set by the -update-golden test flag or the UPDATE_GOLDEN environment
+ * variable: AssertGolden then rewrites golden files instead of comparing.
+ */
+var UpdateGolden = flag.Bool("update-golden", os.Getenv("UPDATE_GOLDEN") != "", "rewrite the golden files of requestmock.AssertGolden")
+
+/**
+ * Timestamps masked by default: RFC 3339 and HTTP dates.
+ */
+var timestampPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`),
+	regexp.MustCompile(`(Mon|Tue|Wed|Thu|Fri|Sat|Sun), \d{2} (Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) \d{4} \d{2}:\d{2}:\d{2} GMT`),
+}
+
+/**
+ * Adjusts how AssertGolden normalizes responses.
+ */
+type GoldenOption func(g *golden)
+
+type golden struct {
+	ignored  map[string]bool
+	masked   map[string]bool
+	patterns []*regexp.Regexp
+}
+
+/**
+ * Leaves the given headers out. The Date header is always left out.
+ */
+func IgnoreHeaders(names ...string) GoldenOption {
+	return func(g *golden) {
+		for _, name := range names {
+			g.ignored[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+/**
+ * Replaces the values of the given headers with "<masked>", e.g. for
+ * request IDs.
+ */
+func MaskHeaders(names ...string) GoldenOption {
+	return func(g *golden) {
+		for _, name := range names {
+			g.masked[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+/**
+ * Replaces the matches of pattern in headers and body with "<masked>", on
+ * top of timestamps.
+ */
+func MaskPattern(pattern string) GoldenOption {
+	return func(g *golden) {
+		g.patterns = append(g.patterns, regexp.MustCompile(pattern))
+	}
+}
+
+/**
+ * Compares resp, normalized, to the golden file at path. Normalization
+ * sorts headers, indents JSON bodies with sorted keys and masks timestamps,
+ * so that the file only changes with the API. With UpdateGolden set, the
+ * file is (re)written instead.
+ */
+func AssertGolden(t TestingT, path string, resp model.Response, options ...GoldenOption) bool {
+
+	t.Helper()
+
+	actual := NormalizeResponse(resp, options...)
+
+	if *UpdateGolden {
+		if err := writeGolden(path, actual); err != nil {
+			t.Errorf("Could not update golden file %s: %v", path, err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		t.Errorf("Golden file %s does not exist, run the test with -update-golden to create it", path)
+		return false
+	}
+
+	if err != nil {
+		t.Errorf("Could not read golden file %s: %v", path, err)
+		return false
+	}
+
+	if bytes.Equal(expected, actual) {
+		return true
+	}
+
+	t.Errorf("Response differs from golden file %s (run with -update-golden to accept it)\n%s", path, firstDifference(expected, actual))
+
+	return false
+}
+
+/**
+ * Returns the normalized form of resp compared by AssertGolden: the status
+ * line, the headers sorted by name, an empty line and the body.
+ */
+func NormalizeResponse(resp model.Response, options ...GoldenOption) []byte {
+
+	g := &golden{
+		ignored: map[string]bool{"Date": true},
+		masked:  make(map[string]bool),
+	}
+
+	for _, option := range options {
+		option(g)
+	}
+
+	httpResp := resp.Response()
+
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "%s %s\n", httpResp.Proto, httpResp.Status)
+
+	names := make([]string, 0, len(httpResp.Header))
+	for name := range httpResp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if g.ignored[name] {
+			continue
+		}
+		for _, value := range httpResp.Header[name] {
+			if g.masked[name] {
+				value = "<masked>"
+			}
+			fmt.Fprintf(&out, "%s: %s\n", name, g.mask(value))
+		}
+	}
+
+	out.WriteString("\n")
+	out.WriteString(g.mask(string(normalizeBody(httpResp.Header.Get("Content-Type"), resp.Body()))))
+
+	return out.Bytes()
+}
+
+func (g *golden) mask(value string) string {
+	for _, pattern := range timestampPatterns {
+		value = pattern.ReplaceAllString(value, "<timestamp>")
+	}
+	for _, pattern := range g.patterns {
+		value = pattern.ReplaceAllString(value, "<masked>")
+	}
+	return value
+}
+
+/**
+ * Indents JSON bodies with sorted keys; other bodies are kept as is.
+ */
+func normalizeBody(contentType string, body []byte) []byte {
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return body
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+
+	indented, err := json.MarshalIndent(value, "", "  ")
+
+	if err != nil {
+		return body
+	}
+
+	return append(indented, '\n')
+}
+
+func writeGolden(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+func firstDifference(expected []byte, actual []byte) string {
+
+	expectedLines := strings.Split(string(expected), "\n")
+	actualLines := strings.Split(string(actual), "\n")
+
+	for i := 0; i < len(expectedLines) || i < len(actualLines); i++ {
+		var want, got string
+		if i < len(expectedLines) {
+			want = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			got = actualLines[i]
+		}
+		if want != got || i >= len(expectedLines) || i >= len(actualLines) {
+			return fmt.Sprintf("line %d:\n  expected: %q\n  actual:   %q", i+1, want, got)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,467 @@
+package gorequest
+
+/**
+ * Network-free stubs for tests of code built on gorequest. A Mock is an
+ * http.RoundTripper answering requests from the stubs registered on it:
+ *
+ *   mock := requestmock.New()
+ *   mock.On("GET", "https://api.example.com/users/*").
+ *       MatchHeader("Accept", "application/json").
+ *       Reply(200, `{"id":1}`)
+ *   client := gorequest.NewClientBuilder().WithTransport(mock).Build()
+ *   ...
+ *   mock.AssertCalled(t, "GET", "https://api.example.com/users/*", requestmock.Times(1))
+ *   if err := mock.Verify(); err != nil {
+ *       t.Fatal(err)
+ *   }
+ */
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+/**
+ * Returned (wrapped in a *url.Error) for requests no stub matches.
+ */
+type UnmatchedError struct {
+	Method string
+	URL    string
+}
+
+func (e *UnmatchedError) Error() string {
+	return fmt.Sprintf("No stub matches %s %s", e.Method, e.URL)
+}
+
+/**
+ * Stubs tried in registration order; the first match answers. Safe for
+ * concurrent use once the stubs are registered.
+ */
+type Mock struct {
+	calls     []Call
+	clock     model.Clock
+	mutex     sync.Mutex
+	stubs     []*Stub
+	unmatched []string
+}
+
+func New() *Mock {
+	return &Mock{}
+}
+
+/**
+ * Times the delays of stubs with clock, e.g. the fake Clock also given to
+ * ClientBuilder.WithClock, instead of the system clock.
+ */
+func (m *Mock) WithClock(clock model.Clock) *Mock {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.clock = clock
+	return m
+}
+
+/**
+ * Registers a stub for requests with the given method ("" or "*" for any)
+ * and URL pattern. Patterns starting with "/" match the path only, others
+ * the scheme, host and path; "*" matches any sequence of characters. The
+ * query string is ignored, see Stub.MatchQuery. The stub replies 200 with
+ * an empty body until configured otherwise.
+ */
+func (m *Mock) On(method string, pattern string) *Stub {
+
+	stub := &Stub{
+		header:  make(http.Header),
+		method:  strings.ToUpper(method),
+		mock:    m,
+		pattern: compilePattern(pattern),
+		status:  http.StatusOK,
+		url:     pattern,
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.stubs = append(m.stubs, stub)
+
+	return stub
+}
+
+/**
+ * http.RoundTripper implementation.
+ */
+func (m *Mock) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	var body []byte
+
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	stub := m.match(req, body)
+
+	if stub == nil {
+		return nil, &UnmatchedError{Method: req.Method, URL: req.URL.String()}
+	}
+
+	return stub.respond(req)
+}
+
+/**
+ * Reports stubs that were never called or called fewer times than
+ * expected, and requests no stub matched.
+ */
+func (m *Mock) Verify() error {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var problems []string
+
+	for _, stub := range m.stubs {
+		if stub.calls == 0 || stub.calls < stub.times {
+			problems = append(problems, fmt.Sprintf("%s %s called %d times", stub.methodName(), stub.url, stub.calls))
+		}
+	}
+
+	for _, request := range m.unmatched {
+		problems = append(problems, "unmatched "+request)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("Mock expectations not met: %s", strings.Join(problems, "; "))
+}
+
+/**
+ * Removes all stubs and forgets the requests received.
+ */
+func (m *Mock) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.calls = nil
+	m.stubs = nil
+	m.unmatched = nil
+}
+
+/**
+ * Returns the requests received so far, matched or not, in order.
+ */
+func (m *Mock) Calls() []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+func (m *Mock) after(d time.Duration) <-chan time.Time {
+	m.mutex.Lock()
+	clock := m.clock
+	m.mutex.Unlock()
+	if clock == nil {
+		return time.After(d)
+	}
+	return clock.After(d)
+}
+
+func (m *Mock) match(req *http.Request, body []byte) *Stub {
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	u := *req.URL
+
+	m.calls = append(m.calls, Call{
+		Body:   body,
+		Header: req.Header.Clone(),
+		Method: req.Method,
+		URL:    &u,
+	})
+
+	for _, stub := range m.stubs {
+		if stub.matches(req, body) {
+			stub.calls++
+			return stub
+		}
+	}
+
+	m.unmatched = append(m.unmatched, req.Method+" "+req.URL.String())
+
+	return nil
+}
+
+/****************************************************
+ * Stubs
+ ****************************************************/
+
+/**
+ * A matcher plus a canned response. Configure stubs before the requests
+ * they answer are sent.
+ */
+type Stub struct {
+	body     []byte
+	calls    int
+	delay    time.Duration
+	err      error
+	file     string
+	fixture  *fixture
+	header   http.Header
+	matchers []func(req *http.Request, body []byte) bool
+	method   string
+	mock     *Mock
+	pattern  *regexp.Regexp
+	status   int
+	times    int
+	url      string
+}
+
+/**
+ * Requires a request header to have the given value.
+ */
+func (s *Stub) MatchHeader(name string, value string) *Stub {
+	return s.Match(func(req *http.Request, body []byte) bool {
+		return req.Header.Get(name) == value
+	})
+}
+
+/**
+ * Requires a query parameter to have the given value.
+ */
+func (s *Stub) MatchQuery(name string, value string) *Stub {
+	return s.Match(func(req *http.Request, body []byte) bool {
+		return req.URL.Query().Get(name) == value
+	})
+}
+
+/**
+ * Requires the request body to equal body.
+ */
+func (s *Stub) MatchBody(body string) *Stub {
+	return s.Match(func(req *http.Request, actual []byte) bool {
+		return string(actual) == body
+	})
+}
+
+/**
+ * Requires the request body to be JSON equivalent to body, regardless of
+ * formatting and key order.
+ */
+func (s *Stub) MatchJSON(body string) *Stub {
+
+	var expected interface{}
+
+	if err := json.Unmarshal([]byte(body), &expected); err != nil {
+		panic(err)
+	}
+
+	return s.Match(func(req *http.Request, actual []byte) bool {
+		return jsonEqual(expected, actual)
+	})
+}
+
+/**
+ * Adds a custom condition; body holds the request body.
+ */
+func (s *Stub) Match(matcher func(req *http.Request, body []byte) bool) *Stub {
+	s.matchers = append(s.matchers, matcher)
+	return s
+}
+
+/**
+ * Sets the status and body of the response.
+ */
+func (s *Stub) Reply(status int, body string) *Stub {
+	s.status = status
+	s.body = []byte(body)
+	return s
+}
+
+/**
+ * Sets the status of the response and its body to value encoded as JSON.
+ */
+func (s *Stub) ReplyJSON(status int, value interface{}) *Stub {
+
+	body, err := json.Marshal(value)
+
+	if err != nil {
+		panic(err)
+	}
+
+	s.status = status
+	s.body = body
+	s.header.Set("Content-Type", "application/json")
+
+	return s
+}
+
+/**
+ * Adds a response header.
+ */
+func (s *Stub) ReplyHeader(name string, value string) *Stub {
+	s.header.Add(name, value)
+	return s
+}
+
+/**
+ * Fails matching requests with err instead of responding, e.g. to simulate
+ * a connection failure.
+ */
+func (s *Stub) ReplyError(err error) *Stub {
+	s.err = err
+	return s
+}
+
+/**
+ * Delays the response, unless the request context is done first.
+ */
+func (s *Stub) Delay(delay time.Duration) *Stub {
+	s.delay = delay
+	return s
+}
+
+/**
+ * Answers exactly n requests; further requests fall through to the next
+ * stubs. By default a stub answers any number of requests.
+ */
+func (s *Stub) Times(n int) *Stub {
+	s.times = n
+	return s
+}
+
+/**
+ * Returns the number of requests the stub answered.
+ */
+func (s *Stub) Calls() int {
+	s.mock.mutex.Lock()
+	defer s.mock.mutex.Unlock()
+	return s.calls
+}
+
+func (s *Stub) matches(req *http.Request, body []byte) bool {
+
+	if s.times > 0 && s.calls >= s.times {
+		return false
+	}
+
+	if s.method != "" && s.method != "*" && s.method != req.Method {
+		return false
+	}
+
+	if !matchURL(s.pattern, s.url, req.URL) {
+		return false
+	}
+
+	for _, matcher := range s.matchers {
+		if !matcher(req, body) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *Stub) respond(req *http.Request) (*http.Response, error) {
+
+	if s.delay > 0 {
+		select {
+		case <-s.mock.after(s.delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	if s.fixture != nil {
+		resp, err := s.fixture.response(req)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range s.header {
+			for _, value := range values {
+				resp.Header.Add(name, value)
+			}
+		}
+		return resp, nil
+	}
+
+	body := ioutil.NopCloser(bytes.NewReader(s.body))
+	length := int64(len(s.body))
+
+	if s.file != "" {
+		file, err := os.Open(s.file)
+		if err != nil {
+			return nil, err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		body, length = file, info.Size()
+	}
+
+	return &http.Response{
+		Body:          body,
+		ContentLength: length,
+		Header:        s.header.Clone(),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Request:       req,
+		Status:        fmt.Sprintf("%d %s", s.status, http.StatusText(s.status)),
+		StatusCode:    s.status,
+	}, nil
+}
+
+func (s *Stub) methodName() string {
+	if s.method == "" {
+		return "*"
+	}
+	return s.method
+}
+
+/**
+ * Reports whether u matches pattern, compiled from raw: the path only when
+ * raw starts with "/", the scheme, host and path otherwise.
+ */
+func matchURL(pattern *regexp.Regexp, raw string, u *url.URL) bool {
+	if strings.HasPrefix(raw, "/") {
+		return pattern.MatchString(u.Path)
+	}
+	return pattern.MatchString(u.Scheme + "://" + u.Host + u.Path)
+}
+
+func jsonEqual(expected interface{}, actual []byte) bool {
+	var value interface{}
+	return json.Unmarshal(actual, &value) == nil && reflect.DeepEqual(expected, value)
+}
+
+/**
+ * Compiles a URL pattern where "*" matches any sequence of characters.
+ */
+func compilePattern(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
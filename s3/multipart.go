@@ -0,0 +1,303 @@
+package gorequest
+
+/**
+ * Multipart uploads to S3 and S3-compatible object stores (MinIO, R2...),
+ * over RequestBuilders whose client signs requests, typically with
+ * gorequest.SigV4Hooks:
+ *
+ *   client := gorequest.NewClient(gorequest.WithHooks(gorequest.SigV4Hooks(credentials)))
+ *   uploader := s3.NewUploader(func() model.RequestBuilder { return gorequest.NewRequestBuilder().WithClient(client) })
+ *   etag, err := uploader.Upload(ctx, "https://bucket.s3.eu-west-1.amazonaws.com/backups/db.tar", file, size, "application/x-tar")
+ */
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/**
+ * An error response of the object store.
+ */
+type Error struct {
+	Code       string
+	Message    string
+	StatusCode int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("S3 error %s (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+/**
+ * Uploads objects in parts sent concurrently.
+ */
+type Uploader struct {
+	newRequest model.RequestBuilderConstructor
+	// Parts sent at once; defaults to 4. Each is held in memory while it
+	// is sent.
+	Concurrency int
+	// Size of the parts but the last; defaults to 8 MiB. S3 takes parts of
+	// 5 MiB to 5 GiB, up to 10000 of them.
+	PartSize int64
+	// Retries of the part uploads; defaults to 3 attempts with backoff
+	// from 500ms.
+	Retry model.Retry
+}
+
+/**
+ * Returns an Uploader sending its requests with RequestBuilders from
+ * newRequest.
+ */
+func NewUploader(newRequest model.RequestBuilderConstructor) *Uploader {
+	return &Uploader{
+		newRequest:  newRequest,
+		Concurrency: 4,
+		PartSize:    8 << 20,
+		Retry:       model.Retry{BaseDelay: 500 * time.Millisecond, MaxAttempts: 3},
+	}
+}
+
+type part struct {
+	ETag   string `xml:"ETag"`
+	Number int    `xml:"PartNumber"`
+}
+
+/**
+ * Uploads size bytes of content to objectURL: initiates a multipart
+ * upload, sends the parts, completes the upload and returns the ETag of
+ * the object. The upload is aborted if any step fails, so no parts are
+ * left behind to be billed.
+ */
+func (u *Uploader) Upload(ctx context.Context, objectURL string, content io.ReaderAt, size int64, contentType string) (string, error) {
+
+	partSize := u.PartSize
+	if partSize <= 0 {
+		partSize = 8 << 20
+	}
+
+	count := int((size + partSize - 1) / partSize)
+	if count == 0 {
+		count = 1
+	}
+
+	if count > 10000 {
+		return "", fmt.Errorf("Upload of %d bytes needs %d parts of %d bytes, more than the 10000 allowed", size, count, partSize)
+	}
+
+	uploadID, err := u.initiate(ctx, objectURL, contentType)
+
+	if err != nil {
+		return "", err
+	}
+
+	parts, err := u.sendParts(ctx, objectURL, uploadID, content, size, partSize, count)
+
+	var etag string
+
+	if err == nil {
+		etag, err = u.complete(ctx, objectURL, uploadID, parts)
+	}
+
+	if err != nil {
+		// the upload is aborted even when ctx is what failed it
+		u.send(context.Background(), u.newRequest(), http.MethodDelete, objectURL, url.Values{"uploadId": {uploadID}}, nil)
+		return "", err
+	}
+
+	return etag, nil
+}
+
+func (u *Uploader) initiate(ctx context.Context, objectURL string, contentType string) (string, error) {
+
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+
+	builder := u.newRequest()
+	if contentType != "" {
+		builder.WithHeader("Content-Type", contentType)
+	}
+
+	if _, err := u.send(ctx, builder, http.MethodPost, objectURL, url.Values{"uploads": {""}}, &result); err != nil {
+		return "", err
+	}
+
+	if result.UploadID == "" {
+		return "", fmt.Errorf("No upload ID in the response to initiating an upload to %s", objectURL)
+	}
+
+	return result.UploadID, nil
+}
+
+func (u *Uploader) sendParts(ctx context.Context, objectURL string, uploadID string, content io.ReaderAt, size int64, partSize int64, count int) ([]part, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := u.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	parts := make([]part, count)
+	numbers := make(chan int)
+
+	var failure error
+	var once sync.Once
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range numbers {
+				etag, err := u.sendPart(ctx, objectURL, uploadID, content, size, partSize, number)
+				if err != nil {
+					once.Do(func() {
+						failure = err
+						cancel()
+					})
+					continue
+				}
+				parts[number-1] = part{ETag: etag, Number: number}
+			}
+		}()
+	}
+
+	for number := 1; number <= count && ctx.Err() == nil; number++ {
+		numbers <- number
+	}
+
+	close(numbers)
+	wg.Wait()
+
+	if failure == nil && ctx.Err() != nil {
+		failure = ctx.Err()
+	}
+
+	return parts, failure
+}
+
+func (u *Uploader) sendPart(ctx context.Context, objectURL string, uploadID string, content io.ReaderAt, size int64, partSize int64, number int) (string, error) {
+
+	offset := int64(number-1) * partSize
+	length := partSize
+	if offset+length > size {
+		length = size - offset
+	}
+
+	data := make([]byte, length)
+
+	if _, err := content.ReadAt(data, offset); err != nil && !(err == io.EOF && int64(len(data)) == length) {
+		return "", fmt.Errorf("Cannot read part %d: %w", number, err)
+	}
+
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	builder := u.newRequest().WithRetry(u.Retry).WithBody(&body{contentType: "application/octet-stream", data: data})
+
+	resp, err := u.send(ctx, builder, http.MethodPut, objectURL, query, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	etag := resp.Response().Header.Get("ETag")
+
+	if etag == "" {
+		return "", fmt.Errorf("No ETag in the response to part %d", number)
+	}
+
+	return etag, nil
+}
+
+func (u *Uploader) complete(ctx context.Context, objectURL string, uploadID string, parts []part) (string, error) {
+
+	data, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}{Parts: parts})
+
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		ETag string `xml:"ETag"`
+	}
+
+	builder := u.newRequest().WithBody(&body{contentType: "application/xml", data: data})
+
+	if _, err := u.send(ctx, builder, http.MethodPost, objectURL, url.Values{"uploadId": {uploadID}}, &result); err != nil {
+		return "", err
+	}
+
+	return result.ETag, nil
+}
+
+/**
+ * Sends a request to objectURL with query added, and decodes the XML of
+ * the response into result, if any. Error responses are returned as
+ * *Error, including those S3 sends with status 200 to complete requests.
+ */
+func (u *Uploader) send(ctx context.Context, builder model.RequestBuilder, method string, objectURL string, query url.Values, result interface{}) (model.Response, error) {
+
+	target, err := url.Parse(objectURL)
+
+	if err != nil {
+		return nil, err
+	}
+
+	target.RawQuery = query.Encode()
+
+	resp, err := builder.WithContext(ctx).WithMethod(method).WithUrl(target.String()).Build().Send()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var failure struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+
+	status := resp.Response().StatusCode
+	parsed := xml.Unmarshal(resp.Body(), &failure) == nil
+
+	if status >= http.StatusMultipleChoices || parsed && failure.XMLName.Local == "Error" {
+		return resp, &Error{Code: failure.Code, Message: failure.Message, StatusCode: status}
+	}
+
+	if result != nil {
+		if err := xml.Unmarshal(resp.Body(), result); err != nil {
+			return resp, fmt.Errorf("Cannot decode S3 response: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+/**
+ * model.RequestBody of parts and of completion requests.
+ */
+type body struct {
+	contentType string
+	data        []byte
+}
+
+func (b *body) ContentType() string {
+	return b.contentType
+}
+
+func (b *body) RawData() *bytes.Buffer {
+	return bytes.NewBuffer(b.data)
+}
@@ -0,0 +1,201 @@
+package gorequest
+
+/**
+ * SOAP 1.1 and 1.2 envelopes for requests sent with a RequestBuilder:
+ *
+ *   body, err := soap.NewBody(soap.SOAP11, "urn:GetUser", GetUser{ID: 1})
+ *   resp := soap.Prepare(gorequest.NewRequestBuilder().WithUrl(endpoint), body).Build().Do()
+ *   var user GetUserResponse
+ *   err = soap.Decode(resp, &user)
+ */
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"strings"
+)
+
+type Version int
+
+const (
+	SOAP11 Version = iota
+	SOAP12
+)
+
+const (
+	namespace11 = "http://schemas.xmlsoap.org/soap/envelope/"
+	namespace12 = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+/**
+ * A fault returned by the service, as a typed error. Code, Reason and Actor
+ * hold faultcode, faultstring and faultactor in SOAP 1.1, and Code/Value,
+ * Reason/Text and Role in SOAP 1.2. Detail is the raw XML of the detail
+ * element, if any.
+ */
+type Fault struct {
+	Actor   string
+	Code    string
+	Detail  string
+	Reason  string
+	Version Version
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("SOAP fault %s: %s", f.Code, f.Reason)
+}
+
+/**
+ * A SOAP envelope as a model.RequestBody, carrying the content type and
+ * action of its version.
+ */
+type Body struct {
+	action  string
+	data    []byte
+	version Version
+}
+
+/**
+ * Wraps payload, marshalled with encoding/xml, into the envelope of version.
+ * action is the SOAPAction of the operation, if the service uses one.
+ */
+func NewBody(version Version, action string, payload interface{}) (*Body, error) {
+
+	content, err := xml.Marshal(payload)
+
+	if err != nil {
+		return nil, fmt.Errorf("Cannot marshal SOAP payload: %w", err)
+	}
+
+	namespace := namespace11
+	if version == SOAP12 {
+		namespace = namespace12
+	}
+
+	var envelope bytes.Buffer
+
+	envelope.WriteString(xml.Header)
+	fmt.Fprintf(&envelope, `<soap:Envelope xmlns:soap="%s"><soap:Body>`, namespace)
+	envelope.Write(content)
+	envelope.WriteString(`</soap:Body></soap:Envelope>`)
+
+	return &Body{action: action, data: envelope.Bytes(), version: version}, nil
+}
+
+/**
+ * SOAP 1.2 carries the action in the content type.
+ */
+func (b *Body) ContentType() string {
+	if b.version == SOAP12 {
+		if b.action != "" {
+			return fmt.Sprintf(`application/soap+xml; charset=utf-8; action="%s"`, b.action)
+		}
+		return "application/soap+xml; charset=utf-8"
+	}
+	return "text/xml; charset=utf-8"
+}
+
+func (b *Body) RawData() *bytes.Buffer {
+	return bytes.NewBuffer(b.data)
+}
+
+/**
+ * Configures builder to POST body, with the SOAPAction header SOAP 1.1
+ * requires.
+ */
+func Prepare(builder model.RequestBuilder, body *Body) model.RequestBuilder {
+
+	builder.WithMethod("POST").WithBody(body)
+
+	if body.version == SOAP11 {
+		builder.WithHeader("SOAPAction", `"`+body.action+`"`)
+	}
+
+	return builder
+}
+
+/**
+ * Decodes the content of the body of the envelope in resp into result,
+ * which may be nil. A fault, in either version, is returned as a *Fault.
+ */
+func Decode(resp model.Response, result interface{}) error {
+
+	var envelope struct {
+		XMLName xml.Name
+		Body    struct {
+			Content []byte `xml:",innerxml"`
+			Fault   *fault `xml:"Fault"`
+		} `xml:"Body"`
+	}
+
+	if err := xml.Unmarshal(resp.Body(), &envelope); err != nil {
+		return fmt.Errorf("Cannot parse SOAP envelope: %w", err)
+	}
+
+	if envelope.XMLName.Local != "Envelope" {
+		return errors.New("Response is not a SOAP envelope")
+	}
+
+	if f := envelope.Body.Fault; f != nil {
+		return f.typed(envelope.XMLName.Space)
+	}
+
+	if result == nil || len(bytes.TrimSpace(envelope.Body.Content)) == 0 {
+		return nil
+	}
+
+	if err := xml.Unmarshal(envelope.Body.Content, result); err != nil {
+		return fmt.Errorf("Cannot decode SOAP body: %w", err)
+	}
+
+	return nil
+}
+
+/**
+ * Both versions of the fault element; only one set of fields is filled.
+ */
+type fault struct {
+	// SOAP 1.1
+	FaultActor  string `xml:"faultactor"`
+	FaultCode   string `xml:"faultcode"`
+	FaultString string `xml:"faultstring"`
+	// SOAP 1.2
+	Code struct {
+		Value string `xml:"Value"`
+	} `xml:"Code"`
+	Reason struct {
+		Text []string `xml:"Text"`
+	} `xml:"Reason"`
+	Role string `xml:"Role"`
+	// detail in SOAP 1.1, Detail in SOAP 1.2
+	Detail struct {
+		Content string `xml:",innerxml"`
+	} `xml:"detail"`
+	Detail12 struct {
+		Content string `xml:",innerxml"`
+	} `xml:"Detail"`
+}
+
+func (f *fault) typed(namespace string) *Fault {
+
+	if namespace == namespace12 {
+		return &Fault{
+			Actor:   f.Role,
+			Code:    f.Code.Value,
+			Detail:  strings.TrimSpace(f.Detail12.Content),
+			Reason:  strings.Join(f.Reason.Text, "; "),
+			Version: SOAP12,
+		}
+	}
+
+	return &Fault{
+		Actor:   f.FaultActor,
+		Code:    f.FaultCode,
+		Detail:  strings.TrimSpace(f.Detail.Content),
+		Reason:  f.FaultString,
+		Version: SOAP11,
+	}
+}
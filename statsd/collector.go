@@ -0,0 +1,104 @@
+package gorequest
+
+/**
+ * statsd adapter of model.MetricsCollector. Metrics are sent over UDP with
+ * tags in the DogStatsD format (name:value|type|#key:value), understood by
+ * the Datadog agent, Telegraf and recent statsd servers.
+ *
+ *   collector, err := statsd.NewCollector("127.0.0.1:8125", "myapp.http")
+ *   client := gorequest.NewClientBuilder().WithMetrics(collector).Build()
+ */
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+/**
+ * Sends every metric as its own datagram. Send errors are ignored, as
+ * usual with statsd.
+ */
+type Collector struct {
+	conn   net.Conn
+	prefix string
+}
+
+/**
+ * Returns a Collector sending to the statsd server at addr (host:port), or
+ * the error resolving addr. Metric names are prefixed with prefix and a
+ * dot, if not empty.
+ */
+func NewCollector(addr string, prefix string) (*Collector, error) {
+
+	conn, err := net.Dial("udp", addr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix != "" {
+		prefix += "."
+	}
+
+	return &Collector{
+		conn:   conn,
+		prefix: prefix,
+	}, nil
+}
+
+/**
+ * model.MetricsCollector implementation.
+ */
+func (c *Collector) AddCounter(name string, delta float64, tags map[string]string) {
+	c.send(name, strconv.FormatFloat(delta, 'f', -1, 64), "c", tags)
+}
+
+func (c *Collector) ObserveTimer(name string, duration time.Duration, tags map[string]string) {
+	c.send(name, strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', -1, 64), "ms", tags)
+}
+
+func (c *Collector) SetGauge(name string, value float64, tags map[string]string) {
+	c.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+/**
+ * Closes the connection to the server.
+ */
+func (c *Collector) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Collector) send(name string, value string, kind string, tags map[string]string) {
+
+	var buf bytes.Buffer
+
+	buf.WriteString(c.prefix)
+	buf.WriteString(name)
+	buf.WriteByte(':')
+	buf.WriteString(value)
+	buf.WriteByte('|')
+	buf.WriteString(kind)
+
+	if len(tags) > 0 {
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("|#")
+		for i, key := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteString(key)
+			buf.WriteByte(':')
+			buf.WriteString(tags[key])
+		}
+	}
+
+	c.conn.Write(buf.Bytes())
+}
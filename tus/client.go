@@ -0,0 +1,318 @@
+package gorequest
+
+/**
+ * Resumable uploads with the tus 1.0 protocol (https://tus.io), with the
+ * creation and checksum extensions, over RequestBuilders so uploads get the
+ * auth and other settings of a Client:
+ *
+ *   uploader := tus.NewClient(func() model.RequestBuilder { return gorequest.NewRequestBuilder().WithClient(client) })
+ *   upload, err := uploader.Create(ctx, "https://uploads.example.com/files/", size, map[string]string{"filename": "video.mp4"})
+ *   err = uploader.Upload(ctx, upload, file)
+ *
+ * and, once the connection is back after a failure:
+ *
+ *   err = uploader.Resume(ctx, upload, file)
+ */
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"hash"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const version = "1.0.0"
+
+/**
+ * Status of the tus checksum extension for chunks that do not match their
+ * Upload-Checksum.
+ */
+const StatusChecksumMismatch = 460
+
+/**
+ * Returned when the server answers a request with an unexpected status.
+ */
+type StatusError struct {
+	Method     string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Upload server answered %s with status %d", e.Method, e.StatusCode)
+}
+
+/**
+ * An upload created on the server: its URL, its size and how many of its
+ * bytes the server has, as of the last request.
+ */
+type Upload struct {
+	Length int64
+	Offset int64
+	URL    string
+}
+
+/**
+ * Creates and sends uploads.
+ */
+type Client struct {
+	newRequest model.RequestBuilderConstructor
+	// Algorithm of the Upload-Checksum sent with every chunk, "sha1",
+	// "md5" or "sha256", for servers with the checksum extension; none
+	// when empty.
+	Checksum string
+	// Size of the PATCH requests; defaults to 4 MiB. Each chunk is held in
+	// memory while it is sent.
+	ChunkSize int64
+}
+
+/**
+ * Returns a Client sending its requests with RequestBuilders from
+ * newRequest.
+ */
+func NewClient(newRequest model.RequestBuilderConstructor) *Client {
+	return &Client{
+		newRequest: newRequest,
+		ChunkSize:  4 << 20,
+	}
+}
+
+/**
+ * Creates an upload of length bytes on the server at endpoint (creation
+ * extension), with metadata sent in Upload-Metadata.
+ */
+func (c *Client) Create(ctx context.Context, endpoint string, length int64, metadata map[string]string) (*Upload, error) {
+
+	builder := c.request(ctx, http.MethodPost, endpoint).
+		WithHeader("Upload-Length", strconv.FormatInt(length, 10))
+
+	if len(metadata) > 0 {
+		builder.WithHeader("Upload-Metadata", encodeMetadata(metadata))
+	}
+
+	resp, err := builder.Build().Send()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Response().StatusCode != http.StatusCreated {
+		return nil, &StatusError{Method: http.MethodPost, StatusCode: resp.Response().StatusCode}
+	}
+
+	location, err := resp.Response().Request.URL.Parse(resp.Response().Header.Get("Location"))
+
+	if err != nil || resp.Response().Header.Get("Location") == "" {
+		return nil, errors.New("Upload server answered POST without a valid Location")
+	}
+
+	return &Upload{Length: length, URL: location.String()}, nil
+}
+
+/**
+ * Asks the server how many bytes of upload it has, and updates
+ * upload.Offset.
+ */
+func (c *Client) Sync(ctx context.Context, upload *Upload) error {
+
+	resp, err := c.request(ctx, http.MethodHead, upload.URL).Build().Send()
+
+	if err != nil {
+		return err
+	}
+
+	if status := resp.Response().StatusCode; status != http.StatusOK && status != http.StatusNoContent {
+		return &StatusError{Method: http.MethodHead, StatusCode: status}
+	}
+
+	offset, err := strconv.ParseInt(resp.Response().Header.Get("Upload-Offset"), 10, 64)
+
+	if err != nil || offset < 0 {
+		return errors.New("Upload server answered HEAD without a valid Upload-Offset")
+	}
+
+	upload.Offset = offset
+
+	return nil
+}
+
+/**
+ * Sends the bytes of content from upload.Offset on, chunk by chunk. A
+ * failure leaves upload.Offset at the bytes the server acknowledged; see
+ * Resume. Chunks the server reports as corrupted (checksum mismatch) are
+ * sent again once; offset conflicts are settled by asking the server for
+ * its offset.
+ */
+func (c *Client) Upload(ctx context.Context, upload *Upload, content io.ReadSeeker) error {
+
+	if c.Checksum != "" && newHash(c.Checksum) == nil {
+		return fmt.Errorf("Unsupported checksum algorithm: %s", c.Checksum)
+	}
+
+	chunkSize := c.ChunkSize
+
+	if chunkSize <= 0 {
+		chunkSize = 4 << 20
+	}
+
+	retried := false
+
+	for upload.Offset < upload.Length {
+
+		if _, err := content.Seek(upload.Offset, io.SeekStart); err != nil {
+			return err
+		}
+
+		if rest := upload.Length - upload.Offset; rest < chunkSize {
+			chunkSize = rest
+		}
+
+		chunk := make([]byte, chunkSize)
+
+		if _, err := io.ReadFull(content, chunk); err != nil {
+			return fmt.Errorf("Cannot read upload content at offset %d: %w", upload.Offset, err)
+		}
+
+		status, err := c.patch(ctx, upload, chunk)
+
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case status == http.StatusNoContent:
+			retried = false
+			continue
+		case (status == http.StatusConflict || status == StatusChecksumMismatch) && !retried:
+			retried = true
+			if status == http.StatusConflict {
+				if err := c.Sync(ctx, upload); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		return &StatusError{Method: http.MethodPatch, StatusCode: status}
+	}
+
+	return nil
+}
+
+/**
+ * Asks the server for the offset of upload, then sends the rest of
+ * content.
+ */
+func (c *Client) Resume(ctx context.Context, upload *Upload, content io.ReadSeeker) error {
+
+	if err := c.Sync(ctx, upload); err != nil {
+		return err
+	}
+
+	return c.Upload(ctx, upload, content)
+}
+
+/**
+ * Sends chunk at upload.Offset, moving the offset on to the one the server
+ * acknowledges.
+ */
+func (c *Client) patch(ctx context.Context, upload *Upload, chunk []byte) (int, error) {
+
+	builder := c.request(ctx, http.MethodPatch, upload.URL).
+		WithHeader("Upload-Offset", strconv.FormatInt(upload.Offset, 10)).
+		WithBody(&body{data: chunk})
+
+	if c.Checksum != "" {
+		digest := newHash(c.Checksum)
+		digest.Write(chunk)
+		builder.WithHeader("Upload-Checksum", c.Checksum+" "+base64.StdEncoding.EncodeToString(digest.Sum(nil)))
+	}
+
+	resp, err := builder.Build().Send()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.Response().StatusCode != http.StatusNoContent {
+		return resp.Response().StatusCode, nil
+	}
+
+	offset, err := strconv.ParseInt(resp.Response().Header.Get("Upload-Offset"), 10, 64)
+
+	if err != nil || offset <= upload.Offset {
+		return 0, errors.New("Upload server answered PATCH without a valid Upload-Offset")
+	}
+
+	upload.Offset = offset
+
+	return http.StatusNoContent, nil
+}
+
+func (c *Client) request(ctx context.Context, method string, target string) model.RequestBuilder {
+	return c.newRequest().
+		WithContext(ctx).
+		WithMethod(method).
+		WithUrl(target).
+		WithHeader("Tus-Resumable", version)
+}
+
+/**
+ * Encodes metadata as in Upload-Metadata: comma-separated keys, sorted,
+ * each followed by its base64-encoded value.
+ */
+func encodeMetadata(metadata map[string]string) string {
+
+	keys := make([]string, 0, len(metadata))
+
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+
+	for i, key := range keys {
+		pairs[i] = key + " " + base64.StdEncoding.EncodeToString([]byte(metadata[key]))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "md5":
+		return md5.New()
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	}
+	return nil
+}
+
+/**
+ * model.RequestBody of chunks.
+ */
+type body struct {
+	data []byte
+}
+
+func (b *body) ContentType() string {
+	return "application/offset+octet-stream"
+}
+
+func (b *body) RawData() *bytes.Buffer {
+	return bytes.NewBuffer(b.data)
+}
@@ -0,0 +1,159 @@
+package gorequest
+
+/**
+ * WebDAV (RFC 4918) requests for RequestBuilders, e.g. to drive Nextcloud
+ * storage:
+ *
+ *   resp := webdav.Propfind(gorequest.NewRequestBuilder().WithUrl(folder), webdav.DepthOne).Build().Do()
+ *   resources, err := webdav.ParseMultistatus(resp)
+ */
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"strconv"
+	"time"
+)
+
+/**
+ * Value of the Depth header: how far below the target an operation applies.
+ */
+type Depth string
+
+const (
+	DepthZero     Depth = "0"
+	DepthOne      Depth = "1"
+	DepthInfinity Depth = "infinity"
+)
+
+/**
+ * Configures builder to list the properties of its target (and of its
+ * members, with DepthOne). Without props every property is requested
+ * (allprop).
+ */
+func Propfind(builder model.RequestBuilder, depth Depth, props ...xml.Name) model.RequestBuilder {
+
+	var body bytes.Buffer
+
+	body.WriteString(xml.Header)
+	body.WriteString(`<d:propfind xmlns:d="DAV:">`)
+
+	if len(props) == 0 {
+		body.WriteString(`<d:allprop/>`)
+	} else {
+		body.WriteString(`<d:prop>`)
+		for _, prop := range props {
+			fmt.Fprintf(&body, `<%s xmlns="%s"/>`, prop.Local, prop.Space)
+		}
+		body.WriteString(`</d:prop>`)
+	}
+
+	body.WriteString(`</d:propfind>`)
+
+	return builder.
+		WithMethod("PROPFIND").
+		WithHeader("Depth", string(depth)).
+		WithBody(&xmlBody{data: body.Bytes()})
+}
+
+/**
+ * Configures builder to create its target collection.
+ */
+func Mkcol(builder model.RequestBuilder) model.RequestBuilder {
+	return builder.WithMethod("MKCOL")
+}
+
+/**
+ * Configures builder to copy its target to destination, an absolute URL.
+ * Collections are copied with their members unless depth is DepthZero. An
+ * existing destination is replaced only if overwrite is set.
+ */
+func Copy(builder model.RequestBuilder, destination string, depth Depth, overwrite bool) model.RequestBuilder {
+	return builder.
+		WithMethod("COPY").
+		WithHeader("Depth", string(depth)).
+		WithHeader("Destination", destination).
+		WithHeader("Overwrite", overwriteHeader(overwrite))
+}
+
+/**
+ * Configures builder to move its target to destination, an absolute URL.
+ */
+func Move(builder model.RequestBuilder, destination string, overwrite bool) model.RequestBuilder {
+	return builder.
+		WithMethod("MOVE").
+		WithHeader("Destination", destination).
+		WithHeader("Overwrite", overwriteHeader(overwrite))
+}
+
+/**
+ * Configures builder to take an exclusive write lock on its target for
+ * timeout (infinite if zero). Read the token of the lock with LockToken.
+ */
+func Lock(builder model.RequestBuilder, owner string, depth Depth, timeout time.Duration) model.RequestBuilder {
+
+	var owned bytes.Buffer
+	xml.EscapeText(&owned, []byte(owner))
+
+	body := xml.Header +
+		`<d:lockinfo xmlns:d="DAV:"><d:lockscope><d:exclusive/></d:lockscope><d:locktype><d:write/></d:locktype>` +
+		`<d:owner>` + owned.String() + `</d:owner></d:lockinfo>`
+
+	lifetime := "Infinite"
+	if timeout > 0 {
+		lifetime = "Second-" + strconv.Itoa(int(timeout/time.Second))
+	}
+
+	return builder.
+		WithMethod("LOCK").
+		WithHeader("Depth", string(depth)).
+		WithHeader("Timeout", lifetime).
+		WithBody(&xmlBody{data: []byte(body)})
+}
+
+/**
+ * Configures builder to release the lock identified by token.
+ */
+func Unlock(builder model.RequestBuilder, token string) model.RequestBuilder {
+	return builder.
+		WithMethod("UNLOCK").
+		WithHeader("Lock-Token", "<"+token+">")
+}
+
+/**
+ * Returns the token of the lock granted by a LOCK request.
+ */
+func LockToken(resp model.Response) (string, error) {
+
+	token := resp.Response().Header.Get("Lock-Token")
+
+	if len(token) < 2 || token[0] != '<' || token[len(token)-1] != '>' {
+		return "", fmt.Errorf("No lock granted (status %d)", resp.Response().StatusCode)
+	}
+
+	return token[1 : len(token)-1], nil
+}
+
+func overwriteHeader(overwrite bool) string {
+	if overwrite {
+		return "T"
+	}
+	return "F"
+}
+
+/**
+ * model.RequestBody of WebDAV requests.
+ */
+type xmlBody struct {
+	data []byte
+}
+
+func (b *xmlBody) ContentType() string {
+	return "application/xml; charset=utf-8"
+}
+
+func (b *xmlBody) RawData() *bytes.Buffer {
+	return bytes.NewBuffer(b.data)
+}
@@ -0,0 +1,137 @@
+package gorequest
+
+import (
+	"encoding/xml"
+	"fmt"
+	model "github.com/demianlessa/gorequest/model"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+ * One resource of a multistatus response. The common DAV: properties are
+ * decoded; Properties holds the text of every property found, DAV: ones
+ * included. Status is the status of the properties (or of the resource,
+ * for responses without properties).
+ */
+type Resource struct {
+	Collection    bool
+	ContentLength int64
+	ContentType   string
+	DisplayName   string
+	ETag          string
+	Href          string
+	LastModified  time.Time
+	Properties    map[xml.Name]string
+	Status        int
+}
+
+/**
+ * Parses a 207 Multi-Status response, e.g. to a PROPFIND request.
+ * Properties reported with a non-2xx status (typically 404 for unknown
+ * properties) are left out.
+ */
+func ParseMultistatus(resp model.Response) ([]Resource, error) {
+
+	if resp.Response().StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("Expected a multistatus response, got status %d", resp.Response().StatusCode)
+	}
+
+	var multistatus struct {
+		Responses []struct {
+			Href      string `xml:"DAV: href"`
+			Propstats []struct {
+				Props struct {
+					Values []property `xml:",any"`
+				} `xml:"DAV: prop"`
+				Status string `xml:"DAV: status"`
+			} `xml:"DAV: propstat"`
+			Status string `xml:"DAV: status"`
+		} `xml:"DAV: response"`
+	}
+
+	if err := xml.Unmarshal(resp.Body(), &multistatus); err != nil {
+		return nil, fmt.Errorf("Cannot parse multistatus response: %w", err)
+	}
+
+	resources := make([]Resource, 0, len(multistatus.Responses))
+
+	for _, entry := range multistatus.Responses {
+
+		resource := Resource{
+			Href:       entry.Href,
+			Properties: map[xml.Name]string{},
+			Status:     parseStatus(entry.Status),
+		}
+
+		for _, propstat := range entry.Propstats {
+
+			status := parseStatus(propstat.Status)
+
+			if status < 200 || status > 299 {
+				continue
+			}
+
+			resource.Status = status
+
+			for _, value := range propstat.Props.Values {
+				resource.set(value)
+			}
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+type property struct {
+	XMLName  xml.Name
+	Children []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+	Text string `xml:",chardata"`
+}
+
+func (r *Resource) set(value property) {
+
+	text := strings.TrimSpace(value.Text)
+	r.Properties[value.XMLName] = text
+
+	if value.XMLName.Space != "DAV:" {
+		return
+	}
+
+	switch value.XMLName.Local {
+	case "displayname":
+		r.DisplayName = text
+	case "getcontentlength":
+		r.ContentLength, _ = strconv.ParseInt(text, 10, 64)
+	case "getcontenttype":
+		r.ContentType = text
+	case "getetag":
+		r.ETag = text
+	case "getlastmodified":
+		r.LastModified, _ = http.ParseTime(text)
+	case "resourcetype":
+		for _, child := range value.Children {
+			if child.XMLName.Space == "DAV:" && child.XMLName.Local == "collection" {
+				r.Collection = true
+			}
+		}
+	}
+}
+
+/**
+ * Reads the code of a status line such as "HTTP/1.1 200 OK"; zero if absent.
+ */
+func parseStatus(line string) int {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return 0
+	}
+	code, _ := strconv.Atoi(fields[1])
+	return code
+}